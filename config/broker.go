@@ -0,0 +1,26 @@
+package config
+
+import (
+	"context"
+	"log"
+	"online-food/utils/broker"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func Broker(rdb *redis.Client) broker.Broker {
+	switch os.Getenv("EVENTS_DRIVER") {
+	case "redis":
+		channel := os.Getenv("EVENTS_CHANNEL")
+		if channel == "" {
+			channel = "online-food:orders"
+		}
+		return broker.NewRedisBroker(context.Background(), rdb, channel)
+	case "", "memory":
+		return broker.NewMemoryBroker()
+	default:
+		log.Fatalf("broker: unknown driver %q", os.Getenv("EVENTS_DRIVER"))
+		return nil
+	}
+}
@@ -25,16 +25,113 @@ func Database() *gorm.DB {
 		log.Fatalf("database: %v", err)
 	}
 
-	err = db.AutoMigrate(
+	return db
+}
+
+func Migrate(db *gorm.DB) error {
+	backfillVerified := !db.Migrator().HasColumn(&entity.User{}, "EmailVerifiedAt")
+	backfillCartSubtotal := db.Migrator().HasTable(&entity.Cart{}) && !db.Migrator().HasColumn(&entity.Cart{}, "Subtotal")
+	backfillItems := db.Migrator().HasTable(&entity.Order{}) && !db.Migrator().HasTable(&entity.OrderItem{})
+	backfillSubtotal := db.Migrator().HasTable(&entity.Order{}) && !db.Migrator().HasColumn(&entity.Order{}, "Subtotal")
+	backfillReleased := db.Migrator().HasTable(&entity.Order{}) && !db.Migrator().HasColumn(&entity.Order{}, "ReleasedAt")
+	backfillDeliveries := db.Migrator().HasTable(&entity.Order{}) && !db.Migrator().HasTable(&entity.Delivery{})
+	backfillDelivery := db.Migrator().HasTable(&entity.Order{}) && !db.Migrator().HasColumn(&entity.Order{}, "delivery_address")
+
+	err := db.AutoMigrate(
 		&entity.User{},
 		&entity.Menu{},
 		&entity.Cart{},
 		&entity.CartMenu{},
 		&entity.Order{},
+		&entity.PasswordReset{},
+		&entity.RecoveryCode{},
+		&entity.Setting{},
+		&entity.Permission{},
+		&entity.Role{},
+		&entity.Address{},
+		&entity.DeliveryZone{},
+		&entity.Voucher{},
+		&entity.VoucherRedemption{},
+		&entity.Promotion{},
+		&entity.CartDiscount{},
+		&entity.OrderTax{},
+		&entity.OrderDiscount{},
+		&entity.OrderItem{},
+		&entity.InvoiceSequence{},
+		&entity.Delivery{},
+		&entity.CourierLocation{},
+		&entity.DeliveryOffer{},
+		&entity.ScheduleSlot{},
+		&entity.Favorite{},
+		&entity.SavedList{},
+		&entity.SavedListItem{},
+		&entity.MenuPair{},
+		&entity.UserRecommendation{},
+		&entity.MenuPopularity{},
 	)
 	if err != nil {
-		log.Fatal("AutoMigrate failed:", err)
+		return fmt.Errorf("auto migrate: %w", err)
 	}
 
-	return db
+	//accounts created before email verification existed are treated as verified
+	if backfillVerified {
+		if err := db.Model(&entity.User{}).Where("email_verified_at IS NULL").
+			UpdateColumn("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+			return fmt.Errorf("backfill email verification: %w", err)
+		}
+	}
+
+	//orders placed before saved addresses existed were delivered to the profile address
+	if backfillDelivery {
+		if err := db.Exec("UPDATE orders JOIN users ON users.id = orders.user_id SET orders.delivery_address = users.address").Error; err != nil {
+			return fmt.Errorf("backfill order delivery address: %w", err)
+		}
+	}
+
+	//older orders had no delivery fee, the amount paid was the subtotal
+	if backfillSubtotal {
+		if err := db.Exec("UPDATE orders SET subtotal = amount_pay").Error; err != nil {
+			return fmt.Errorf("backfill order subtotal: %w", err)
+		}
+	}
+
+	//orders placed before items were copied still point at the cart and the live menu
+	if backfillItems {
+		if err := db.Exec("INSERT INTO order_items (order_id, menu_id, name, category, unit_price, qty) " +
+			"SELECT o.id, cm.menu_id, m.name, m.category, cm.unit_price, cm.qty FROM orders o " +
+			"JOIN cart_menus cm ON cm.cart_id = o.cart_id AND cm.deleted_at IS NULL " +
+			"JOIN menus m ON m.id = cm.menu_id ORDER BY o.id, cm.id").Error; err != nil {
+			return fmt.Errorf("backfill order items: %w", err)
+		}
+	}
+
+	//carts priced before promotions existed had no discount
+	if backfillCartSubtotal {
+		if err := db.Exec("UPDATE carts SET subtotal = COALESCE(amount, 0)").Error; err != nil {
+			return fmt.Errorf("backfill cart subtotal: %w", err)
+		}
+	}
+
+	//orders placed before scheduling went to the kitchen when they were placed
+	if backfillReleased {
+		if err := db.Exec("UPDATE orders SET released_at = order_date").Error; err != nil {
+			return fmt.Errorf("backfill order release: %w", err)
+		}
+	}
+
+	//orders that already had a courier get a delivery record matching their status
+	if backfillDeliveries {
+		if err := db.Exec("INSERT INTO deliveries (order_id, courier_id, status, assigned_at, picked_up_at, delivered_at, failed_at, created_at, updated_at) " +
+			"SELECT id, courier_id, CASE status WHEN 'delivering' THEN 'picked_up' WHEN 'delivered' THEN 'delivered' WHEN 'cancelled' THEN 'failed' ELSE 'assigned' END, " +
+			"updated_at, IF(status IN ('delivering', 'delivered'), updated_at, NULL), IF(status = 'delivered', updated_at, NULL), " +
+			"IF(status = 'cancelled', updated_at, NULL), NOW(), NOW() FROM orders WHERE courier_id IS NOT NULL AND deleted_at IS NULL ORDER BY id").Error; err != nil {
+			return fmt.Errorf("backfill deliveries: %w", err)
+		}
+	}
+
+	if err := seedRoles(db); err != nil {
+		return fmt.Errorf("seed roles: %w", err)
+	}
+
+	return nil
 }
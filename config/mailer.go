@@ -0,0 +1,31 @@
+package config
+
+import (
+	"log"
+	"online-food/utils/mailer"
+	"os"
+)
+
+func Mailer() mailer.Mailer {
+	switch os.Getenv("MAIL_DRIVER") {
+	case "smtp":
+		return mailer.NewSmtpMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PWD"),
+			os.Getenv("MAIL_FROM"),
+		)
+	case "file":
+		path := os.Getenv("MAIL_FILE_PATH")
+		if path == "" {
+			path = "mail.log"
+		}
+		return mailer.NewFileMailer(path)
+	case "", "memory":
+		return mailer.NewMemoryMailer()
+	default:
+		log.Fatalf("mailer: unknown driver %q", os.Getenv("MAIL_DRIVER"))
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"online-food/entity"
+	"online-food/utils/constanta"
+
+	"gorm.io/gorm"
+)
+
+func seedRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var existing []entity.Permission
+		if err := tx.Find(&existing).Error; err != nil {
+			return fmt.Errorf("load permissions: %w", err)
+		}
+
+		byName := make(map[string]entity.Permission, len(constanta.Permissions))
+		for _, v := range existing {
+			byName[v.Name] = v
+		}
+
+		//permissions added to the catalog since the last start
+		added := map[string]bool{}
+		for name, description := range constanta.Permissions {
+			permission, ok := byName[name]
+			if ok {
+				if permission.Description != description {
+					if err := tx.Model(&permission).Update("description", description).Error; err != nil {
+						return fmt.Errorf("update permission %s: %w", name, err)
+					}
+				}
+				continue
+			}
+
+			permission = entity.Permission{Name: name, Description: description}
+			if err := tx.Create(&permission).Error; err != nil {
+				return fmt.Errorf("seed permission %s: %w", name, err)
+			}
+
+			byName[name] = permission
+			added[name] = true
+		}
+
+		all := make([]entity.Permission, 0, len(byName))
+		for _, v := range byName {
+			all = append(all, v)
+		}
+
+		for name, defaults := range constanta.DefaultRoles {
+			role := entity.Role{}
+			result := tx.Where("name = ?", name).Limit(1).Find(&role)
+			if result.Error != nil {
+				return fmt.Errorf("find role %s: %w", name, result.Error)
+			}
+
+			if result.RowsAffected == 0 {
+				role = entity.Role{Name: name, System: true}
+				if err := tx.Create(&role).Error; err != nil {
+					return fmt.Errorf("create role %s: %w", name, err)
+				}
+			}
+
+			//admin follows the catalog, other roles only get defaults that are new so admin edits are kept
+			if name == constanta.Admin {
+				if err := tx.Model(&role).Association("Permissions").Replace(all); err != nil {
+					return fmt.Errorf("assign permissions %s: %w", name, err)
+				}
+				continue
+			}
+
+			grant := []entity.Permission{}
+			for _, v := range defaults {
+				if result.RowsAffected == 0 || added[v] {
+					grant = append(grant, byName[v])
+				}
+			}
+
+			if len(grant) == 0 {
+				continue
+			}
+
+			if err := tx.Model(&role).Association("Permissions").Append(grant); err != nil {
+				return fmt.Errorf("assign permissions %s: %w", name, err)
+			}
+		}
+
+		return nil
+	})
+}
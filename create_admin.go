@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"online-food/config"
+	"online-food/dto"
+	"online-food/repository"
+	"online-food/service"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func createAdmin(args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	name := flags.String("name", "Administrator", "display name")
+	email := flags.String("email", "", "login email (required)")
+	password := flags.String("password", "", "login password, falls back to ADMIN_PASSWORD")
+	hp := flags.String("hp", "0", "phone number")
+	address := flags.String("address", "-", "address")
+	promote := flags.Bool("promote", false, "promote the account to admin when the email already exists")
+	flags.Parse(args)
+
+	if *email == "" {
+		flags.Usage()
+		return errors.New("email is required")
+	}
+
+	//flags end up in shell history, the env var keeps the password out of it
+	if *password == "" {
+		*password = os.Getenv("ADMIN_PASSWORD")
+	}
+
+	database := config.Database()
+	if err := config.Migrate(database); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	userRepo := repository.NewUserRepositoryImpl(database)
+	userService := service.NewUserServiceImpl(
+		userRepo,
+		repository.NewPasswordResetRepositoryImpl(database),
+		repository.NewSettingRepositoryImpl(database),
+		repository.NewRoleRepositoryImpl(database),
+		repository.NewLoginAttemptRepositoryImpl(config.RedisCLient()),
+		config.Mailer(),
+		validator.New(),
+	)
+
+	result, err := userService.CreateAdmin(ctx, &dto.UserCreateReq{
+		Name:     *name,
+		Email:    *email,
+		Password: *password,
+		Hp:       *hp,
+		Address:  *address,
+	})
+	if err == nil {
+		log.Printf("admin %s created with id %d", result.Email, result.ID)
+		return nil
+	}
+
+	if errors.Is(err, handling.ErrorValidation) {
+		return errors.New("invalid input, a valid email and a password of at least 8 characters are required")
+	}
+
+	if !errors.Is(err, handling.ErrorEmailExist) {
+		return err
+	}
+
+	if !*promote {
+		return fmt.Errorf("%s already exists, pass -promote to make it an admin", *email)
+	}
+
+	user, err := userRepo.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(*email)))
+	if err != nil {
+		return err
+	}
+
+	if _, err := userRepo.UpdateRole(ctx, user.ID, constanta.Admin); err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		if err := userRepo.VerifyEmail(ctx, user.ID, user.Email, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("%s promoted to admin", user.Email)
+	return nil
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"online-food/entity"
+	"time"
+)
+
+type AddressCreateReq struct {
+	Label     string   `validate:"required,min=1,max=50" json:"label"`
+	Address   string   `validate:"required,min=1,max=255" json:"address"`
+	Latitude  *float64 `validate:"required,latitude" json:"latitude"`
+	Longitude *float64 `validate:"required,longitude" json:"longitude"`
+	Notes     string   `validate:"max=255" json:"notes"`
+	IsDefault bool     `json:"is_default"`
+}
+
+type AddressUpdateReq struct {
+	ID        uint     `validate:"required"`
+	Label     *string  `validate:"omitempty,min=1,max=50" json:"label,omitempty"`
+	Address   *string  `validate:"omitempty,min=1,max=255" json:"address,omitempty"`
+	Latitude  *float64 `validate:"omitempty,latitude" json:"latitude,omitempty"`
+	Longitude *float64 `validate:"omitempty,longitude" json:"longitude,omitempty"`
+	Notes     *string  `validate:"omitempty,max=255" json:"notes,omitempty"`
+	IsDefault *bool    `json:"is_default,omitempty"`
+}
+
+type AddressResponse struct {
+	ID        uint      `json:"id"`
+	Label     string    `json:"label"`
+	Address   string    `json:"address"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Notes     string    `json:"notes"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type DeliveryDetails struct {
+	AddressID  *uint    `json:"address_id,omitempty"`
+	Label      string   `json:"label,omitempty"`
+	Address    string   `json:"address"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+	Notes      string   `json:"notes,omitempty"`
+	ZoneID     *uint    `json:"zone_id,omitempty"`
+	DistanceKm float64  `json:"distance_km"`
+}
+
+func ToAddressResponse(address *entity.Address) *AddressResponse {
+	return &AddressResponse{
+		ID:        address.ID,
+		Label:     address.Label,
+		Address:   address.Address,
+		Latitude:  address.Latitude,
+		Longitude: address.Longitude,
+		Notes:     address.Notes,
+		IsDefault: address.IsDefault,
+		CreatedAt: address.CreatedAt,
+		UpdatedAt: address.UpdatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"online-food/entity"
+	"online-food/utils/billing"
+)
+
+type TaxRateReq struct {
+	Category  string   `validate:"required,oneof=makanan minuman" json:"category"`
+	Rate      *float64 `validate:"required,gte=0,lte=100" json:"rate"`
+	Inclusive bool     `json:"inclusive"`
+}
+
+type BillingConfigReq struct {
+	ServiceCharge *float64     `validate:"required,gte=0,lte=100" json:"service_charge"`
+	Taxes         []TaxRateReq `validate:"omitempty,dive" json:"taxes"`
+}
+
+type TaxRateDetails struct {
+	Category  string  `json:"category"`
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+}
+
+type BillingConfigResponse struct {
+	ServiceCharge float64          `json:"service_charge"`
+	Taxes         []TaxRateDetails `json:"taxes"`
+}
+
+type TaxDetails struct {
+	Category  string  `json:"category"`
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	Base      float64 `json:"base"`
+	Amount    float64 `json:"amount"`
+}
+
+type BillDetails struct {
+	Subtotal        float64      `json:"subtotal"`
+	PromoDiscount   float64      `json:"promo_discount"`
+	VoucherDiscount float64      `json:"voucher_discount"`
+	ServiceRate     float64      `json:"service_rate"`
+	ServiceCharge   float64      `json:"service_charge"`
+	Tax             float64      `json:"tax"`
+	TaxIncluded     float64      `json:"tax_included"`
+	Taxes           []TaxDetails `json:"taxes"`
+	DeliveryFee     float64      `json:"delivery_fee"`
+	Total           float64      `json:"total"`
+}
+
+// ToCartBill prices a cart with the current rates, delivery and voucher are only known at checkout.
+func ToCartBill(cart *entity.Cart, config billing.Config) BillDetails {
+	bill := billing.Compute(billing.CartLines(cart), cart.Subtotal-cart.Amount, 0, config)
+
+	taxes := make([]TaxDetails, 0, len(bill.Taxes))
+	for _, v := range bill.Taxes {
+		taxes = append(taxes, TaxDetails{
+			Category:  v.Category,
+			Rate:      v.Rate,
+			Inclusive: v.Inclusive,
+			Base:      v.Base,
+			Amount:    v.Amount,
+		})
+	}
+
+	return BillDetails{
+		Subtotal:      bill.Subtotal,
+		PromoDiscount: bill.Discount,
+		ServiceRate:   config.ServiceCharge,
+		ServiceCharge: bill.ServiceCharge,
+		Tax:           bill.Tax,
+		TaxIncluded:   bill.TaxIncluded,
+		Taxes:         taxes,
+		Total:         bill.Total,
+	}
+}
+
+// ToOrderBill reads the breakdown stored on the order, rates changed after checkout don't touch it.
+func ToOrderBill(order *entity.Order) BillDetails {
+	taxes := make([]TaxDetails, 0, len(order.Taxes))
+	for _, v := range order.Taxes {
+		taxes = append(taxes, TaxDetails{
+			Category:  v.Category,
+			Rate:      v.Rate,
+			Inclusive: v.Inclusive,
+			Base:      v.Base,
+			Amount:    v.Amount,
+		})
+	}
+
+	return BillDetails{
+		Subtotal:        order.Subtotal,
+		PromoDiscount:   order.PromoDiscount,
+		VoucherDiscount: order.Discount,
+		ServiceRate:     order.ServiceRate,
+		ServiceCharge:   order.ServiceCharge,
+		Tax:             order.Tax,
+		TaxIncluded:     order.TaxIncluded,
+		Taxes:           taxes,
+		DeliveryFee:     order.DeliveryFee,
+		Total:           order.AmountPay,
+	}
+}
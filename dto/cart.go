@@ -2,6 +2,7 @@ package dto
 
 import (
 	"online-food/entity"
+	"online-food/utils/billing"
 	"time"
 )
 
@@ -35,17 +36,50 @@ type UserDetails struct {
 	Address string `json:"address"`
 }
 
+type DiscountDetails struct {
+	PromotionID uint    `json:"promotion_id"`
+	Name        string  `json:"name"`
+	Amount      float64 `json:"amount"`
+}
+
 type CartResponse struct {
-	CartID    uint          `json:"cart_id"`
-	User      UserDetails   `json:"user"`
-	Amount    float64       `json:"amount"`
-	Status    string        `json:"status"`
-	Menus     []MenuDetails `json:"menus"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
+	CartID    uint              `json:"cart_id"`
+	User      UserDetails       `json:"user"`
+	Subtotal  float64           `json:"subtotal"`
+	Discounts []DiscountDetails `json:"discounts"`
+	Amount    float64           `json:"amount"`
+	Bill      BillDetails       `json:"bill"`
+	Status    string            `json:"status"`
+	Menus     []MenuDetails     `json:"menus"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func toDiscountDetails(discounts []entity.CartDiscount) []DiscountDetails {
+	result := make([]DiscountDetails, 0, len(discounts))
+	for _, v := range discounts {
+		result = append(result, DiscountDetails{
+			PromotionID: v.PromotionID,
+			Name:        v.Name,
+			Amount:      v.Amount,
+		})
+	}
+	return result
 }
 
-func ToCartResponse(cart *entity.Cart) *CartResponse {
+func toOrderDiscountDetails(discounts []entity.OrderDiscount) []DiscountDetails {
+	result := make([]DiscountDetails, 0, len(discounts))
+	for _, v := range discounts {
+		result = append(result, DiscountDetails{
+			PromotionID: v.PromotionID,
+			Name:        v.Name,
+			Amount:      v.Amount,
+		})
+	}
+	return result
+}
+
+func ToCartResponse(cart *entity.Cart, config billing.Config) *CartResponse {
 	menus := make([]MenuDetails, 0, len(cart.CartMenu))
 	for _, v := range cart.CartMenu {
 		menus = append(menus, MenuDetails{
@@ -62,7 +96,10 @@ func ToCartResponse(cart *entity.Cart) *CartResponse {
 			Hp:      cart.User.Hp,
 			Address: cart.User.Address,
 		},
+		Subtotal:  cart.Subtotal,
+		Discounts: toDiscountDetails(cart.Discounts),
 		Amount:    cart.Amount,
+		Bill:      ToCartBill(cart, config),
 		Status:    cart.Status,
 		Menus:     menus,
 		CreatedAt: cart.CreatedAt,
@@ -70,21 +107,39 @@ func ToCartResponse(cart *entity.Cart) *CartResponse {
 	}
 }
 
+type CheckoutReq struct {
+	PaymentMethod string     `validate:"omitempty,oneof=cash transfer" json:"payment_method"`
+	AddressID     *uint      `validate:"omitempty,gt=0" json:"address_id"`
+	VoucherCode   string     `validate:"omitempty,max=50" json:"voucher_code"`
+	ScheduledFor  *time.Time `json:"scheduled_for"`
+}
+
 type OrderResponse struct {
-	OrderID   uint          `json:"order_id"`
-	OrderDate time.Time     `json:"order_date"`
-	User      UserDetails   `json:"user"`
-	AmountPay float64       `json:"amount_pay"`
-	Menus     []MenuDetails `json:"menus"`
-	Status    string        `json:"status"`
+	OrderID       uint              `json:"order_id"`
+	OrderDate     time.Time         `json:"order_date"`
+	User          UserDetails       `json:"user"`
+	Subtotal      float64           `json:"subtotal"`
+	Promotions    []DiscountDetails `json:"promotions"`
+	PromoDiscount float64           `json:"promo_discount"`
+	Discount      float64           `json:"discount"`
+	VoucherCode   string            `json:"voucher_code,omitempty"`
+	DeliveryFee   float64           `json:"delivery_fee"`
+	AmountPay     float64           `json:"amount_pay"`
+	Bill          BillDetails       `json:"bill"`
+	PaymentMethod string            `json:"payment_method"`
+	Menus         []MenuDetails     `json:"menus"`
+	Status        string            `json:"status"`
+	CourierID     *uint             `json:"courier_id,omitempty"`
+	Delivery      DeliveryDetails   `json:"delivery"`
+	ScheduledFor  *time.Time        `json:"scheduled_for,omitempty"`
 }
 
 func ToOrderResponse(order *entity.Order) *OrderResponse {
-	menus := make([]MenuDetails, 0, len(order.Cart.CartMenu))
-	for _, v := range order.Cart.CartMenu {
+	menus := make([]MenuDetails, 0, len(order.Items))
+	for _, v := range order.Items {
 		menus = append(menus, MenuDetails{
-			MenuID:    v.Menu.ID,
-			Name:      v.Menu.Name,
+			MenuID:    v.MenuID,
+			Name:      v.Name,
 			Qty:       v.Qty,
 			UnitPrice: v.UnitPrice,
 		})
@@ -95,10 +150,56 @@ func ToOrderResponse(order *entity.Order) *OrderResponse {
 		User: UserDetails{
 			Name:    order.User.Name,
 			Hp:      order.User.Hp,
-			Address: order.User.Address,
+			Address: order.Delivery.Address,
 		},
-		AmountPay: order.AmountPay,
-		Menus:     menus,
-		Status:    order.Status,
+		Subtotal:      order.Subtotal,
+		Promotions:    toOrderDiscountDetails(order.DiscountLines()),
+		PromoDiscount: order.PromoDiscount,
+		Discount:      order.Discount,
+		VoucherCode:   order.VoucherCode,
+		DeliveryFee:   order.DeliveryFee,
+		AmountPay:     order.AmountPay,
+		Bill:          ToOrderBill(order),
+		PaymentMethod: order.PaymentMethod,
+		Menus:         menus,
+		Status:        order.Status,
+		CourierID:     order.CourierID,
+		Delivery:      toDeliveryDetails(order),
+		ScheduledFor:  order.ScheduledFor,
+	}
+}
+
+func toDeliveryDetails(order *entity.Order) DeliveryDetails {
+	return DeliveryDetails{
+		AddressID:  order.AddressID,
+		Label:      order.Delivery.Label,
+		Address:    order.Delivery.Address,
+		Latitude:   order.Delivery.Latitude,
+		Longitude:  order.Delivery.Longitude,
+		Notes:      order.Delivery.Notes,
+		ZoneID:     order.DeliveryZoneID,
+		DistanceKm: order.DistanceKm,
 	}
 }
+
+type ReorderReq struct {
+	OrderID uint `validate:"required"`
+	UserID  uint `validate:"required"`
+}
+
+// ReorderChange is how one item of the new cart differs from the order, each item shows up once.
+type ReorderChange struct {
+	MenuID        uint     `json:"menu_id"`
+	Name          string   `json:"name"`
+	Reasons       []string `json:"reasons"`
+	Qty           int      `json:"qty"`
+	PreviousQty   int      `json:"previous_qty"`
+	UnitPrice     float64  `json:"unit_price"`
+	PreviousPrice float64  `json:"previous_price"`
+}
+
+type ReorderResponse struct {
+	OrderID uint            `json:"order_id"`
+	Cart    *CartResponse   `json:"cart"`
+	Changes []ReorderChange `json:"changes"`
+}
@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"online-food/entity"
+	"time"
+)
+
+type DeliveryUpdateReq struct {
+	OrderID   uint   `validate:"required" form:"-"`
+	CourierID uint   `validate:"required" form:"-"`
+	Status    string `validate:"required,oneof=picked_up delivered failed" form:"status" json:"status"`
+	Notes     string `validate:"max=255" form:"notes" json:"notes"`
+	Photo     []byte `form:"-" json:"-"`
+}
+
+type CourierLocationReq struct {
+	CourierID  uint       `validate:"required"`
+	Latitude   *float64   `validate:"required,latitude" json:"latitude"`
+	Longitude  *float64   `validate:"required,longitude" json:"longitude"`
+	AccuracyM  float64    `validate:"gte=0" json:"accuracy_m"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+}
+
+type DeliveryTrackReq struct {
+	OrderID uint `validate:"required"`
+	UserID  uint `validate:"required"`
+	All     bool `json:"-"`
+}
+
+type DeliveryPhoto struct {
+	Name        string
+	ContentType string
+	Body        []byte
+}
+
+type CourierDetails struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Hp   string `json:"hp"`
+}
+
+type DeliveryResponse struct {
+	ID          uint           `json:"id"`
+	OrderID     uint           `json:"order_id"`
+	Status      string         `json:"status"`
+	Courier     CourierDetails `json:"courier"`
+	AssignedAt  time.Time      `json:"assigned_at"`
+	PickedUpAt  *time.Time     `json:"picked_up_at,omitempty"`
+	DeliveredAt *time.Time     `json:"delivered_at,omitempty"`
+	FailedAt    *time.Time     `json:"failed_at,omitempty"`
+	ProofNotes  string         `json:"proof_notes,omitempty"`
+	ProofPhoto  bool           `json:"proof_photo"`
+}
+
+type CourierPosition struct {
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	AccuracyM  float64   `json:"accuracy_m"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// DeliveryTrackingResponse is what the customer sees, the position is only shared while the delivery is open.
+type DeliveryTrackingResponse struct {
+	OrderID     uint             `json:"order_id"`
+	OrderStatus string           `json:"order_status"`
+	Delivery    DeliveryResponse `json:"delivery"`
+	Position    *CourierPosition `json:"position"`
+	DistanceKm  *float64         `json:"distance_km,omitempty"`
+	EtaMinutes  *int             `json:"eta_minutes,omitempty"`
+	EtaAt       *time.Time       `json:"eta_at,omitempty"`
+}
+
+func ToDeliveryResponse(delivery *entity.Delivery) DeliveryResponse {
+	return DeliveryResponse{
+		ID:      delivery.ID,
+		OrderID: delivery.OrderID,
+		Status:  delivery.Status,
+		Courier: CourierDetails{
+			ID:   delivery.Courier.ID,
+			Name: delivery.Courier.Name,
+			Hp:   delivery.Courier.Hp,
+		},
+		AssignedAt:  delivery.AssignedAt,
+		PickedUpAt:  delivery.PickedUpAt,
+		DeliveredAt: delivery.DeliveredAt,
+		FailedAt:    delivery.FailedAt,
+		ProofNotes:  delivery.ProofNotes,
+		ProofPhoto:  delivery.ProofPhoto != "",
+	}
+}
+
+func ToCourierPosition(location *entity.CourierLocation) *CourierPosition {
+	return &CourierPosition{
+		Latitude:   location.Latitude,
+		Longitude:  location.Longitude,
+		AccuracyM:  location.AccuracyM,
+		RecordedAt: location.RecordedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"encoding/json"
+	"online-food/entity"
+	"online-food/utils/geo"
+	"time"
+)
+
+type DeliveryZoneCreateReq struct {
+	Name     string      `validate:"required,min=1,max=100" json:"name"`
+	Type     string      `validate:"required,oneof=radius polygon" json:"type"`
+	RadiusKm float64     `validate:"required_if=Type radius,gte=0" json:"radius_km"`
+	Polygon  []geo.Point `validate:"required_if=Type polygon" json:"polygon"`
+	Fee      float64     `validate:"gte=0" json:"fee"`
+	MinOrder float64     `validate:"gte=0" json:"min_order"`
+	Active   *bool       `json:"active"`
+}
+
+type DeliveryZoneUpdateReq struct {
+	ID       uint        `validate:"required"`
+	Name     *string     `validate:"omitempty,min=1,max=100" json:"name,omitempty"`
+	Type     *string     `validate:"omitempty,oneof=radius polygon" json:"type,omitempty"`
+	RadiusKm *float64    `validate:"omitempty,gt=0" json:"radius_km,omitempty"`
+	Polygon  []geo.Point `json:"polygon,omitempty"`
+	Fee      *float64    `validate:"omitempty,gte=0" json:"fee,omitempty"`
+	MinOrder *float64    `validate:"omitempty,gte=0" json:"min_order,omitempty"`
+	Active   *bool       `json:"active,omitempty"`
+}
+
+type DeliveryZoneResponse struct {
+	ID        uint        `json:"id"`
+	Name      string      `json:"name"`
+	Type      string      `json:"type"`
+	RadiusKm  float64     `json:"radius_km,omitempty"`
+	Polygon   []geo.Point `json:"polygon,omitempty"`
+	Fee       float64     `json:"fee"`
+	MinOrder  float64     `json:"min_order"`
+	Active    bool        `json:"active"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func ToDeliveryZoneResponse(zone *entity.DeliveryZone) *DeliveryZoneResponse {
+	var polygon []geo.Point
+	if zone.Polygon != "" {
+		_ = json.Unmarshal([]byte(zone.Polygon), &polygon)
+	}
+
+	return &DeliveryZoneResponse{
+		ID:        zone.ID,
+		Name:      zone.Name,
+		Type:      zone.Type,
+		RadiusKm:  zone.RadiusKm,
+		Polygon:   polygon,
+		Fee:       zone.Fee,
+		MinOrder:  zone.MinOrder,
+		Active:    zone.Active,
+		CreatedAt: zone.CreatedAt,
+		UpdatedAt: zone.UpdatedAt,
+	}
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"online-food/entity"
+	"time"
+)
+
+type OfferReq struct {
+	ID        uint `validate:"required"`
+	CourierID uint `validate:"required"`
+}
+
+type OfferResponse struct {
+	ID            uint            `json:"id"`
+	OrderID       uint            `json:"order_id"`
+	CourierID     uint            `json:"courier_id"`
+	Status        string          `json:"status"`
+	Score         float64         `json:"score"`
+	DistanceKm    float64         `json:"distance_km"`
+	OfferedAt     time.Time       `json:"offered_at"`
+	ExpiresAt     time.Time       `json:"expires_at"`
+	AmountPay     float64         `json:"amount_pay,omitempty"`
+	PaymentMethod string          `json:"payment_method,omitempty"`
+	Delivery      DeliveryDetails `json:"delivery"`
+}
+
+// DispatchResponse is what one dispatch round did, waiting are the orders nobody could be offered.
+type DispatchResponse struct {
+	Expired int64           `json:"expired"`
+	Offers  []OfferResponse `json:"offers"`
+	Waiting []uint          `json:"waiting"`
+}
+
+// ToOfferResponse shows the order's address and what the courier collects, the order has to be loaded.
+func ToOfferResponse(offer *entity.DeliveryOffer) OfferResponse {
+	return OfferResponse{
+		ID:            offer.ID,
+		OrderID:       offer.OrderID,
+		CourierID:     offer.CourierID,
+		Status:        offer.Status,
+		Score:         offer.Score,
+		DistanceKm:    offer.DistanceKm,
+		OfferedAt:     offer.OfferedAt,
+		ExpiresAt:     offer.ExpiresAt,
+		AmountPay:     offer.Order.AmountPay,
+		PaymentMethod: offer.Order.PaymentMethod,
+		Delivery:      toDeliveryDetails(&offer.Order),
+	}
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"online-food/entity"
+	"time"
+)
+
+type FavoriteReq struct {
+	UserID uint `validate:"required"`
+	MenuID uint `validate:"required"`
+}
+
+type SavedListReq struct {
+	ID    uint             `json:"-"`
+	Name  string           `validate:"required,min=1,max=100" json:"name"`
+	Items []CreateMenuItem `validate:"required,min=1,dive" json:"items"`
+}
+
+type FavoriteResponse struct {
+	Menu      *MenuResponse `json:"menu"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type SavedListItemResponse struct {
+	MenuID    uint    `json:"menu_id"`
+	Name      string  `json:"name"`
+	Qty       int     `json:"qty"`
+	Price     float64 `json:"price"`
+	Available bool    `json:"available"`
+}
+
+type SavedListResponse struct {
+	ID        uint                    `json:"id"`
+	Name      string                  `json:"name"`
+	Items     []SavedListItemResponse `json:"items"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
+}
+
+func ToFavoriteResponse(favorite *entity.Favorite) FavoriteResponse {
+	menu := ToMenuResponse(&favorite.Menu)
+	menu.IsFavorite = true
+	return FavoriteResponse{Menu: menu, CreatedAt: favorite.CreatedAt}
+}
+
+// ToSavedListResponse marks an item unavailable once its menu is deleted or sold out.
+func ToSavedListResponse(list *entity.SavedList) SavedListResponse {
+	items := make([]SavedListItemResponse, 0, len(list.Items))
+	for _, v := range list.Items {
+		items = append(items, SavedListItemResponse{
+			MenuID:    v.MenuID,
+			Name:      v.Menu.Name,
+			Qty:       v.Qty,
+			Price:     v.Menu.Price,
+			Available: !v.Menu.DeletedAt.Valid && v.Menu.Stock >= v.Qty,
+		})
+	}
+
+	return SavedListResponse{
+		ID:        list.ID,
+		Name:      list.Name,
+		Items:     items,
+		CreatedAt: list.CreatedAt,
+		UpdatedAt: list.UpdatedAt,
+	}
+}
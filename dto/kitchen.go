@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"online-food/entity"
+	"sort"
+	"time"
+)
+
+type KitchenItem struct {
+	ID      uint       `json:"id"`
+	MenuID  uint       `json:"menu_id"`
+	Name    string     `json:"name"`
+	Qty     int        `json:"qty"`
+	Ready   bool       `json:"ready"`
+	ReadyAt *time.Time `json:"ready_at,omitempty"`
+}
+
+type KitchenStation struct {
+	Station string        `json:"station"`
+	Items   []KitchenItem `json:"items"`
+}
+
+type KitchenOrder struct {
+	OrderID   uint             `json:"order_id"`
+	Status    string           `json:"status"`
+	OrderDate time.Time        `json:"order_date"`
+	DueAt     *time.Time       `json:"due_at,omitempty"`
+	Customer  string           `json:"customer"`
+	Notes     string           `json:"notes,omitempty"`
+	Stations  []KitchenStation `json:"stations"`
+}
+
+// KitchenMessage is everything the kitchen display receives, Cursor is sent back as resume after a reconnect.
+type KitchenMessage struct {
+	Type    string         `json:"type"`
+	Cursor  string         `json:"cursor,omitempty"`
+	Orders  []KitchenOrder `json:"orders,omitempty"`
+	Order   *KitchenOrder  `json:"order,omitempty"`
+	OrderID uint           `json:"order_id,omitempty"`
+	ItemIDs []uint         `json:"item_ids,omitempty"`
+	Status  string         `json:"status,omitempty"`
+	Message string         `json:"message,omitempty"`
+}
+
+// KitchenCommand is what the kitchen display sends: bump, recall or ping.
+type KitchenCommand struct {
+	Type    string `json:"type"`
+	OrderID uint   `json:"order_id"`
+	ItemID  *uint  `json:"item_id"`
+}
+
+// ToKitchenOrder groups the items by station, the station of an item is its menu category.
+func ToKitchenOrder(order *entity.Order) KitchenOrder {
+	stations := map[string][]KitchenItem{}
+	for _, v := range order.Items {
+		stations[v.Category] = append(stations[v.Category], KitchenItem{
+			ID:      v.ID,
+			MenuID:  v.MenuID,
+			Name:    v.Name,
+			Qty:     v.Qty,
+			Ready:   v.ReadyAt != nil,
+			ReadyAt: v.ReadyAt,
+		})
+	}
+
+	names := make([]string, 0, len(stations))
+	for k := range stations {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	result := KitchenOrder{
+		OrderID:   order.ID,
+		Status:    order.Status,
+		OrderDate: order.OrderDate,
+		DueAt:     order.ScheduledFor,
+		Customer:  order.User.Name,
+		Notes:     order.Delivery.Notes,
+		Stations:  make([]KitchenStation, 0, len(names)),
+	}
+
+	for _, v := range names {
+		result.Stations = append(result.Stations, KitchenStation{Station: v, Items: stations[v]})
+	}
+
+	return result
+}
@@ -29,6 +29,7 @@ type MenuResponse struct {
 	Price       float64   `json:"price"`
 	Category    string    `json:"category"`
 	Description string    `json:"description"`
+	IsFavorite  bool      `json:"is_favorite"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
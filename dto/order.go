@@ -0,0 +1,36 @@
+package dto
+
+type OrderStatusReq struct {
+	ID     uint   `validate:"required"`
+	Status string `validate:"required,oneof=pending paid preparing ready delivering delivered cancelled" json:"status"`
+}
+
+type OrderAssignCourierReq struct {
+	ID        uint `validate:"required"`
+	CourierID uint `validate:"required" json:"courier_id"`
+}
+
+type InvoiceReq struct {
+	ID     uint   `validate:"required"`
+	UserID uint   `validate:"required"`
+	All    bool   `json:"-"`
+	Format string `validate:"omitempty,oneof=pdf text escpos" form:"format"`
+}
+
+type InvoiceFile struct {
+	Name        string
+	ContentType string
+	Body        []byte
+}
+
+type OrderStreamReq struct {
+	UserID  uint
+	All     bool
+	Courier bool
+	Done    <-chan struct{}
+}
+
+type KitchenItemReq struct {
+	ID     uint  `validate:"required"`
+	ItemID *uint `validate:"omitempty,gt=0" json:"item_id"`
+}
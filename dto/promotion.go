@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"online-food/entity"
+	"time"
+)
+
+type PromotionCreateReq struct {
+	Name        string     `validate:"required,min=1,max=100" json:"name"`
+	Type        string     `validate:"required,oneof=bogo happy_hour spend_save" json:"type"`
+	MenuID      *uint      `validate:"omitempty,gt=0" json:"menu_id"`
+	Category    string     `validate:"omitempty,oneof=makanan minuman" json:"category"`
+	BuyQty      int        `validate:"gte=0" json:"buy_qty"`
+	FreeQty     int        `validate:"gte=0" json:"free_qty"`
+	Percent     float64    `validate:"gte=0,lte=100" json:"percent"`
+	HourStart   string     `validate:"omitempty,datetime=15:04" json:"hour_start"`
+	HourEnd     string     `validate:"omitempty,datetime=15:04" json:"hour_end"`
+	SpendAmount float64    `validate:"gte=0" json:"spend_amount"`
+	SaveAmount  float64    `validate:"gte=0" json:"save_amount"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Active      *bool      `json:"active"`
+}
+
+type PromotionUpdateReq struct {
+	ID          uint       `validate:"required"`
+	Name        *string    `validate:"omitempty,min=1,max=100" json:"name,omitempty"`
+	Type        *string    `validate:"omitempty,oneof=bogo happy_hour spend_save" json:"type,omitempty"`
+	MenuID      *uint      `json:"menu_id,omitempty"`
+	Category    *string    `validate:"omitempty,oneof=makanan minuman all" json:"category,omitempty"`
+	BuyQty      *int       `validate:"omitempty,gte=0" json:"buy_qty,omitempty"`
+	FreeQty     *int       `validate:"omitempty,gte=0" json:"free_qty,omitempty"`
+	Percent     *float64   `validate:"omitempty,gte=0,lte=100" json:"percent,omitempty"`
+	HourStart   *string    `validate:"omitempty,datetime=15:04" json:"hour_start,omitempty"`
+	HourEnd     *string    `validate:"omitempty,datetime=15:04" json:"hour_end,omitempty"`
+	SpendAmount *float64   `validate:"omitempty,gte=0" json:"spend_amount,omitempty"`
+	SaveAmount  *float64   `validate:"omitempty,gte=0" json:"save_amount,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Active      *bool      `json:"active,omitempty"`
+}
+
+type PromotionResponse struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	MenuID      *uint      `json:"menu_id"`
+	Category    string     `json:"category"`
+	BuyQty      int        `json:"buy_qty,omitempty"`
+	FreeQty     int        `json:"free_qty,omitempty"`
+	Percent     float64    `json:"percent,omitempty"`
+	HourStart   string     `json:"hour_start,omitempty"`
+	HourEnd     string     `json:"hour_end,omitempty"`
+	SpendAmount float64    `json:"spend_amount,omitempty"`
+	SaveAmount  float64    `json:"save_amount,omitempty"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func ToPromotionResponse(promotion *entity.Promotion) *PromotionResponse {
+	return &PromotionResponse{
+		ID:          promotion.ID,
+		Name:        promotion.Name,
+		Type:        promotion.Type,
+		MenuID:      promotion.MenuID,
+		Category:    promotion.Category,
+		BuyQty:      promotion.BuyQty,
+		FreeQty:     promotion.FreeQty,
+		Percent:     promotion.Percent,
+		HourStart:   promotion.HourStart,
+		HourEnd:     promotion.HourEnd,
+		SpendAmount: promotion.SpendAmount,
+		SaveAmount:  promotion.SaveAmount,
+		StartsAt:    promotion.StartsAt,
+		EndsAt:      promotion.EndsAt,
+		Active:      promotion.Active,
+		CreatedAt:   promotion.CreatedAt,
+		UpdatedAt:   promotion.UpdatedAt,
+	}
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "online-food/entity"
+
+type RecommendationReq struct {
+	UserID uint `validate:"required"`
+	MenuID uint `form:"menu_id"`
+	Limit  int  `validate:"omitempty,gte=1,lte=50" form:"limit"`
+}
+
+type RecommendedMenu struct {
+	Menu   *MenuResponse `json:"menu"`
+	Reason string        `json:"reason"`
+	Score  float64       `json:"score"`
+}
+
+type RecommendationResponse struct {
+	ForYou         []RecommendedMenu `json:"for_you"`
+	Personalised   bool              `json:"personalised"`
+	BoughtTogether []RecommendedMenu `json:"bought_together,omitempty"`
+}
+
+type RecommendationRefreshResponse struct {
+	Baskets int `json:"baskets"`
+	Pairs   int `json:"pairs"`
+	Users   int `json:"users"`
+	Menus   int `json:"menus"`
+}
+
+func ToRecommendedMenu(menu *entity.Menu, reason string, score float64) RecommendedMenu {
+	return RecommendedMenu{Menu: ToMenuResponse(menu), Reason: reason, Score: score}
+}
@@ -0,0 +1,75 @@
+package dto
+
+type ReportReq struct {
+	From     string `validate:"omitempty,datetime=2006-01-02" form:"from"`
+	To       string `validate:"omitempty,datetime=2006-01-02" form:"to"`
+	Timezone string `form:"tz"`
+	Group    string `validate:"omitempty,oneof=day week month" form:"group"`
+	Sort     string `validate:"omitempty,oneof=qty revenue" form:"sort"`
+	Limit    int    `validate:"omitempty,gte=1,lte=100" form:"limit"`
+	Format   string `validate:"omitempty,oneof=json csv" form:"format"`
+}
+
+// ReportRange is the period of a report, From and To are whole days in Timezone and both included.
+type ReportRange struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Timezone string `json:"timezone"`
+}
+
+type RevenueBucket struct {
+	Period      string  `json:"period"`
+	Orders      int     `json:"orders"`
+	Revenue     float64 `json:"revenue"`
+	Discount    float64 `json:"discount"`
+	Tax         float64 `json:"tax"`
+	DeliveryFee float64 `json:"delivery_fee"`
+}
+
+type RevenueReport struct {
+	ReportRange
+	Group   string          `json:"group"`
+	Orders  int             `json:"orders"`
+	Revenue float64         `json:"revenue"`
+	Periods []RevenueBucket `json:"periods"`
+}
+
+type StatusCount struct {
+	Status string `json:"status"`
+	Orders int    `json:"orders"`
+}
+
+type OrderCountReport struct {
+	ReportRange
+	Total    int           `json:"total"`
+	Statuses []StatusCount `json:"statuses"`
+}
+
+type MenuSales struct {
+	MenuID  uint    `json:"menu_id"`
+	Name    string  `json:"name"`
+	Qty     int     `json:"qty"`
+	Revenue float64 `json:"revenue"`
+}
+
+type TopMenuReport struct {
+	ReportRange
+	Sort  string      `json:"sort"`
+	Menus []MenuSales `json:"menus"`
+}
+
+type SalesSummary struct {
+	ReportRange
+	Orders             int     `json:"orders"`
+	Revenue            float64 `json:"revenue"`
+	AverageOrderValue  float64 `json:"average_order_value"`
+	Customers          int     `json:"customers"`
+	NewCustomers       int     `json:"new_customers"`
+	ReturningCustomers int     `json:"returning_customers"`
+}
+
+type ReportFile struct {
+	Name        string
+	ContentType string
+	Body        []byte
+}
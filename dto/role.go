@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"online-food/entity"
+	"time"
+)
+
+type RoleCreateReq struct {
+	Name        string   `validate:"required,min=1,max=50" json:"name"`
+	Description string   `validate:"omitempty,max=255" json:"description"`
+	Permissions []string `validate:"required,dive,required" json:"permissions"`
+}
+
+type RoleUpdateReq struct {
+	ID          uint     `validate:"required"`
+	Description *string  `validate:"omitempty,max=255" json:"description,omitempty"`
+	Permissions []string `validate:"omitempty,dive,required" json:"permissions,omitempty"`
+}
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type RoleResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	System      bool      `json:"system"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func ToRoleResponse(role *entity.Role) *RoleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, v := range role.Permissions {
+		permissions = append(permissions, v.Name)
+	}
+
+	return &RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		System:      role.System,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func ToPermissionResponse(permission *entity.Permission) *PermissionResponse {
+	return &PermissionResponse{
+		Name:        permission.Name,
+		Description: permission.Description,
+	}
+}
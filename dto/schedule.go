@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"online-food/utils/schedule"
+	"time"
+)
+
+type ScheduleConfigReq struct {
+	Hours       map[string][]schedule.Window `json:"hours"`
+	SlotMinutes *int                         `validate:"required,gte=5,lte=1440" json:"slot_minutes"`
+	Capacity    *int                         `validate:"required,gte=0" json:"capacity"`
+	LeadMinutes *int                         `validate:"required,gte=0,lte=1440" json:"lead_minutes"`
+	MaxDays     *int                         `validate:"required,gte=1,lte=60" json:"max_days"`
+}
+
+type ScheduleConfigResponse struct {
+	Timezone string `json:"timezone"`
+	schedule.Config
+}
+
+type SlotReq struct {
+	Date string `validate:"required" form:"date"`
+}
+
+type SlotResponse struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Booked    int       `json:"booked"`
+	Remaining *int      `json:"remaining,omitempty"`
+	Available bool      `json:"available"`
+}
+
+func ToScheduleConfigResponse(config schedule.Config, loc *time.Location) *ScheduleConfigResponse {
+	return &ScheduleConfigResponse{Timezone: loc.String(), Config: config}
+}
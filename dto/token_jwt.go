@@ -3,17 +3,44 @@ package dto
 import "github.com/golang-jwt/jwt/v5"
 
 type TokenClaim struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	TokenType string `json:"token_type"`
+	Session   uint   `json:"sv"`
 	jwt.RegisteredClaims
 }
 
 type TokenResponse struct {
-	Username     string `json:"username"`
-	Token        string `json:"access_token"`
-	TokenRefresh string `json:"refresh_token"`
-	TokenType    string `json:"token_type"`
-	ExipresIn    int    `json:"expires_in"`
+	Username               string `json:"username"`
+	Token                  string `json:"access_token,omitempty"`
+	TokenRefresh           string `json:"refresh_token,omitempty"`
+	TokenType              string `json:"token_type,omitempty"`
+	ExipresIn              int    `json:"expires_in"`
+	ChallengeToken         string `json:"challenge_token,omitempty"`
+	TwoFactorRequired      bool   `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool   `json:"two_factor_setup_required,omitempty"`
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSResponse struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type PurposeTokenClaim struct {
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
 }
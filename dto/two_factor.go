@@ -0,0 +1,43 @@
+package dto
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorChallengeReq struct {
+	ChallengeToken string `validate:"required" json:"challenge_token"`
+}
+
+type TwoFactorCodeReq struct {
+	Code string `validate:"required,min=6,max=20" json:"code"`
+}
+
+type TwoFactorConfirmReq struct {
+	ChallengeToken string `validate:"omitempty" json:"challenge_token,omitempty"`
+	Code           string `validate:"required,numeric,len=6" json:"code"`
+}
+
+type TwoFactorLoginReq struct {
+	ChallengeToken string `validate:"required" json:"challenge_token"`
+	Code           string `validate:"required,min=6,max=20" json:"code"`
+	IP             string `json:"-"`
+}
+
+type TwoFactorDisableReq struct {
+	Password string `validate:"required,min=8,max=100" json:"password"`
+	Code     string `validate:"required,min=6,max=20" json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string       `json:"recovery_codes"`
+	Token         *TokenResponse `json:"token,omitempty"`
+}
+
+type TwoFactorPolicyReq struct {
+	AdminRequired *bool `validate:"required" json:"admin_required"`
+}
+
+type TwoFactorPolicyResponse struct {
+	AdminRequired bool `json:"admin_required"`
+}
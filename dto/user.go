@@ -20,31 +20,72 @@ type UserUpdateReq struct {
 	Address  *string `validate:"omitempty,min=1" json:"address,omitempty"`
 }
 
+type UserRoleReq struct {
+	Role string `validate:"required,max=50" json:"role"`
+}
+
+type UserSuspendReq struct {
+	Reason string `validate:"max=255" json:"reason"`
+}
+
 type UserResponse struct {
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Hp        string    `json:"hp"`
-	Address   string    `json:"address"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	Hp            string     `json:"hp"`
+	Address       string     `json:"address"`
+	EmailVerified bool       `json:"email_verified"`
+	TwoFactor     bool       `json:"two_factor"`
+	SuspendedAt   *time.Time `json:"suspended_at,omitempty"`
+	SuspendReason string     `json:"suspend_reason,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 type UserLoginReq struct {
 	Email    string `validate:"required,email,min=1,max=100" json:"email"`
 	Password string `validate:"required,min=8,max=100" json:"password"`
+	IP       string `json:"-"`
 }
 
 type UserRefreshTokenReq struct {
 	TokenRefresh string `validate:"required" json:"refresh_token"`
 }
 
+type UserResendVerificationReq struct {
+	Email string `validate:"required,email,min=1,max=100" json:"email"`
+}
+
+type UserForgotPasswordReq struct {
+	Email string `validate:"required,email,min=1,max=100" json:"email"`
+}
+
+type UserResetPasswordReq struct {
+	Token    string `validate:"required" json:"token"`
+	Password string `validate:"required,min=8,max=255" json:"password"`
+}
+
 func ToUserResponse(user *entity.User) *UserResponse {
+	var deletedAt *time.Time
+	if user.DeletedAt.Valid {
+		deletedAt = &user.DeletedAt.Time
+	}
+
 	return &UserResponse{
-		Name:      user.Name,
-		Email:     user.Email,
-		Hp:        user.Hp,
-		Address:   user.Address,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.Role,
+		Hp:            user.Hp,
+		Address:       user.Address,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		TwoFactor:     user.TwoFactor,
+		SuspendedAt:   user.SuspendedAt,
+		SuspendReason: user.SuspendReason,
+		DeletedAt:     deletedAt,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
 	}
 }
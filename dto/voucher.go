@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"online-food/entity"
+	"strings"
+	"time"
+)
+
+type VoucherCreateReq struct {
+	Code         string     `validate:"required,min=3,max=50,alphanum" json:"code"`
+	Description  string     `validate:"max=255" json:"description"`
+	Type         string     `validate:"required,oneof=percentage fixed" json:"type"`
+	Value        float64    `validate:"required,gt=0" json:"value"`
+	MinSpend     float64    `validate:"gte=0" json:"min_spend"`
+	MaxDiscount  float64    `validate:"gte=0" json:"max_discount"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	UsageLimit   int        `validate:"gte=0" json:"usage_limit"`
+	PerUserLimit int        `validate:"gte=0" json:"per_user_limit"`
+	MenuIDs      []uint     `validate:"omitempty,dive,gt=0" json:"menu_ids"`
+	Categories   []string   `validate:"omitempty,dive,oneof=makanan minuman" json:"categories"`
+	Active       *bool      `json:"active"`
+}
+
+type VoucherUpdateReq struct {
+	ID           uint       `validate:"required"`
+	Code         *string    `validate:"omitempty,min=3,max=50,alphanum" json:"code,omitempty"`
+	Description  *string    `validate:"omitempty,max=255" json:"description,omitempty"`
+	Type         *string    `validate:"omitempty,oneof=percentage fixed" json:"type,omitempty"`
+	Value        *float64   `validate:"omitempty,gt=0" json:"value,omitempty"`
+	MinSpend     *float64   `validate:"omitempty,gte=0" json:"min_spend,omitempty"`
+	MaxDiscount  *float64   `validate:"omitempty,gte=0" json:"max_discount,omitempty"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	UsageLimit   *int       `validate:"omitempty,gte=0" json:"usage_limit,omitempty"`
+	PerUserLimit *int       `validate:"omitempty,gte=0" json:"per_user_limit,omitempty"`
+	MenuIDs      []uint     `validate:"omitempty,dive,gt=0" json:"menu_ids,omitempty"`
+	Categories   []string   `validate:"omitempty,dive,oneof=makanan minuman" json:"categories,omitempty"`
+	Active       *bool      `json:"active,omitempty"`
+}
+
+type VoucherPreviewReq struct {
+	CartID uint   `validate:"required"`
+	Code   string `validate:"required,max=50" json:"code"`
+}
+
+type VoucherResponse struct {
+	ID           uint       `json:"id"`
+	Code         string     `json:"code"`
+	Description  string     `json:"description"`
+	Type         string     `json:"type"`
+	Value        float64    `json:"value"`
+	MinSpend     float64    `json:"min_spend"`
+	MaxDiscount  float64    `json:"max_discount"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	UsageLimit   int        `json:"usage_limit"`
+	PerUserLimit int        `json:"per_user_limit"`
+	UsedCount    int        `json:"used_count"`
+	MenuIDs      []uint     `json:"menu_ids"`
+	Categories   []string   `json:"categories"`
+	Active       bool       `json:"active"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type VoucherPreviewResponse struct {
+	Code             string  `json:"code"`
+	Subtotal         float64 `json:"subtotal"`
+	EligibleSubtotal float64 `json:"eligible_subtotal"`
+	Discount         float64 `json:"discount"`
+	Total            float64 `json:"total"`
+}
+
+func ToVoucherResponse(voucher *entity.Voucher) *VoucherResponse {
+	menuIDs := make([]uint, 0, len(voucher.Menus))
+	for _, v := range voucher.Menus {
+		menuIDs = append(menuIDs, v.ID)
+	}
+
+	categories := []string{}
+	if voucher.Categories != "" {
+		categories = strings.Split(voucher.Categories, ",")
+	}
+
+	return &VoucherResponse{
+		ID:           voucher.ID,
+		Code:         voucher.Code,
+		Description:  voucher.Description,
+		Type:         voucher.Type,
+		Value:        voucher.Value,
+		MinSpend:     voucher.MinSpend,
+		MaxDiscount:  voucher.MaxDiscount,
+		StartsAt:     voucher.StartsAt,
+		EndsAt:       voucher.EndsAt,
+		UsageLimit:   voucher.UsageLimit,
+		PerUserLimit: voucher.PerUserLimit,
+		UsedCount:    voucher.UsedCount,
+		MenuIDs:      menuIDs,
+		Categories:   categories,
+		Active:       voucher.Active,
+		CreatedAt:    voucher.CreatedAt,
+		UpdatedAt:    voucher.UpdatedAt,
+	}
+}
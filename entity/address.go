@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Address struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement"`
+	UserID    uint           `gorm:"notnull;index"`
+	User      User           `gorm:"foreignKey:UserID;references:ID;onDelete:CASCADE"`
+	Label     string         `gorm:"size:50;notnull"`
+	Address   string         `gorm:"size:255;notnull"`
+	Latitude  float64        `gorm:"type:decimal(10,7);notnull"`
+	Longitude float64        `gorm:"type:decimal(10,7);notnull"`
+	Notes     string         `gorm:"size:255;notnull;default:''"`
+	IsDefault bool           `gorm:"notnull;default:false"`
+	CreatedAt time.Time      `gorm:"notnull"`
+	UpdatedAt time.Time      `gorm:"notnull"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// AddressSnapshot is copied onto an order at checkout so editing an address later doesn't rewrite history.
+type AddressSnapshot struct {
+	Label     string   `gorm:"size:50;notnull;default:''"`
+	Address   string   `gorm:"size:255;notnull;default:''"`
+	Latitude  *float64 `gorm:"type:decimal(10,7);default:null"`
+	Longitude *float64 `gorm:"type:decimal(10,7);default:null"`
+	Notes     string   `gorm:"size:255;notnull;default:''"`
+}
+
+func (a *Address) Snapshot() AddressSnapshot {
+	lat, lng := a.Latitude, a.Longitude
+	return AddressSnapshot{
+		Label:     a.Label,
+		Address:   a.Address,
+		Latitude:  &lat,
+		Longitude: &lng,
+		Notes:     a.Notes,
+	}
+}
@@ -11,6 +11,8 @@ type Cart struct {
 	UserID    uint           `gorm:"notnull"`
 	User      User           `gorm:"foreignKey:UserID;references:ID"`
 	CartMenu  []CartMenu     `gorm:"foreignKey:CartID"`
+	Discounts []CartDiscount `gorm:"foreignKey:CartID"`
+	Subtotal  float64        `gorm:"notnull;default:0"`
 	Amount    float64        `gorm:"default:null"`
 	Status    string         `gorm:"type:enum('uncheckout','checkout');default:'uncheckout';notnull"`
 	CreatedAt time.Time      `gorm:"notnull"`
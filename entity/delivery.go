@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Delivery is one courier's attempt at an order, a failed attempt stays and a new one is made on reassignment.
+type Delivery struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"`
+	OrderID     uint       `gorm:"notnull;index"`
+	Order       Order      `gorm:"foreignKey:OrderID;references:ID;onDelete:CASCADE"`
+	CourierID   uint       `gorm:"notnull;index"`
+	Courier     User       `gorm:"foreignKey:CourierID;references:ID;onDelete:RESTRICT"`
+	Status      string     `gorm:"type:enum('assigned','picked_up','delivered','failed');default:'assigned';notnull;index"`
+	AssignedAt  time.Time  `gorm:"notnull"`
+	PickedUpAt  *time.Time `gorm:"default:null"`
+	DeliveredAt *time.Time `gorm:"default:null"`
+	FailedAt    *time.Time `gorm:"default:null"`
+	ProofNotes  string     `gorm:"size:255;notnull;default:''"`
+	ProofPhoto  string     `gorm:"size:255;notnull;default:''"`
+	CreatedAt   time.Time  `gorm:"notnull"`
+	UpdatedAt   time.Time  `gorm:"notnull"`
+}
+
+// CourierLocation is a gps fix sent by a courier app, the latest one is shown to customers.
+type CourierLocation struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement"`
+	CourierID  uint      `gorm:"notnull;index:idx_courier_recorded,priority:1"`
+	Latitude   float64   `gorm:"type:decimal(10,7);notnull"`
+	Longitude  float64   `gorm:"type:decimal(10,7);notnull"`
+	AccuracyM  float64   `gorm:"notnull;default:0"`
+	RecordedAt time.Time `gorm:"notnull;index:idx_courier_recorded,priority:2"`
+}
+
+// DeliveryOffer is a ready order proposed to one courier by the dispatcher, it is only assigned once accepted.
+type DeliveryOffer struct {
+	ID          uint       `gorm:"primaryKey;autoIncrement"`
+	OrderID     uint       `gorm:"notnull;index"`
+	Order       Order      `gorm:"foreignKey:OrderID;references:ID;onDelete:CASCADE"`
+	CourierID   uint       `gorm:"notnull;index"`
+	Courier     User       `gorm:"foreignKey:CourierID;references:ID;onDelete:CASCADE"`
+	Status      string     `gorm:"type:enum('pending','accepted','declined','expired','withdrawn');default:'pending';notnull;index"`
+	Score       float64    `gorm:"notnull;default:0"`
+	DistanceKm  float64    `gorm:"notnull;default:0"`
+	OfferedAt   time.Time  `gorm:"notnull"`
+	ExpiresAt   time.Time  `gorm:"notnull;index"`
+	RespondedAt *time.Time `gorm:"default:null"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DeliveryZone struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement"`
+	Name      string         `gorm:"size:100;notnull"`
+	Type      string         `gorm:"type:enum('radius','polygon');notnull"`
+	RadiusKm  float64        `gorm:"notnull;default:0"`
+	Polygon   string         `gorm:"type:text"`
+	Fee       float64        `gorm:"notnull;default:0"`
+	MinOrder  float64        `gorm:"notnull;default:0"`
+	Active    bool           `gorm:"notnull;default:true"`
+	CreatedAt time.Time      `gorm:"notnull"`
+	UpdatedAt time.Time      `gorm:"notnull"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+type Favorite struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	MenuID    uint      `gorm:"primaryKey;autoIncrement:false;index"`
+	Menu      Menu      `gorm:"foreignKey:MenuID;references:ID;onDelete:CASCADE"`
+	CreatedAt time.Time `gorm:"notnull"`
+}
+
+// SavedList is a named set of menus a user orders together, like an office lunch.
+type SavedList struct {
+	ID        uint            `gorm:"primaryKey;autoIncrement"`
+	UserID    uint            `gorm:"notnull;uniqueIndex:idx_user_list"`
+	Name      string          `gorm:"size:100;notnull;uniqueIndex:idx_user_list"`
+	Items     []SavedListItem `gorm:"foreignKey:SavedListID"`
+	CreatedAt time.Time       `gorm:"notnull"`
+	UpdatedAt time.Time       `gorm:"notnull"`
+}
+
+type SavedListItem struct {
+	ID          uint `gorm:"primaryKey;autoIncrement"`
+	SavedListID uint `gorm:"notnull;uniqueIndex:idx_list_menu"`
+	MenuID      uint `gorm:"notnull;uniqueIndex:idx_list_menu"`
+	Menu        Menu `gorm:"foreignKey:MenuID;references:ID;onDelete:CASCADE"`
+	Qty         int  `gorm:"notnull"`
+}
@@ -7,15 +7,96 @@ import (
 )
 
 type Order struct {
-	ID        uint           `gorm:"primaryKey;autoIncrement"`
-	UserID    uint           `grom:"notnull"`
-	User      User           `gorm:"foreignKey:UserID;references:ID;onDelete:RESTRICT"`
-	CartID    uint           `gorm:"notnull"`
-	Cart      Cart           `gorm:"foreignKey:CartID;references:ID;onDelete:RESTRICT"`
-	AmountPay float64        `gorm:"notnull"`
-	OrderDate time.Time      `gorm:"notnull"`
-	Status    string         `gorm:"type:enum('pending','paid','cancelled');default:'pending';notnull"`
-	CreatedAt time.Time      `gorm:"notnull"`
-	UpdatedAt time.Time      `gorm:"notnull"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID             uint            `gorm:"primaryKey;autoIncrement"`
+	UserID         uint            `grom:"notnull"`
+	User           User            `gorm:"foreignKey:UserID;references:ID;onDelete:RESTRICT"`
+	CartID         uint            `gorm:"notnull"`
+	Cart           Cart            `gorm:"foreignKey:CartID;references:ID;onDelete:RESTRICT"`
+	CourierID      *uint           `gorm:"default:null;index"`
+	Courier        *User           `gorm:"foreignKey:CourierID;references:ID;onDelete:SET NULL"`
+	Subtotal       float64         `gorm:"notnull;default:0"`
+	DeliveryFee    float64         `gorm:"notnull;default:0"`
+	PromoDiscount  float64         `gorm:"notnull;default:0"`
+	VoucherID      *uint           `gorm:"default:null"`
+	VoucherCode    string          `gorm:"size:50;notnull;default:''"`
+	Discount       float64         `gorm:"notnull;default:0"`
+	ServiceRate    float64         `gorm:"notnull;default:0"`
+	ServiceCharge  float64         `gorm:"notnull;default:0"`
+	Tax            float64         `gorm:"notnull;default:0"`
+	TaxIncluded    float64         `gorm:"notnull;default:0"`
+	Taxes          []OrderTax      `gorm:"foreignKey:OrderID"`
+	Discounts      []OrderDiscount `gorm:"foreignKey:OrderID"`
+	Items          []OrderItem     `gorm:"foreignKey:OrderID"`
+	InvoiceNo      *string         `gorm:"size:30;uniqueIndex"`
+	InvoicedAt     *time.Time      `gorm:"default:null"`
+	AmountPay      float64         `gorm:"notnull"`
+	PaymentMethod  string          `gorm:"type:enum('cash','transfer');default:'cash';notnull"`
+	AddressID      *uint           `gorm:"default:null;index"`
+	Delivery       AddressSnapshot `gorm:"embedded;embeddedPrefix:delivery_"`
+	DeliveryZoneID *uint           `gorm:"default:null"`
+	DistanceKm     float64         `gorm:"notnull;default:0"`
+	OrderDate      time.Time       `gorm:"notnull"`
+	ScheduledFor   *time.Time      `gorm:"default:null;index"`
+	ReleasedAt     *time.Time      `gorm:"default:null;index"`
+	Status         string          `gorm:"type:enum('pending','paid','preparing','ready','delivering','delivered','cancelled');default:'pending';notnull;index"`
+	CreatedAt      time.Time       `gorm:"notnull"`
+	UpdatedAt      time.Time       `gorm:"notnull"`
+	DeletedAt      gorm.DeletedAt  `gorm:"index"`
+}
+
+// OrderItem is the menu as it was sold, invoices are printed from here and not from the live menu.
+type OrderItem struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	OrderID   uint       `gorm:"notnull;index"`
+	MenuID    uint       `gorm:"notnull"`
+	Name      string     `gorm:"size:255;notnull"`
+	Category  string     `gorm:"size:20;notnull"`
+	UnitPrice float64    `gorm:"notnull"`
+	Qty       int        `gorm:"notnull"`
+	ReadyAt   *time.Time `gorm:"default:null"`
+}
+
+// InvoiceSequence hands out invoice numbers without gaps, one row per year.
+type InvoiceSequence struct {
+	Year int `gorm:"primaryKey;autoIncrement:false"`
+	Last int `gorm:"notnull;default:0"`
+}
+
+// OrderTax is the tax charged for one menu category, kept so the bill can be printed again as it was.
+type OrderTax struct {
+	ID        uint    `gorm:"primaryKey;autoIncrement"`
+	OrderID   uint    `gorm:"notnull;index"`
+	Category  string  `gorm:"size:20;notnull"`
+	Rate      float64 `gorm:"notnull"`
+	Inclusive bool    `gorm:"notnull"`
+	Base      float64 `gorm:"notnull"`
+	Amount    float64 `gorm:"notnull"`
+}
+
+// OrderDiscount is a promotion line as it was charged at checkout, the cart's lines are priced again while it is open.
+type OrderDiscount struct {
+	ID          uint    `gorm:"primaryKey;autoIncrement"`
+	OrderID     uint    `gorm:"notnull;index"`
+	PromotionID uint    `gorm:"notnull"`
+	Name        string  `gorm:"size:100;notnull"`
+	Amount      float64 `gorm:"notnull"`
+}
+
+// DiscountLines is the promotions charged on the order, orders placed before the lines were kept read them from the cart.
+func (o *Order) DiscountLines() []OrderDiscount {
+	if len(o.Discounts) > 0 || o.PromoDiscount == 0 {
+		return o.Discounts
+	}
+
+	lines := make([]OrderDiscount, 0, len(o.Cart.Discounts))
+	for _, v := range o.Cart.Discounts {
+		lines = append(lines, OrderDiscount{OrderID: o.ID, PromotionID: v.PromotionID, Name: v.Name, Amount: v.Amount})
+	}
+	return lines
+}
+
+// ScheduleSlot counts the orders booked into a delivery slot, the row lock keeps a full slot from taking one more.
+type ScheduleSlot struct {
+	Start  time.Time `gorm:"primaryKey;autoIncrement:false"`
+	Booked int       `gorm:"notnull;default:0"`
 }
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+)
+
+type PasswordReset struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `gorm:"notnull;index"`
+	User      User       `gorm:"foreignKey:UserID;references:ID;onDelete:CASCADE"`
+	TokenHash string     `gorm:"size:64;uniqueIndex;notnull"`
+	ExpiresAt time.Time  `gorm:"notnull"`
+	UsedAt    *time.Time `gorm:"default:null"`
+	CreatedAt time.Time  `gorm:"notnull"`
+}
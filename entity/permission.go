@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"time"
+)
+
+type Permission struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement"`
+	Name        string    `gorm:"size:100;unique;notnull"`
+	Description string    `gorm:"size:255"`
+	CreatedAt   time.Time `gorm:"notnull"`
+	UpdatedAt   time.Time `gorm:"notnull"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Promotion struct {
+	ID          uint           `gorm:"primaryKey;autoIncrement"`
+	Name        string         `gorm:"size:100;notnull"`
+	Type        string         `gorm:"type:enum('bogo','happy_hour','spend_save');notnull"`
+	MenuID      *uint          `gorm:"default:null"`
+	Category    string         `gorm:"size:20;notnull;default:''"`
+	BuyQty      int            `gorm:"notnull;default:0"`
+	FreeQty     int            `gorm:"notnull;default:0"`
+	Percent     float64        `gorm:"notnull;default:0"`
+	HourStart   string         `gorm:"size:5;notnull;default:''"`
+	HourEnd     string         `gorm:"size:5;notnull;default:''"`
+	SpendAmount float64        `gorm:"notnull;default:0"`
+	SaveAmount  float64        `gorm:"notnull;default:0"`
+	StartsAt    *time.Time     `gorm:"default:null"`
+	EndsAt      *time.Time     `gorm:"default:null"`
+	Active      bool           `gorm:"notnull;default:true"`
+	CreatedAt   time.Time      `gorm:"notnull"`
+	UpdatedAt   time.Time      `gorm:"notnull"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// CartDiscount is a promotion applied to a cart, rewritten every time the cart amount is recomputed.
+type CartDiscount struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement"`
+	CartID      uint      `gorm:"notnull;index"`
+	PromotionID uint      `gorm:"notnull"`
+	Name        string    `gorm:"size:100;notnull"`
+	Amount      float64   `gorm:"notnull"`
+	CreatedAt   time.Time `gorm:"notnull"`
+}
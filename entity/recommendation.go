@@ -0,0 +1,26 @@
+package entity
+
+// MenuPair is a menu often bought with another one, rebuilt by the recommendation job.
+type MenuPair struct {
+	MenuID    uint    `gorm:"primaryKey;autoIncrement:false"`
+	RelatedID uint    `gorm:"primaryKey;autoIncrement:false"`
+	Related   Menu    `gorm:"foreignKey:RelatedID;references:ID;onDelete:CASCADE"`
+	Count     int     `gorm:"notnull"`
+	Score     float64 `gorm:"notnull"`
+}
+
+// UserRecommendation is a menu picked for a user from what they bought before.
+type UserRecommendation struct {
+	UserID uint    `gorm:"primaryKey;autoIncrement:false"`
+	MenuID uint    `gorm:"primaryKey;autoIncrement:false"`
+	Menu   Menu    `gorm:"foreignKey:MenuID;references:ID;onDelete:CASCADE"`
+	Score  float64 `gorm:"notnull"`
+}
+
+// MenuPopularity ranks the best sellers shown to users without a history.
+type MenuPopularity struct {
+	MenuID uint `gorm:"primaryKey;autoIncrement:false"`
+	Menu   Menu `gorm:"foreignKey:MenuID;references:ID;onDelete:CASCADE"`
+	Orders int  `gorm:"notnull"`
+	Qty    int  `gorm:"notnull"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"time"
+)
+
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `gorm:"notnull;index"`
+	User      User       `gorm:"foreignKey:UserID;references:ID;onDelete:CASCADE"`
+	CodeHash  string     `gorm:"size:64;notnull;index"`
+	UsedAt    *time.Time `gorm:"default:null"`
+	CreatedAt time.Time  `gorm:"notnull"`
+}
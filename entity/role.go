@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+)
+
+type Role struct {
+	ID          uint         `gorm:"primaryKey;autoIncrement"`
+	Name        string       `gorm:"size:50;unique;notnull"`
+	Description string       `gorm:"size:255"`
+	System      bool         `gorm:"notnull;default:false"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time    `gorm:"notnull"`
+	UpdatedAt   time.Time    `gorm:"notnull"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import (
+	"time"
+)
+
+type Setting struct {
+	Key       string    `gorm:"primaryKey;size:100"`
+	Value     string    `gorm:"size:255;notnull"`
+	UpdatedAt time.Time `gorm:"notnull"`
+}
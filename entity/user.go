@@ -7,14 +7,21 @@ import (
 )
 
 type User struct {
-	ID        uint           `gorm:"primaryKey;autoIncrement"`
-	Name      string         `gorm:"size:100;notnull"`
-	Email     string         `gorm:"size:100;unique;notnull"`
-	Password  string         `gorm:"size:255;notnull"`
-	Role      string         `gorm:"type:enum('customer','admin');default:'customer';notnull"`
-	Hp        string         `gorm:"notnull"`
-	Address   string         `gorm:"notnull"`
-	CreatedAt time.Time      `gorm:"notnull"`
-	UpdatedAt time.Time      `gorm:"notnull"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	ID              uint           `gorm:"primaryKey;autoIncrement"`
+	Name            string         `gorm:"size:100;notnull"`
+	Email           string         `gorm:"size:100;unique;notnull"`
+	Password        string         `gorm:"size:255;notnull"`
+	Role            string         `gorm:"size:50;default:'customer';notnull;index"`
+	Hp              string         `gorm:"notnull"`
+	Address         string         `gorm:"notnull"`
+	EmailVerifiedAt *time.Time     `gorm:"default:null"`
+	SessionVersion  uint           `gorm:"notnull;default:0"`
+	TotpSecret      string         `gorm:"size:64;notnull;default:''"`
+	TotpLastStep    int64          `gorm:"notnull;default:0"`
+	TwoFactor       bool           `gorm:"notnull;default:false"`
+	SuspendedAt     *time.Time     `gorm:"default:null"`
+	SuspendReason   string         `gorm:"size:255;notnull;default:''"`
+	CreatedAt       time.Time      `gorm:"notnull"`
+	UpdatedAt       time.Time      `gorm:"notnull"`
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
 }
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Voucher struct {
+	ID           uint           `gorm:"primaryKey;autoIncrement"`
+	Code         string         `gorm:"size:50;uniqueIndex;notnull"`
+	Description  string         `gorm:"size:255;notnull;default:''"`
+	Type         string         `gorm:"type:enum('percentage','fixed');notnull"`
+	Value        float64        `gorm:"notnull"`
+	MinSpend     float64        `gorm:"notnull;default:0"`
+	MaxDiscount  float64        `gorm:"notnull;default:0"`
+	StartsAt     *time.Time     `gorm:"default:null"`
+	EndsAt       *time.Time     `gorm:"default:null"`
+	UsageLimit   int            `gorm:"notnull;default:0"`
+	PerUserLimit int            `gorm:"notnull;default:0"`
+	UsedCount    int            `gorm:"notnull;default:0"`
+	Categories   string         `gorm:"size:100;notnull;default:''"`
+	Menus        []Menu         `gorm:"many2many:voucher_menus"`
+	Active       bool           `gorm:"notnull;default:true"`
+	CreatedAt    time.Time      `gorm:"notnull"`
+	UpdatedAt    time.Time      `gorm:"notnull"`
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+// Running reports whether the voucher can be used at the given time, limits are checked separately.
+func (v *Voucher) Running(now time.Time) bool {
+	if !v.Active {
+		return false
+	}
+
+	if v.StartsAt != nil && now.Before(*v.StartsAt) {
+		return false
+	}
+
+	if v.EndsAt != nil && !now.Before(*v.EndsAt) {
+		return false
+	}
+
+	return true
+}
+
+type VoucherRedemption struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	VoucherID uint      `gorm:"notnull;index:idx_voucher_user"`
+	Voucher   Voucher   `gorm:"foreignKey:VoucherID;references:ID;onDelete:RESTRICT"`
+	UserID    uint      `gorm:"notnull;index:idx_voucher_user"`
+	User      User      `gorm:"foreignKey:UserID;references:ID;onDelete:CASCADE"`
+	OrderID   uint      `gorm:"notnull;uniqueIndex"`
+	Order     Order     `gorm:"foreignKey:OrderID;references:ID;onDelete:CASCADE"`
+	Discount  float64   `gorm:"notnull"`
+	CreatedAt time.Time `gorm:"notnull"`
+}
@@ -0,0 +1,309 @@
+package fixtures
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"online-food/utils/hashing"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed demo.json
+var demo []byte
+
+type User struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+	Hp       string `json:"hp"`
+	Address  string `json:"address"`
+}
+
+type Menu struct {
+	Name        string  `json:"name"`
+	Category    string  `json:"category"`
+	Price       float64 `json:"price"`
+	Stock       int     `json:"stock"`
+	Description string  `json:"description"`
+}
+
+type Item struct {
+	Menu string `json:"menu"`
+	Qty  int    `json:"qty"`
+}
+
+type Order struct {
+	Status        string `json:"status"`
+	PaymentMethod string `json:"payment_method"`
+	Courier       string `json:"courier"`
+	DaysAgo       int    `json:"days_ago"`
+}
+
+// Cart references its user by email and its items by menu name, a cart with an order is checked out.
+type Cart struct {
+	User  string `json:"user"`
+	Items []Item `json:"items"`
+	Order *Order `json:"order"`
+}
+
+type Fixture struct {
+	Users []User `json:"users"`
+	Menus []Menu `json:"menus"`
+	Carts []Cart `json:"carts"`
+}
+
+type Result struct {
+	Users  int
+	Menus  int
+	Carts  int
+	Orders int
+}
+
+// Demo returns the dataset bundled with the binary.
+func Demo() (*Fixture, error) {
+	return Parse(demo)
+}
+
+func Load(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Fixture, error) {
+	fixture := &Fixture{}
+	if err := json.Unmarshal(data, fixture); err != nil {
+		return nil, fmt.Errorf("parse fixture: %w", err)
+	}
+
+	return fixture, nil
+}
+
+// Apply inserts the fixture in one transaction. Users and menus that already exist are reused,
+// carts are only created for users added by this run so seeding twice doesn't duplicate orders.
+func (f *Fixture) Apply(db *gorm.DB) (*Result, error) {
+	result := &Result{}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		users := map[string]*entity.User{}
+		created := map[string]bool{}
+
+		for _, v := range f.Users {
+			email := strings.ToLower(strings.TrimSpace(v.Email))
+
+			user := &entity.User{}
+			find := tx.Where("email = ?", email).Limit(1).Find(user)
+			if find.Error != nil {
+				return fmt.Errorf("find user %s: %w", email, find.Error)
+			}
+
+			if find.RowsAffected == 0 {
+				var role entity.Role
+				if err := tx.Where("name = ?", v.Role).Take(&role).Error; err != nil {
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return fmt.Errorf("user %s: unknown role %q", email, v.Role)
+					}
+					return fmt.Errorf("find role %s: %w", v.Role, err)
+				}
+
+				pass, err := hashing.HashPassword(v.Password)
+				if err != nil {
+					return fmt.Errorf("hashing: %w", err)
+				}
+
+				now := time.Now()
+				user = &entity.User{
+					Name:            v.Name,
+					Email:           email,
+					Password:        pass,
+					Role:            v.Role,
+					Hp:              v.Hp,
+					Address:         v.Address,
+					EmailVerifiedAt: &now,
+				}
+
+				if err := tx.Create(user).Error; err != nil {
+					return fmt.Errorf("create user %s: %w", email, err)
+				}
+
+				created[email] = true
+				result.Users++
+			}
+
+			users[email] = user
+		}
+
+		menus := map[string]*entity.Menu{}
+		for _, v := range f.Menus {
+			menu := &entity.Menu{}
+			find := tx.Where("name = ?", v.Name).Limit(1).Find(menu)
+			if find.Error != nil {
+				return fmt.Errorf("find menu %s: %w", v.Name, find.Error)
+			}
+
+			if find.RowsAffected == 0 {
+				menu = &entity.Menu{
+					Name:        v.Name,
+					Category:    v.Category,
+					Price:       v.Price,
+					Stock:       v.Stock,
+					Description: v.Description,
+				}
+
+				if err := tx.Create(menu).Error; err != nil {
+					return fmt.Errorf("create menu %s: %w", v.Name, err)
+				}
+
+				result.Menus++
+			}
+
+			menus[v.Name] = menu
+		}
+
+		for i, v := range f.Carts {
+			email := strings.ToLower(strings.TrimSpace(v.User))
+
+			user, ok := users[email]
+			if !ok {
+				return fmt.Errorf("cart %d: user %s is not in the fixture", i, v.User)
+			}
+
+			if !created[email] {
+				continue
+			}
+
+			if err := applyCart(tx, i, user, v, users, menus, result); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func applyCart(tx *gorm.DB, index int, user *entity.User, v Cart, users map[string]*entity.User, menus map[string]*entity.Menu, result *Result) error {
+	if len(v.Items) == 0 {
+		return fmt.Errorf("cart %d: no items", index)
+	}
+
+	cart := entity.Cart{
+		UserID: user.ID,
+		Status: constanta.Uncheckout,
+	}
+
+	amount := 0.0
+	items := make([]entity.OrderItem, 0, len(v.Items))
+	for _, item := range v.Items {
+		menu, ok := menus[item.Menu]
+		if !ok {
+			return fmt.Errorf("cart %d: menu %s is not in the fixture", index, item.Menu)
+		}
+
+		cart.CartMenu = append(cart.CartMenu, entity.CartMenu{
+			MenuID:    menu.ID,
+			UnitPrice: menu.Price,
+			Qty:       item.Qty,
+		})
+		items = append(items, entity.OrderItem{
+			MenuID:    menu.ID,
+			Name:      menu.Name,
+			Category:  menu.Category,
+			UnitPrice: menu.Price,
+			Qty:       item.Qty,
+		})
+		amount += menu.Price * float64(item.Qty)
+	}
+
+	cart.Subtotal = amount
+	cart.Amount = amount
+	if v.Order != nil {
+		cart.Status = constanta.Checkout
+	}
+
+	if err := tx.Create(&cart).Error; err != nil {
+		return fmt.Errorf("cart %d: create cart: %w", index, err)
+	}
+	result.Carts++
+
+	if v.Order == nil {
+		return nil
+	}
+
+	paymentMethod := v.Order.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = constanta.Cash
+	}
+
+	order := entity.Order{
+		UserID:        user.ID,
+		CartID:        cart.ID,
+		Subtotal:      amount,
+		AmountPay:     amount,
+		PaymentMethod: paymentMethod,
+		OrderDate:     time.Now().UTC().AddDate(0, 0, -v.Order.DaysAgo),
+		Status:        v.Order.Status,
+		Delivery:      entity.AddressSnapshot{Address: user.Address},
+		Items:         items,
+	}
+	order.ReleasedAt = &order.OrderDate
+
+	if v.Order.Courier != "" {
+		courier, ok := users[strings.ToLower(v.Order.Courier)]
+		if !ok {
+			return fmt.Errorf("cart %d: courier %s is not in the fixture", index, v.Order.Courier)
+		}
+		order.CourierID = &courier.ID
+	}
+
+	if err := tx.Create(&order).Error; err != nil {
+		return fmt.Errorf("cart %d: create order: %w", index, err)
+	}
+	result.Orders++
+
+	if order.CourierID == nil {
+		return nil
+	}
+
+	delivery := entity.Delivery{
+		OrderID:    order.ID,
+		CourierID:  *order.CourierID,
+		Status:     constanta.DeliveryAssigned,
+		AssignedAt: order.OrderDate,
+	}
+
+	switch order.Status {
+	case constanta.Delivering:
+		delivery.Status = constanta.DeliveryPickedUp
+		delivery.PickedUpAt = &order.OrderDate
+	case constanta.Delivered:
+		delivery.Status = constanta.DeliveryDelivered
+		delivery.PickedUpAt = &order.OrderDate
+		delivery.DeliveredAt = &order.OrderDate
+	case constanta.Cancelled:
+		delivery.Status = constanta.DeliveryFailed
+		delivery.FailedAt = &order.OrderDate
+	}
+
+	if err := tx.Create(&delivery).Error; err != nil {
+		return fmt.Errorf("cart %d: create delivery: %w", index, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AddressHandler interface {
+	Create(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	FindByID(ctx *gin.Context)
+	FindAll(ctx *gin.Context)
+}
+
+type addressHandlerImpl struct {
+	AddressService service.AddressService
+}
+
+func NewAddressHandlerImpl(addressService service.AddressService) *addressHandlerImpl {
+	return &addressHandlerImpl{
+		AddressService: addressService,
+	}
+}
+
+func (a *addressHandlerImpl) Create(ctx *gin.Context) {
+	req := dto.AddressCreateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := a.AddressService.Create(ctx.Request.Context(), user.UserID, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusCreated, "Created", "address created successfully", result)
+}
+
+func (a *addressHandlerImpl) Update(ctx *gin.Context) {
+	req := dto.AddressUpdateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	addressId := ctx.Param("addressId")
+	id, err := strconv.Atoi(addressId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	req.ID = uint(id)
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := a.AddressService.Update(ctx.Request.Context(), user.UserID, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "address updated successfully", result)
+}
+
+func (a *addressHandlerImpl) Delete(ctx *gin.Context) {
+	addressId := ctx.Param("addressId")
+	id, err := strconv.Atoi(addressId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	if err := a.AddressService.Delete(ctx.Request.Context(), user.UserID, uint(id)); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Deleted", "address deleted successfully", nil)
+}
+
+func (a *addressHandlerImpl) FindByID(ctx *gin.Context) {
+	addressId := ctx.Param("addressId")
+	id, err := strconv.Atoi(addressId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := a.AddressService.FindByID(ctx.Request.Context(), user.UserID, uint(id))
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "address found", result)
+}
+
+func (a *addressHandlerImpl) FindAll(ctx *gin.Context) {
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := a.AddressService.FindByUserID(ctx.Request.Context(), user.UserID)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "addresses found", result)
+}
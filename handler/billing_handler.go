@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BillingHandler interface {
+	GetConfig(ctx *gin.Context)
+	UpdateConfig(ctx *gin.Context)
+}
+
+type billingHandlerImpl struct {
+	BillingService service.BillingService
+}
+
+func NewBillingHandlerImpl(billingService service.BillingService) *billingHandlerImpl {
+	return &billingHandlerImpl{
+		BillingService: billingService,
+	}
+}
+
+func (b *billingHandlerImpl) GetConfig(ctx *gin.Context) {
+	result, err := b.BillingService.GetConfig(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get billing settings successfully", result)
+}
+
+func (b *billingHandlerImpl) UpdateConfig(ctx *gin.Context) {
+	req := dto.BillingConfigReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := b.BillingService.UpdateConfig(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "billing settings updated successfully", result)
+}
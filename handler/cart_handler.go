@@ -18,6 +18,7 @@ type CartHandler interface {
 	GetCartByID(ctx *gin.Context)
 	GetAllCarts(ctx *gin.Context)
 	CheckoutCart(ctx *gin.Context)
+	Reorder(ctx *gin.Context)
 }
 
 type cartHandlerImpl struct {
@@ -130,10 +131,19 @@ func (c *cartHandlerImpl) CheckoutCart(ctx *gin.Context) {
 		return
 	}
 
+	//body is optional, checkout without one pays cash
+	req := dto.CheckoutReq{}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			handling.HandleError(ctx, err)
+			return
+		}
+	}
+
 	userClaims, _ := ctx.Get("user")
 	user := userClaims.(*dto.TokenClaim)
 
-	result, err := c.CartService.CheckoutCart(ctx.Request.Context(), uint(id), user.UserID)
+	result, err := c.CartService.CheckoutCart(ctx.Request.Context(), uint(id), user.UserID, &req)
 	if err != nil {
 		handling.HandleError(ctx, err)
 		return
@@ -141,3 +151,23 @@ func (c *cartHandlerImpl) CheckoutCart(ctx *gin.Context) {
 
 	response.ToResponseJson(ctx, http.StatusOK, "Success", "checkout cart successfully", result)
 }
+
+func (c *cartHandlerImpl) Reorder(ctx *gin.Context) {
+	orderId := ctx.Param("orderId")
+	id, err := strconv.Atoi(orderId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := c.CartService.Reorder(ctx.Request.Context(), &dto.ReorderReq{OrderID: uint(id), UserID: user.UserID})
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusCreated, "Created", "cart created from order successfully", result)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxDeliveryPhoto = 5 << 20
+
+type DeliveryHandler interface {
+	UpdateDelivery(ctx *gin.Context)
+	UpdateLocation(ctx *gin.Context)
+	Track(ctx *gin.Context)
+	Photo(ctx *gin.Context)
+}
+
+type deliveryHandlerImpl struct {
+	DeliveryService service.DeliveryService
+}
+
+func NewDeliveryHandlerImpl(deliveryService service.DeliveryService) *deliveryHandlerImpl {
+	return &deliveryHandlerImpl{
+		DeliveryService: deliveryService,
+	}
+}
+
+// UpdateDelivery takes json, or multipart when the courier attaches a photo as "photo".
+func (d *deliveryHandlerImpl) UpdateDelivery(ctx *gin.Context) {
+	req := dto.DeliveryUpdateReq{}
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	id, ok := orderID(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := ctx.FormFile("photo")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) && !errors.Is(err, http.ErrNotMultipart) {
+		handling.HandleError(ctx, handling.ErrInvalidPhoto)
+		return
+	}
+
+	if file != nil {
+		if file.Size > maxDeliveryPhoto {
+			handling.HandleError(ctx, handling.ErrInvalidPhoto)
+			return
+		}
+
+		opened, err := file.Open()
+		if err != nil {
+			handling.HandleError(ctx, handling.ErrInvalidPhoto)
+			return
+		}
+		defer opened.Close()
+
+		if req.Photo, err = io.ReadAll(io.LimitReader(opened, maxDeliveryPhoto)); err != nil {
+			handling.HandleError(ctx, handling.ErrInvalidPhoto)
+			return
+		}
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	req.OrderID = id
+	req.CourierID = user.UserID
+
+	result, err := d.DeliveryService.UpdateDelivery(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "delivery updated successfully", result)
+}
+
+func (d *deliveryHandlerImpl) UpdateLocation(ctx *gin.Context) {
+	req := dto.CourierLocationReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+	req.CourierID = user.UserID
+
+	if err := d.DeliveryService.UpdateLocation(ctx.Request.Context(), &req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "location saved", nil)
+}
+
+func trackReq(ctx *gin.Context) (dto.DeliveryTrackReq, bool) {
+	id, ok := orderID(ctx)
+	if !ok {
+		return dto.DeliveryTrackReq{}, false
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	granted, _ := ctx.Get("permissions")
+	owned := granted.(map[string]bool)
+
+	return dto.DeliveryTrackReq{
+		OrderID: id,
+		UserID:  user.UserID,
+		All:     owned[constanta.PermOrderReadAll],
+	}, true
+}
+
+func (d *deliveryHandlerImpl) Track(ctx *gin.Context) {
+	req, ok := trackReq(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := d.DeliveryService.Track(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "delivery found", result)
+}
+
+func (d *deliveryHandlerImpl) Photo(ctx *gin.Context) {
+	req, ok := trackReq(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := d.DeliveryService.Photo(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", result.Name))
+	ctx.Data(http.StatusOK, result.ContentType, result.Body)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeliveryZoneHandler interface {
+	Create(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	FindByID(ctx *gin.Context)
+	FindAll(ctx *gin.Context)
+}
+
+type deliveryZoneHandlerImpl struct {
+	ZoneService service.DeliveryZoneService
+}
+
+func NewDeliveryZoneHandlerImpl(zoneService service.DeliveryZoneService) *deliveryZoneHandlerImpl {
+	return &deliveryZoneHandlerImpl{
+		ZoneService: zoneService,
+	}
+}
+
+func (d *deliveryZoneHandlerImpl) Create(ctx *gin.Context) {
+	req := dto.DeliveryZoneCreateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := d.ZoneService.Create(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusCreated, "Created", "delivery zone created successfully", result)
+}
+
+func (d *deliveryZoneHandlerImpl) Update(ctx *gin.Context) {
+	req := dto.DeliveryZoneUpdateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	zoneId := ctx.Param("zoneId")
+	id, err := strconv.Atoi(zoneId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	req.ID = uint(id)
+
+	result, err := d.ZoneService.Update(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "delivery zone updated successfully", result)
+}
+
+func (d *deliveryZoneHandlerImpl) Delete(ctx *gin.Context) {
+	zoneId := ctx.Param("zoneId")
+	id, err := strconv.Atoi(zoneId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	if err := d.ZoneService.Delete(ctx.Request.Context(), uint(id)); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Deleted", "delivery zone deleted successfully", nil)
+}
+
+func (d *deliveryZoneHandlerImpl) FindByID(ctx *gin.Context) {
+	zoneId := ctx.Param("zoneId")
+	id, err := strconv.Atoi(zoneId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	result, err := d.ZoneService.FindByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "delivery zone found", result)
+}
+
+func (d *deliveryZoneHandlerImpl) FindAll(ctx *gin.Context) {
+	result, err := d.ZoneService.FindAll(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "delivery zones found", result)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DispatchHandler interface {
+	Dispatch(ctx *gin.Context)
+	Offers(ctx *gin.Context)
+	Accept(ctx *gin.Context)
+	Decline(ctx *gin.Context)
+}
+
+type dispatchHandlerImpl struct {
+	DispatchService service.DispatchService
+}
+
+func NewDispatchHandlerImpl(dispatchService service.DispatchService) *dispatchHandlerImpl {
+	return &dispatchHandlerImpl{
+		DispatchService: dispatchService,
+	}
+}
+
+func offerReq(ctx *gin.Context) (dto.OfferReq, bool) {
+	id, err := strconv.Atoi(ctx.Param("offerId"))
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return dto.OfferReq{}, false
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	return dto.OfferReq{ID: uint(id), CourierID: user.UserID}, true
+}
+
+// Dispatch runs a round now instead of waiting for the next tick.
+func (d *dispatchHandlerImpl) Dispatch(ctx *gin.Context) {
+	result, err := d.DispatchService.Dispatch(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "dispatch finished", result)
+}
+
+func (d *dispatchHandlerImpl) Offers(ctx *gin.Context) {
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := d.DispatchService.Offers(ctx.Request.Context(), user.UserID)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "offers found", result)
+}
+
+func (d *dispatchHandlerImpl) Accept(ctx *gin.Context) {
+	req, ok := offerReq(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := d.DispatchService.Accept(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "offer accepted", result)
+}
+
+func (d *dispatchHandlerImpl) Decline(ctx *gin.Context) {
+	req, ok := offerReq(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := d.DispatchService.Decline(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "offer declined", result)
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FavoriteHandler interface {
+	AddFavorite(ctx *gin.Context)
+	RemoveFavorite(ctx *gin.Context)
+	FindFavorites(ctx *gin.Context)
+	CreateList(ctx *gin.Context)
+	UpdateList(ctx *gin.Context)
+	DeleteList(ctx *gin.Context)
+	FindList(ctx *gin.Context)
+	FindLists(ctx *gin.Context)
+	AddListToCart(ctx *gin.Context)
+}
+
+type favoriteHandlerImpl struct {
+	FavoriteService service.FavoriteService
+}
+
+func NewFavoriteHandlerImpl(favoriteService service.FavoriteService) *favoriteHandlerImpl {
+	return &favoriteHandlerImpl{
+		FavoriteService: favoriteService,
+	}
+}
+
+func favoriteReq(ctx *gin.Context) (*dto.FavoriteReq, bool) {
+	menuId := ctx.Param("menuId")
+	id, err := strconv.Atoi(menuId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return nil, false
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	return &dto.FavoriteReq{UserID: user.UserID, MenuID: uint(id)}, true
+}
+
+func listID(ctx *gin.Context) (uint, bool) {
+	listId := ctx.Param("listId")
+	id, err := strconv.Atoi(listId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func (f *favoriteHandlerImpl) AddFavorite(ctx *gin.Context) {
+	req, ok := favoriteReq(ctx)
+	if !ok {
+		return
+	}
+
+	if err := f.FavoriteService.AddFavorite(ctx.Request.Context(), req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "menu added to favorites successfully", nil)
+}
+
+func (f *favoriteHandlerImpl) RemoveFavorite(ctx *gin.Context) {
+	req, ok := favoriteReq(ctx)
+	if !ok {
+		return
+	}
+
+	if err := f.FavoriteService.RemoveFavorite(ctx.Request.Context(), req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "menu removed from favorites successfully", nil)
+}
+
+func (f *favoriteHandlerImpl) FindFavorites(ctx *gin.Context) {
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := f.FavoriteService.FindFavorites(ctx.Request.Context(), user.UserID)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get favorites successfully", result)
+}
+
+func (f *favoriteHandlerImpl) CreateList(ctx *gin.Context) {
+	req := dto.SavedListReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := f.FavoriteService.CreateList(ctx.Request.Context(), user.UserID, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusCreated, "Created", "saved list created successfully", result)
+}
+
+func (f *favoriteHandlerImpl) UpdateList(ctx *gin.Context) {
+	req := dto.SavedListReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	id, ok := listID(ctx)
+	if !ok {
+		return
+	}
+
+	req.ID = id
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := f.FavoriteService.UpdateList(ctx.Request.Context(), user.UserID, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "saved list updated successfully", result)
+}
+
+func (f *favoriteHandlerImpl) DeleteList(ctx *gin.Context) {
+	id, ok := listID(ctx)
+	if !ok {
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	if err := f.FavoriteService.DeleteList(ctx.Request.Context(), user.UserID, id); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Deleted", "saved list deleted successfully", nil)
+}
+
+func (f *favoriteHandlerImpl) FindList(ctx *gin.Context) {
+	id, ok := listID(ctx)
+	if !ok {
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := f.FavoriteService.FindList(ctx.Request.Context(), user.UserID, id)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get saved list successfully", result)
+}
+
+func (f *favoriteHandlerImpl) FindLists(ctx *gin.Context) {
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := f.FavoriteService.FindLists(ctx.Request.Context(), user.UserID)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get saved lists successfully", result)
+}
+
+func (f *favoriteHandlerImpl) AddListToCart(ctx *gin.Context) {
+	id, ok := listID(ctx)
+	if !ok {
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := f.FavoriteService.AddListToCart(ctx.Request.Context(), user.UserID, id)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusCreated, "Created", "cart created from saved list successfully", result)
+}
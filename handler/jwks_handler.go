@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/utils/handling"
+	"online-food/utils/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JwksHandler interface {
+	Keys(ctx *gin.Context)
+}
+
+type jwksHandlerImpl struct{}
+
+func NewJwksHandlerImpl() *jwksHandlerImpl {
+	return &jwksHandlerImpl{}
+}
+
+func (j *jwksHandlerImpl) Keys(ctx *gin.Context) {
+	result, err := token.JWKS()
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	// served as a raw JWK set so standard jwt libraries can consume it directly
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	kitchenWriteWait  = 10 * time.Second
+	kitchenPongWait   = 60 * time.Second
+	kitchenPingPeriod = 20 * time.Second
+)
+
+type KitchenDisplayHandler interface {
+	Connect(ctx *gin.Context)
+}
+
+type kitchenDisplayHandlerImpl struct {
+	OrderService   service.OrderService
+	DisplayService service.KitchenDisplayService
+	Upgrader       websocket.Upgrader
+}
+
+// NewKitchenDisplayHandlerImpl only accepts browsers from allowedOrigins, without any the display has to be
+// served from the api host. Clients that send no origin are not browsers and are let through.
+func NewKitchenDisplayHandlerImpl(orderService service.OrderService, displayService service.KitchenDisplayService, allowedOrigins []string) *kitchenDisplayHandlerImpl {
+	upgrader := websocket.Upgrader{
+		//echoing "bearer" keeps the token out of the handshake response
+		Subprotocols: []string{constanta.KitchenProtocol},
+	}
+
+	if len(allowedOrigins) > 0 {
+		upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || slices.Contains(allowedOrigins, origin)
+		}
+	}
+
+	return &kitchenDisplayHandlerImpl{
+		OrderService:   orderService,
+		DisplayService: displayService,
+		Upgrader:       upgrader,
+	}
+}
+
+// Connect upgrades to a websocket, sends the snapshot or what was missed since ?resume=cursor,
+// then streams the queue and takes bump, recall and ping commands.
+func (k *kitchenDisplayHandlerImpl) Connect(ctx *gin.Context) {
+	initial, feed, cancel, err := k.DisplayService.Attach(ctx.Request.Context(), ctx.Query("resume"))
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+	defer cancel()
+
+	conn, err := k.Upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	replies := make(chan dto.KitchenMessage, 16)
+	done := make(chan struct{})
+	go k.read(ctx.Request.Context(), conn, replies, done)
+
+	write := func(message dto.KitchenMessage) bool {
+		conn.SetWriteDeadline(time.Now().Add(kitchenWriteWait))
+		return conn.WriteJSON(message) == nil
+	}
+
+	for _, v := range initial {
+		if !write(v) {
+			return
+		}
+	}
+
+	ping := time.NewTicker(kitchenPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case message, ok := <-feed:
+			if !ok {
+				//too slow to keep up, the display reconnects with its last cursor
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "resume"), time.Now().Add(kitchenWriteWait))
+				return
+			}
+			if !write(message) {
+				return
+			}
+		case message := <-replies:
+			if !write(message) {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(kitchenWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// read handles the commands of one display, results reach every display through the feed.
+func (k *kitchenDisplayHandlerImpl) read(parent context.Context, conn *websocket.Conn, replies chan<- dto.KitchenMessage, done chan<- struct{}) {
+	defer close(done)
+
+	conn.SetReadLimit(4096)
+	conn.SetReadDeadline(time.Now().Add(kitchenPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(kitchenPongWait))
+	})
+
+	//the writer stops first when the connection breaks, a reply nobody will send is dropped
+	reply := func(message dto.KitchenMessage) {
+		select {
+		case replies <- message:
+		default:
+		}
+	}
+
+	for {
+		command := dto.KitchenCommand{}
+		if err := conn.ReadJSON(&command); err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(kitchenPongWait))
+
+		var err error
+		req := dto.KitchenItemReq{ID: command.OrderID, ItemID: command.ItemID}
+		ctx, cancel := context.WithTimeout(parent, kitchenWriteWait)
+		switch command.Type {
+		case constanta.KitchenPing:
+			reply(dto.KitchenMessage{Type: constanta.KitchenPong})
+		case constanta.KitchenBump:
+			_, err = k.OrderService.KitchenBump(ctx, &req)
+		case constanta.KitchenRecall:
+			_, err = k.OrderService.KitchenRecall(ctx, &req)
+		default:
+			reply(dto.KitchenMessage{Type: constanta.KitchenError, Message: "unknown command"})
+		}
+
+		cancel()
+
+		if err != nil {
+			reply(dto.KitchenMessage{Type: constanta.KitchenError, OrderID: command.OrderID, Message: handling.Message(err)})
+		}
+	}
+}
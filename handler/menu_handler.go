@@ -96,7 +96,10 @@ func (m *menuHandlerImpl) FindByID(ctx *gin.Context) {
 		return
 	}
 
-	result, err := m.MenuService.FindByID(ctx.Request.Context(), uint(id))
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := m.MenuService.FindByID(ctx.Request.Context(), uint(id), user.UserID)
 	if err != nil {
 		handling.HandleError(ctx, err)
 		return
@@ -106,7 +109,10 @@ func (m *menuHandlerImpl) FindByID(ctx *gin.Context) {
 }
 
 func (m *menuHandlerImpl) FindAll(ctx *gin.Context) {
-	result, err := m.MenuService.FindAll(ctx.Request.Context())
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := m.MenuService.FindAll(ctx.Request.Context(), user.UserID)
 	if err != nil {
 		handling.HandleError(ctx, err)
 		return
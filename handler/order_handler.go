@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrderHandler interface {
+	FindByID(ctx *gin.Context)
+	FindAll(ctx *gin.Context)
+	FindMine(ctx *gin.Context)
+	UpdateStatus(ctx *gin.Context)
+	AssignCourier(ctx *gin.Context)
+	KitchenOrders(ctx *gin.Context)
+	KitchenUpdateStatus(ctx *gin.Context)
+	CashierOrders(ctx *gin.Context)
+	CashierMarkPaid(ctx *gin.Context)
+	CourierOrders(ctx *gin.Context)
+	CourierUpdateStatus(ctx *gin.Context)
+	Invoice(ctx *gin.Context)
+	Stream(ctx *gin.Context)
+}
+
+type orderHandlerImpl struct {
+	OrderService service.OrderService
+}
+
+func NewOrderHandlerImpl(orderService service.OrderService) *orderHandlerImpl {
+	return &orderHandlerImpl{
+		OrderService: orderService,
+	}
+}
+
+func orderID(ctx *gin.Context) (uint, bool) {
+	orderId := ctx.Param("orderId")
+	id, err := strconv.Atoi(orderId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func (o *orderHandlerImpl) FindByID(ctx *gin.Context) {
+	id, ok := orderID(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := o.OrderService.FindByID(ctx.Request.Context(), id)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "order found", result)
+}
+
+func (o *orderHandlerImpl) FindAll(ctx *gin.Context) {
+	result, err := o.OrderService.FindAll(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "orders found", result)
+}
+
+func (o *orderHandlerImpl) FindMine(ctx *gin.Context) {
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := o.OrderService.FindByUserID(ctx.Request.Context(), user.UserID)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "orders found", result)
+}
+
+func (o *orderHandlerImpl) UpdateStatus(ctx *gin.Context) {
+	req := dto.OrderStatusReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	id, ok := orderID(ctx)
+	if !ok {
+		return
+	}
+
+	req.ID = id
+
+	result, err := o.OrderService.UpdateStatus(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "order status updated successfully", result)
+}
+
+func (o *orderHandlerImpl) AssignCourier(ctx *gin.Context) {
+	req := dto.OrderAssignCourierReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	id, ok := orderID(ctx)
+	if !ok {
+		return
+	}
+
+	req.ID = id
+
+	result, err := o.OrderService.AssignCourier(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "courier assigned successfully", result)
+}
+
+func (o *orderHandlerImpl) KitchenOrders(ctx *gin.Context) {
+	result, err := o.OrderService.KitchenOrders(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "orders found", result)
+}
+
+func (o *orderHandlerImpl) KitchenUpdateStatus(ctx *gin.Context) {
+	req := dto.OrderStatusReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	id, ok := orderID(ctx)
+	if !ok {
+		return
+	}
+
+	req.ID = id
+
+	result, err := o.OrderService.KitchenUpdateStatus(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "order status updated successfully", result)
+}
+
+func (o *orderHandlerImpl) CashierOrders(ctx *gin.Context) {
+	result, err := o.OrderService.CashierOrders(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "orders found", result)
+}
+
+func (o *orderHandlerImpl) CashierMarkPaid(ctx *gin.Context) {
+	id, ok := orderID(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := o.OrderService.CashierMarkPaid(ctx.Request.Context(), id)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "order marked as paid", result)
+}
+
+func (o *orderHandlerImpl) CourierOrders(ctx *gin.Context) {
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := o.OrderService.CourierOrders(ctx.Request.Context(), user.UserID)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "orders found", result)
+}
+
+func (o *orderHandlerImpl) CourierUpdateStatus(ctx *gin.Context) {
+	req := dto.OrderStatusReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	id, ok := orderID(ctx)
+	if !ok {
+		return
+	}
+
+	req.ID = id
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := o.OrderService.CourierUpdateStatus(ctx.Request.Context(), user.UserID, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "order status updated successfully", result)
+}
+
+func (o *orderHandlerImpl) Invoice(ctx *gin.Context) {
+	req := dto.InvoiceReq{}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	id, ok := orderID(ctx)
+	if !ok {
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	//staff who read every order or take payments print any invoice
+	granted, _ := ctx.Get("permissions")
+	owned := granted.(map[string]bool)
+
+	req.ID = id
+	req.UserID = user.UserID
+	req.All = owned[constanta.PermOrderReadAll] || owned[constanta.PermOrderPay]
+
+	result, err := o.OrderService.Invoice(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", result.Name))
+	ctx.Data(http.StatusOK, result.ContentType, result.Body)
+}
+
+// Stream sends order events as server-sent events until the client goes away.
+func (o *orderHandlerImpl) Stream(ctx *gin.Context) {
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	granted, _ := ctx.Get("permissions")
+	owned := granted.(map[string]bool)
+
+	req := dto.OrderStreamReq{
+		UserID:  user.UserID,
+		All:     owned[constanta.PermOrderReadAll] || owned[constanta.PermOrderPrepare] || owned[constanta.PermOrderPay],
+		Courier: owned[constanta.PermOrderDeliver],
+		Done:    ctx.Request.Context().Done(),
+	}
+
+	events, cancel := o.OrderService.Subscribe(&req)
+	defer cancel()
+
+	//proxies must not buffer the stream, and idle connections get a ping so they aren't closed
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	ctx.SSEvent("ready", gin.H{"user_id": user.UserID})
+	ctx.Writer.Flush()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-req.Done:
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionHandler interface {
+	Create(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	FindByID(ctx *gin.Context)
+	FindAll(ctx *gin.Context)
+}
+
+type promotionHandlerImpl struct {
+	PromotionService service.PromotionService
+}
+
+func NewPromotionHandlerImpl(promotionService service.PromotionService) *promotionHandlerImpl {
+	return &promotionHandlerImpl{
+		PromotionService: promotionService,
+	}
+}
+
+func (p *promotionHandlerImpl) Create(ctx *gin.Context) {
+	req := dto.PromotionCreateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := p.PromotionService.Create(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusCreated, "Created", "promotion created successfully", result)
+}
+
+func (p *promotionHandlerImpl) Update(ctx *gin.Context) {
+	req := dto.PromotionUpdateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	promotionId := ctx.Param("promotionId")
+	id, err := strconv.Atoi(promotionId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	req.ID = uint(id)
+
+	result, err := p.PromotionService.Update(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "promotion updated successfully", result)
+}
+
+func (p *promotionHandlerImpl) Delete(ctx *gin.Context) {
+	promotionId := ctx.Param("promotionId")
+	id, err := strconv.Atoi(promotionId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	if err := p.PromotionService.Delete(ctx.Request.Context(), uint(id)); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Deleted", "promotion deleted successfully", nil)
+}
+
+func (p *promotionHandlerImpl) FindByID(ctx *gin.Context) {
+	promotionId := ctx.Param("promotionId")
+	id, err := strconv.Atoi(promotionId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	result, err := p.PromotionService.FindByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "promotion found", result)
+}
+
+func (p *promotionHandlerImpl) FindAll(ctx *gin.Context) {
+	result, err := p.PromotionService.FindAll(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "promotions found", result)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler interface {
+	Recommend(ctx *gin.Context)
+	Refresh(ctx *gin.Context)
+}
+
+type recommendationHandlerImpl struct {
+	RecommendationService service.RecommendationService
+}
+
+func NewRecommendationHandlerImpl(recommendationService service.RecommendationService) *recommendationHandlerImpl {
+	return &recommendationHandlerImpl{
+		RecommendationService: recommendationService,
+	}
+}
+
+func (r *recommendationHandlerImpl) Recommend(ctx *gin.Context) {
+	req := dto.RecommendationReq{}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	req.UserID = user.UserID
+
+	result, err := r.RecommendationService.Recommend(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get recommendations successfully", result)
+}
+
+func (r *recommendationHandlerImpl) Refresh(ctx *gin.Context) {
+	result, err := r.RecommendationService.Refresh(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "recommendations refreshed successfully", result)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler interface {
+	Revenue(ctx *gin.Context)
+	OrderCounts(ctx *gin.Context)
+	TopMenus(ctx *gin.Context)
+	Summary(ctx *gin.Context)
+}
+
+type reportHandlerImpl struct {
+	ReportService service.ReportService
+}
+
+func NewReportHandlerImpl(reportService service.ReportService) *reportHandlerImpl {
+	return &reportHandlerImpl{
+		ReportService: reportService,
+	}
+}
+
+// reportReq binds the query, a csv export is answered here and reported back as done.
+func (r *reportHandlerImpl) reportReq(ctx *gin.Context, report string) (*dto.ReportReq, bool) {
+	req := dto.ReportReq{}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return nil, true
+	}
+
+	if req.Format != "csv" {
+		return &req, false
+	}
+
+	result, err := r.ReportService.Export(ctx.Request.Context(), report, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return nil, true
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.Name))
+	ctx.Data(http.StatusOK, result.ContentType, result.Body)
+	return nil, true
+}
+
+func (r *reportHandlerImpl) Revenue(ctx *gin.Context) {
+	req, done := r.reportReq(ctx, constanta.ReportRevenue)
+	if done {
+		return
+	}
+
+	result, err := r.ReportService.Revenue(ctx.Request.Context(), req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get revenue report successfully", result)
+}
+
+func (r *reportHandlerImpl) OrderCounts(ctx *gin.Context) {
+	req, done := r.reportReq(ctx, constanta.ReportOrders)
+	if done {
+		return
+	}
+
+	result, err := r.ReportService.OrderCounts(ctx.Request.Context(), req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get order report successfully", result)
+}
+
+func (r *reportHandlerImpl) TopMenus(ctx *gin.Context) {
+	req, done := r.reportReq(ctx, constanta.ReportMenus)
+	if done {
+		return
+	}
+
+	result, err := r.ReportService.TopMenus(ctx.Request.Context(), req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get menu report successfully", result)
+}
+
+func (r *reportHandlerImpl) Summary(ctx *gin.Context) {
+	req, done := r.reportReq(ctx, constanta.ReportSummary)
+	if done {
+		return
+	}
+
+	result, err := r.ReportService.Summary(ctx.Request.Context(), req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get sales summary successfully", result)
+}
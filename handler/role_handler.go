@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler interface {
+	Create(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	FindByID(ctx *gin.Context)
+	FindAll(ctx *gin.Context)
+	FindAllPermissions(ctx *gin.Context)
+}
+
+type roleHandlerImpl struct {
+	RoleService service.RoleService
+}
+
+func NewRoleHandlerImpl(roleService service.RoleService) *roleHandlerImpl {
+	return &roleHandlerImpl{
+		RoleService: roleService,
+	}
+}
+
+func (r *roleHandlerImpl) Create(ctx *gin.Context) {
+	req := dto.RoleCreateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := r.RoleService.Create(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusCreated, "Created", "role created successfully", result)
+}
+
+func (r *roleHandlerImpl) Update(ctx *gin.Context) {
+	req := dto.RoleUpdateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	roleId := ctx.Param("roleId")
+	id, err := strconv.Atoi(roleId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	req.ID = uint(id)
+
+	result, err := r.RoleService.Update(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "role updated successfully", result)
+}
+
+func (r *roleHandlerImpl) Delete(ctx *gin.Context) {
+	roleId := ctx.Param("roleId")
+	id, err := strconv.Atoi(roleId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	if err := r.RoleService.Delete(ctx.Request.Context(), uint(id)); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Deleted", "role deleted successfully", nil)
+}
+
+func (r *roleHandlerImpl) FindByID(ctx *gin.Context) {
+	roleId := ctx.Param("roleId")
+	id, err := strconv.Atoi(roleId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	result, err := r.RoleService.FindByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "role find id successfully", result)
+}
+
+func (r *roleHandlerImpl) FindAll(ctx *gin.Context) {
+	result, err := r.RoleService.FindAll(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "role find successfully", result)
+}
+
+func (r *roleHandlerImpl) FindAllPermissions(ctx *gin.Context) {
+	result, err := r.RoleService.FindAllPermissions(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "permission find successfully", result)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleHandler interface {
+	GetConfig(ctx *gin.Context)
+	UpdateConfig(ctx *gin.Context)
+	Slots(ctx *gin.Context)
+}
+
+type scheduleHandlerImpl struct {
+	ScheduleService service.ScheduleService
+}
+
+func NewScheduleHandlerImpl(scheduleService service.ScheduleService) *scheduleHandlerImpl {
+	return &scheduleHandlerImpl{
+		ScheduleService: scheduleService,
+	}
+}
+
+func (s *scheduleHandlerImpl) GetConfig(ctx *gin.Context) {
+	result, err := s.ScheduleService.GetConfig(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get schedule settings successfully", result)
+}
+
+func (s *scheduleHandlerImpl) UpdateConfig(ctx *gin.Context) {
+	req := dto.ScheduleConfigReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := s.ScheduleService.UpdateConfig(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "schedule settings updated successfully", result)
+}
+
+func (s *scheduleHandlerImpl) Slots(ctx *gin.Context) {
+	req := dto.SlotReq{}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := s.ScheduleService.Slots(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get delivery slots successfully", result)
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler interface {
+	Setup(ctx *gin.Context)
+	Confirm(ctx *gin.Context)
+	Disable(ctx *gin.Context)
+	RegenerateRecoveryCodes(ctx *gin.Context)
+	ChallengeSetup(ctx *gin.Context)
+	ChallengeConfirm(ctx *gin.Context)
+	Login(ctx *gin.Context)
+	GetPolicy(ctx *gin.Context)
+	UpdatePolicy(ctx *gin.Context)
+}
+
+type twoFactorHandlerImpl struct {
+	TwoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorHandlerImpl(twoFactorService service.TwoFactorService) *twoFactorHandlerImpl {
+	return &twoFactorHandlerImpl{
+		TwoFactorService: twoFactorService,
+	}
+}
+
+func (t *twoFactorHandlerImpl) Setup(ctx *gin.Context) {
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := t.TwoFactorService.Setup(ctx.Request.Context(), user.UserID)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "two factor setup started", result)
+}
+
+func (t *twoFactorHandlerImpl) Confirm(ctx *gin.Context) {
+	req := dto.TwoFactorConfirmReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := t.TwoFactorService.Confirm(ctx.Request.Context(), user.UserID, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "two factor enabled successfully", result)
+}
+
+func (t *twoFactorHandlerImpl) Disable(ctx *gin.Context) {
+	req := dto.TwoFactorDisableReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	if err := t.TwoFactorService.Disable(ctx.Request.Context(), user.UserID, &req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "two factor disabled successfully", nil)
+}
+
+func (t *twoFactorHandlerImpl) RegenerateRecoveryCodes(ctx *gin.Context) {
+	req := dto.TwoFactorCodeReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := t.TwoFactorService.RegenerateRecoveryCodes(ctx.Request.Context(), user.UserID, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "recovery codes regenerated successfully", result)
+}
+
+func (t *twoFactorHandlerImpl) ChallengeSetup(ctx *gin.Context) {
+	req := dto.TwoFactorChallengeReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := t.TwoFactorService.ChallengeSetup(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "two factor setup started", result)
+}
+
+func (t *twoFactorHandlerImpl) ChallengeConfirm(ctx *gin.Context) {
+	req := dto.TwoFactorConfirmReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := t.TwoFactorService.ChallengeConfirm(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "two factor enabled successfully", result)
+}
+
+func (t *twoFactorHandlerImpl) Login(ctx *gin.Context) {
+	req := dto.TwoFactorLoginReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	req.IP = ctx.ClientIP()
+
+	result, err := t.TwoFactorService.Login(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "generate token successfully", result)
+}
+
+func (t *twoFactorHandlerImpl) GetPolicy(ctx *gin.Context) {
+	result, err := t.TwoFactorService.GetPolicy(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "get two factor policy successfully", result)
+}
+
+func (t *twoFactorHandlerImpl) UpdatePolicy(ctx *gin.Context) {
+	req := dto.TwoFactorPolicyReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := t.TwoFactorService.UpdatePolicy(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "two factor policy updated successfully", result)
+}
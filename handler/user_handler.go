@@ -21,6 +21,17 @@ type UserHandler interface {
 	FindByEmail(ctx *gin.Context)
 	Login(ctx *gin.Context)
 	RefreshToken(ctx *gin.Context)
+	VerifyEmail(ctx *gin.Context)
+	ResendVerification(ctx *gin.Context)
+	ForgotPassword(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+	Unlock(ctx *gin.Context)
+	ChangeRole(ctx *gin.Context)
+	Suspend(ctx *gin.Context)
+	Unsuspend(ctx *gin.Context)
+	FindDeleted(ctx *gin.Context)
+	Restore(ctx *gin.Context)
+	Purge(ctx *gin.Context)
 }
 
 type userHandlerImpl struct {
@@ -155,6 +166,8 @@ func (u *userHandlerImpl) Login(ctx *gin.Context) {
 		return
 	}
 
+	req.IP = ctx.ClientIP()
+
 	result, err := u.UserService.Login(ctx.Request.Context(), &req)
 	if err != nil {
 		handling.HandleError(ctx, err)
@@ -179,4 +192,203 @@ func (u *userHandlerImpl) RefreshToken(ctx *gin.Context) {
 	}
 
 	response.ToResponseJson(ctx, http.StatusOK, "Success", "generate new token successfully", result)
-}	
+}
+
+func (u *userHandlerImpl) VerifyEmail(ctx *gin.Context) {
+	verifyToken := ctx.Query("token")
+	if verifyToken == "" {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "token is required", nil)
+		return
+	}
+
+	if err := u.UserService.VerifyEmail(ctx.Request.Context(), verifyToken); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "email verified successfully", nil)
+}
+
+func (u *userHandlerImpl) ResendVerification(ctx *gin.Context) {
+	req := dto.UserResendVerificationReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	if err := u.UserService.ResendVerification(ctx.Request.Context(), &req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "if the email is registered and unverified, a verification link has been sent", nil)
+}
+
+func (u *userHandlerImpl) ForgotPassword(ctx *gin.Context) {
+	req := dto.UserForgotPasswordReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	if err := u.UserService.ForgotPassword(ctx.Request.Context(), &req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "if the email is registered, a reset link has been sent", nil)
+}
+
+func (u *userHandlerImpl) ResetPassword(ctx *gin.Context) {
+	req := dto.UserResetPasswordReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	if err := u.UserService.ResetPassword(ctx.Request.Context(), &req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "password reset successfully", nil)
+}
+
+func (u *userHandlerImpl) Unlock(ctx *gin.Context) {
+	userId := ctx.Param("userId")
+	id, err := strconv.Atoi(userId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	if err := u.UserService.Unlock(ctx.Request.Context(), uint(id)); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Success", "user login unlocked successfully", nil)
+}
+
+func (u *userHandlerImpl) ChangeRole(ctx *gin.Context) {
+	req := dto.UserRoleReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	userId := ctx.Param("userId")
+	id, err := strconv.Atoi(userId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := u.UserService.ChangeRole(ctx.Request.Context(), user.UserID, uint(id), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "user role updated successfully", result)
+}
+
+func (u *userHandlerImpl) Suspend(ctx *gin.Context) {
+	req := dto.UserSuspendReq{}
+
+	//the reason is optional, so an empty body is fine
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			handling.HandleError(ctx, err)
+			return
+		}
+	}
+
+	userId := ctx.Param("userId")
+	id, err := strconv.Atoi(userId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := u.UserService.Suspend(ctx.Request.Context(), user.UserID, uint(id), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "user suspended successfully", result)
+}
+
+func (u *userHandlerImpl) Unsuspend(ctx *gin.Context) {
+	userId := ctx.Param("userId")
+	id, err := strconv.Atoi(userId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	result, err := u.UserService.Unsuspend(ctx.Request.Context(), uint(id))
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "user unsuspended successfully", result)
+}
+
+func (u *userHandlerImpl) FindDeleted(ctx *gin.Context) {
+	result, err := u.UserService.FindDeleted(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "deleted users found", result)
+}
+
+func (u *userHandlerImpl) Restore(ctx *gin.Context) {
+	userId := ctx.Param("userId")
+	id, err := strconv.Atoi(userId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	result, err := u.UserService.Restore(ctx.Request.Context(), uint(id))
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "user restored successfully", result)
+}
+
+func (u *userHandlerImpl) Purge(ctx *gin.Context) {
+	userId := ctx.Param("userId")
+	id, err := strconv.Atoi(userId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	if err := u.UserService.Purge(ctx.Request.Context(), user.UserID, uint(id)); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Deleted", "user purged permanently", nil)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"online-food/dto"
+	"online-food/service"
+	"online-food/utils/handling"
+	"online-food/utils/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VoucherHandler interface {
+	Create(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	FindByID(ctx *gin.Context)
+	FindAll(ctx *gin.Context)
+	Preview(ctx *gin.Context)
+}
+
+type voucherHandlerImpl struct {
+	VoucherService service.VoucherService
+}
+
+func NewVoucherHandlerImpl(voucherService service.VoucherService) *voucherHandlerImpl {
+	return &voucherHandlerImpl{
+		VoucherService: voucherService,
+	}
+}
+
+func (v *voucherHandlerImpl) Create(ctx *gin.Context) {
+	req := dto.VoucherCreateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	result, err := v.VoucherService.Create(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusCreated, "Created", "voucher created successfully", result)
+}
+
+func (v *voucherHandlerImpl) Update(ctx *gin.Context) {
+	req := dto.VoucherUpdateReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	voucherId := ctx.Param("voucherId")
+	id, err := strconv.Atoi(voucherId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	req.ID = uint(id)
+
+	result, err := v.VoucherService.Update(ctx.Request.Context(), &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Updated", "voucher updated successfully", result)
+}
+
+func (v *voucherHandlerImpl) Delete(ctx *gin.Context) {
+	voucherId := ctx.Param("voucherId")
+	id, err := strconv.Atoi(voucherId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	if err := v.VoucherService.Delete(ctx.Request.Context(), uint(id)); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "Deleted", "voucher deleted successfully", nil)
+}
+
+func (v *voucherHandlerImpl) FindByID(ctx *gin.Context) {
+	voucherId := ctx.Param("voucherId")
+	id, err := strconv.Atoi(voucherId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	result, err := v.VoucherService.FindByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "voucher found", result)
+}
+
+func (v *voucherHandlerImpl) FindAll(ctx *gin.Context) {
+	result, err := v.VoucherService.FindAll(ctx.Request.Context())
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "vouchers found", result)
+}
+
+func (v *voucherHandlerImpl) Preview(ctx *gin.Context) {
+	req := dto.VoucherPreviewReq{}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	cartId := ctx.Param("cartId")
+	id, err := strconv.Atoi(cartId)
+	if err != nil {
+		response.ToResponseJson(ctx, http.StatusBadRequest, "Bad Request", "invalid input type id", nil)
+		return
+	}
+
+	req.CartID = uint(id)
+
+	userClaims, _ := ctx.Get("user")
+	user := userClaims.(*dto.TokenClaim)
+
+	result, err := v.VoucherService.Preview(ctx.Request.Context(), user.UserID, &req)
+	if err != nil {
+		handling.HandleError(ctx, err)
+		return
+	}
+
+	response.ToResponseJson(ctx, http.StatusOK, "OK", "voucher can be applied", result)
+}
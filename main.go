@@ -1,46 +1,63 @@
 package main
 
 import (
+	"fmt"
 	"log"
-	"online-food/config"
-	"online-food/handler"
-	"online-food/repository"
-	"online-food/routes"
-	"online-food/service"
 	"os"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 )
 
-func main() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("error load env")
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "start the http server (default)", serve},
+	{"migrate", "migrate the database and sync default roles", migrate},
+	{"create-admin", "create an admin account", createAdmin},
+	{"seed", "load demo users, menus, carts and orders from a fixture file", seed},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	for _, v := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", v.name, v.usage)
 	}
+}
 
-	database := config.Database()
-	//redis:= config.RedisCLient()
-	validate := validator.New()
+func main() {
+	//no command keeps the old behaviour of starting the server
+	name, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		name, args = os.Args[1], os.Args[2:]
+	}
 
-	//user
-	userRepo := repository.NewUserRepositoryImpl(database)
-	userService := service.NewUserServiceImpl(userRepo, validate)
-	userHandler := handler.NewUserHandlerImpl(userService)
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
 
-	//menu
-	menuRepo := repository.NewMenuRepositoryImpl(database)
-	menuService := service.NewMenuServiceImpl(menuRepo, validate)
-	menuHandler := handler.NewMenuHandlerImpl(menuService)
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
 
-	//cart
-	cartRepo := repository.NewCartRepositoryImpl(database)
-	cartService := service.NewCartServiceImpl(cartRepo, validate)
-	cartHandler := handler.NewCartHandlerImpl(cartService)
+	if cmd == nil {
+		usage()
+		os.Exit(2)
+	}
 
-	routes := routes.SetupRouter(userHandler, menuHandler, cartHandler)
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("error load env")
+	}
 
-	port := os.Getenv("APP_PORT")
-	routes.Run(port)
-	log.Println("server running on port " + port)
+	if err := cmd.run(args); err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
 }
@@ -1,18 +1,18 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"online-food/dto"
+	"online-food/repository"
+	"online-food/utils/handling"
 	"online-food/utils/response"
-	"os"
+	"online-food/utils/token"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func Authentication() gin.HandlerFunc {
+func Authentication(userRepo repository.UserRepository, roleRepo repository.RoleRepository) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 
@@ -30,42 +30,96 @@ func Authentication() gin.HandlerFunc {
 		}
 
 		tokenStr := tokenPart[1]
-		jwtSecret := []byte(os.Getenv("JWT_SECRET"))
-		claim := &dto.TokenClaim{}
 
-		token, err := jwt.ParseWithClaims(tokenStr, claim, func(t *jwt.Token) (any, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		claim, err := token.ClaimTokenAccess(tokenStr)
+		if err != nil {
+			response.ToResponseJson(ctx, http.StatusUnauthorized, "Unauthorization", "invalid token", nil)
+			ctx.Abort()
+			return
+		}
+
+		//session version is bumped on password reset, so older tokens stop working right away
+		user, err := userRepo.FindByID(ctx.Request.Context(), claim.UserID)
+		if err != nil {
+			if errors.Is(err, handling.ErrorIdNotFound) {
+				response.ToResponseJson(ctx, http.StatusUnauthorized, "Unauthorization", "invalid token", nil)
+				ctx.Abort()
+				return
 			}
-			return jwtSecret, nil
-		})
+			handling.HandleError(ctx, err)
+			ctx.Abort()
+			return
+		}
 
-		if err != nil || !token.Valid {
-			response.ToResponseJson(ctx, http.StatusUnauthorized, "Unauthorization", "invalid token", nil)
+		if user.SessionVersion != claim.Session {
+			response.ToResponseJson(ctx, http.StatusUnauthorized, "Unauthorization", "session revoked", nil)
+			ctx.Abort()
+			return
+		}
+
+		if user.SuspendedAt != nil {
+			handling.HandleError(ctx, handling.ErrUserSuspended)
 			ctx.Abort()
 			return
 		}
 
+		//role comes from the database so role changes apply without a new token
+		permissions, err := roleRepo.PermissionsByRole(ctx.Request.Context(), user.Role)
+		if err != nil {
+			handling.HandleError(ctx, err)
+			ctx.Abort()
+			return
+		}
+
+		granted := make(map[string]bool, len(permissions))
+		for _, v := range permissions {
+			granted[v] = true
+		}
+
+		claim.Role = user.Role
+
 		ctx.Set("user", claim)
+		ctx.Set("permissions", granted)
 		ctx.Next()
 	}
 
 }
 
-func RoleAccessMiddleware(allowedRoles ...string) gin.HandlerFunc {
+func RequirePermission(permissions ...string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		userClaims, exist := ctx.Get("user")
+		granted, exist := ctx.Get("permissions")
 		if !exist {
 			response.ToResponseJson(ctx, http.StatusUnauthorized, "Unauthorization", "user not found", nil)
 			ctx.Abort()
 			return
 		}
 
-		user := userClaims.(*dto.TokenClaim)
-		role := user.Role
+		owned := granted.(map[string]bool)
+		for _, v := range permissions {
+			if !owned[v] {
+				response.ToResponseJson(ctx, http.StatusForbidden, "Forbidden", "role no permission", nil)
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
 
-		for _, v := range allowedRoles {
-			if role == v {
+// RequireAnyPermission lets the request through when the role has at least one of the permissions.
+func RequireAnyPermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		granted, exist := ctx.Get("permissions")
+		if !exist {
+			response.ToResponseJson(ctx, http.StatusUnauthorized, "Unauthorization", "user not found", nil)
+			ctx.Abort()
+			return
+		}
+
+		owned := granted.(map[string]bool)
+		for _, v := range permissions {
+			if owned[v] {
 				ctx.Next()
 				return
 			}
@@ -75,3 +129,22 @@ func RoleAccessMiddleware(allowedRoles ...string) gin.HandlerFunc {
 		ctx.Abort()
 	}
 }
+
+// ProtocolToken takes the access token from the websocket subprotocols when there is no authorization header,
+// browsers can't set headers on a websocket handshake but can send new WebSocket(url, ["bearer", token]).
+// Unlike the query the header never reaches the access log.
+func ProtocolToken(protocol string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			values := strings.Split(ctx.GetHeader("Sec-WebSocket-Protocol"), ",")
+			for i := 0; i < len(values)-1; i++ {
+				if strings.TrimSpace(values[i]) == protocol {
+					ctx.Request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(values[i+1]))
+					break
+				}
+			}
+		}
+
+		ctx.Next()
+	}
+}
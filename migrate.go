@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"online-food/config"
+)
+
+func migrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	flags.Parse(args)
+
+	if err := config.Migrate(config.Database()); err != nil {
+		return err
+	}
+
+	log.Println("database migrated")
+	return nil
+}
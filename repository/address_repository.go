@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-food/entity"
+	"online-food/utils/handling"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AddressRepository interface {
+	Create(ctx context.Context, address *entity.Address) (*entity.Address, error)
+	Update(ctx context.Context, address *entity.Address, makeDefault bool) (*entity.Address, error)
+	Delete(ctx context.Context, userID, id uint) error
+	FindByID(ctx context.Context, userID, id uint) (*entity.Address, error)
+	FindByUserID(ctx context.Context, userID uint) ([]*entity.Address, error)
+	FindDefault(ctx context.Context, userID uint) (*entity.Address, error)
+}
+
+type addressRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewAddressRepositoryImpl(db *gorm.DB) *addressRepositoryImpl {
+	return &addressRepositoryImpl{
+		Db: db,
+	}
+}
+
+// lockUser serializes default flag changes of one user, so two requests can't both leave a default behind.
+func lockUser(tx *gorm.DB, userID uint) error {
+	var user entity.User
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&user, userID).Error
+}
+
+func clearDefault(tx *gorm.DB, userID uint) error {
+	return tx.Model(&entity.Address{}).Where("user_id = ? AND is_default = ?", userID, true).Update("is_default", false).Error
+}
+
+func (a *addressRepositoryImpl) Create(ctx context.Context, address *entity.Address) (*entity.Address, error) {
+	err := a.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, address.UserID); err != nil {
+			return err
+		}
+
+		//the first address is the default one
+		var count int64
+		if err := tx.Model(&entity.Address{}).Where("user_id = ?", address.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count == 0 {
+			address.IsDefault = true
+		}
+
+		if address.IsDefault {
+			if err := clearDefault(tx, address.UserID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit("User").Create(address).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (a *addressRepositoryImpl) Update(ctx context.Context, address *entity.Address, makeDefault bool) (*entity.Address, error) {
+	err := a.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, address.UserID); err != nil {
+			return err
+		}
+
+		if makeDefault {
+			if err := clearDefault(tx, address.UserID); err != nil {
+				return err
+			}
+			address.IsDefault = true
+		}
+
+		return tx.Omit("User").Select("Label", "Address", "Latitude", "Longitude", "Notes", "IsDefault").Updates(address).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.FindByID(ctx, address.UserID, address.ID)
+}
+
+func (a *addressRepositoryImpl) Delete(ctx context.Context, userID, id uint) error {
+	return a.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, userID); err != nil {
+			return err
+		}
+
+		var address entity.Address
+		if err := tx.Where("user_id = ?", userID).First(&address, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrAddressNotFound
+			}
+			return err
+		}
+
+		if err := tx.Delete(&address).Error; err != nil {
+			return err
+		}
+
+		if !address.IsDefault {
+			return nil
+		}
+
+		//hand the default flag to the most recently added address left
+		var next entity.Address
+		result := tx.Where("user_id = ?", userID).Order("id DESC").Limit(1).Find(&next)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+func (a *addressRepositoryImpl) FindByID(ctx context.Context, userID, id uint) (*entity.Address, error) {
+	var address entity.Address
+	if err := a.Db.WithContext(ctx).Where("user_id = ?", userID).First(&address, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+func (a *addressRepositoryImpl) FindByUserID(ctx context.Context, userID uint) ([]*entity.Address, error) {
+	var addresses []*entity.Address
+	if err := a.Db.WithContext(ctx).Where("user_id = ?", userID).Order("is_default DESC, id").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+func (a *addressRepositoryImpl) FindDefault(ctx context.Context, userID uint) (*entity.Address, error) {
+	var address entity.Address
+	if err := a.Db.WithContext(ctx).Where("user_id = ? AND is_default = ?", userID, true).Take(&address).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrAddressNotFound
+		}
+		return nil, err
+	}
+
+	return &address, nil
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"online-food/entity"
+	"online-food/utils/billing"
 	"online-food/utils/constanta"
 	"online-food/utils/handling"
+	"online-food/utils/promotion"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,13 +16,15 @@ import (
 )
 
 type CartRepository interface {
-	CreateCart(ctx context.Context, cart *entity.Cart) (*entity.Cart, error)
-	UpdateCart(ctx context.Context, cartID, menuID, userID uint, qty int) (*entity.Cart, error)
+	CreateCart(ctx context.Context, cart *entity.Cart, now time.Time) (*entity.Cart, error)
+	UpdateCart(ctx context.Context, cartID, menuID, userID uint, qty int, now time.Time) (*entity.Cart, error)
 	//DeleteCart(ctx context.Context, cartID uint) error
 	GetCartByUserID(ctx context.Context, userID uint) ([]*entity.Cart, error)
 	GetCartByID(ctx context.Context, cartID uint) (*entity.Cart, error)
 	GetAllCarts(ctx context.Context) ([]*entity.Cart, error)
-	CheckoutCart(ctx context.Context, cartID, userID uint) (*entity.Order, error)
+	CheckoutCart(ctx context.Context, cartID, userID uint, order *entity.Order, slotCapacity int) (*entity.Order, error)
+	Reprice(ctx context.Context, cartID, userID uint, now time.Time) error
+	FindOrderLines(ctx context.Context, orderID, userID uint) ([]*entity.CartMenu, error)
 }
 
 type cartRepositoryImpl struct {
@@ -33,7 +37,7 @@ func NewCartRepositoryImpl(db *gorm.DB) CartRepository {
 	}
 }
 
-func (c *cartRepositoryImpl) CreateCart(ctx context.Context, cart *entity.Cart) (*entity.Cart, error) {
+func (c *cartRepositoryImpl) CreateCart(ctx context.Context, cart *entity.Cart, now time.Time) (*entity.Cart, error) {
 	if cart == nil {
 		return nil, fmt.Errorf("cart is nil")
 	}
@@ -81,23 +85,14 @@ func (c *cartRepositoryImpl) CreateCart(ctx context.Context, cart *entity.Cart)
 			return fmt.Errorf("create cart menu: %w", err)
 		}
 
-		pay := 0.0
-		for _, v := range cart.CartMenu {
-			pay += v.UnitPrice * float64(v.Qty)
-		}
-
-		if err := tx.Model(cart).Update("amount", pay).Error; err != nil {
-			return err
-		}
-
-		return nil
+		return recomputeCart(tx, cart.ID, now)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if err := c.Db.WithContext(ctx).Preload("User").Preload("CartMenu").Preload("CartMenu.Menu").
+	if err := c.Db.WithContext(ctx).Preload("User").Preload("CartMenu").Preload("CartMenu.Menu").Preload("Discounts").
 		First(cart, cart.ID).Error; err != nil {
 		return nil, err
 	}
@@ -105,7 +100,7 @@ func (c *cartRepositoryImpl) CreateCart(ctx context.Context, cart *entity.Cart)
 	return cart, nil
 }
 
-func (c *cartRepositoryImpl) UpdateCart(ctx context.Context, cartID, menuID, userID uint, qty int) (*entity.Cart, error) {
+func (c *cartRepositoryImpl) UpdateCart(ctx context.Context, cartID, menuID, userID uint, qty int, now time.Time) (*entity.Cart, error) {
 	var result *entity.Cart
 
 	err := c.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -208,17 +203,12 @@ func (c *cartRepositoryImpl) UpdateCart(ctx context.Context, cartID, menuID, use
 			}
 		}
 
-		if err := tx.Preload("User").Preload("CartMenu").
-			Preload("CartMenu.Menu").First(&cart, cartID).Error; err != nil {
+		if err := recomputeCart(tx, cartID, now); err != nil {
 			return err
 		}
 
-		total := 0.0
-		for _, v := range cart.CartMenu {
-			total += v.UnitPrice * float64(v.Qty)
-		}
-
-		if err := tx.Model(&cart).Update("amount", total).Error; err != nil {
+		if err := tx.Preload("User").Preload("CartMenu").
+			Preload("CartMenu.Menu").Preload("Discounts").First(&cart, cartID).Error; err != nil {
 			return err
 		}
 
@@ -239,7 +229,7 @@ func (c *cartRepositoryImpl) UpdateCart(ctx context.Context, cartID, menuID, use
 
 func (c *cartRepositoryImpl) GetCartByUserID(ctx context.Context, userID uint) ([]*entity.Cart, error) {
 	var carts []*entity.Cart
-	if err := c.Db.WithContext(ctx).Preload("User").Preload("CartMenu").Preload("CartMenu.Menu").
+	if err := c.Db.WithContext(ctx).Preload("User").Preload("CartMenu").Preload("CartMenu.Menu").Preload("Discounts").
 		Where("user_id = ?", userID).Find(&carts).Error; err != nil {
 		return nil, err
 	}
@@ -249,7 +239,7 @@ func (c *cartRepositoryImpl) GetCartByUserID(ctx context.Context, userID uint) (
 
 func (c *cartRepositoryImpl) GetCartByID(ctx context.Context, cartID uint) (*entity.Cart, error) {
 	var cart entity.Cart
-	if err := c.Db.WithContext(ctx).Preload("User").Preload("CartMenu").Preload("CartMenu.Menu").
+	if err := c.Db.WithContext(ctx).Preload("User").Preload("CartMenu").Preload("CartMenu.Menu").Preload("Discounts").
 		First(&cart, cartID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, handling.ErrorIdNotFound
@@ -262,7 +252,7 @@ func (c *cartRepositoryImpl) GetCartByID(ctx context.Context, cartID uint) (*ent
 
 func (c *cartRepositoryImpl) GetAllCarts(ctx context.Context) ([]*entity.Cart, error) {
 	var carts []*entity.Cart
-	if err := c.Db.WithContext(ctx).Preload("User").Preload("CartMenu").Preload("CartMenu.Menu").
+	if err := c.Db.WithContext(ctx).Preload("User").Preload("CartMenu").Preload("CartMenu.Menu").Preload("Discounts").
 		Find(&carts).Error; err != nil {
 		return nil, err
 	}
@@ -270,12 +260,32 @@ func (c *cartRepositoryImpl) GetAllCarts(ctx context.Context) ([]*entity.Cart, e
 	return carts, nil
 }
 
-func (c *cartRepositoryImpl) CheckoutCart(ctx context.Context, cartID, userID uint) (*entity.Order, error) {
-	var order entity.Order
+// bookSlot takes a place in the slot of a pre-order, capacity 0 is unlimited.
+func bookSlot(tx *gorm.DB, start time.Time, capacity int) error {
+	slot := entity.ScheduleSlot{Start: start}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&slot).Error; err != nil {
+		return fmt.Errorf("create schedule slot: %w", err)
+	}
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&slot, "start = ?", start).Error; err != nil {
+		return fmt.Errorf("lock schedule slot: %w", err)
+	}
+
+	if capacity > 0 && slot.Booked >= capacity {
+		return handling.ErrSlotFull
+	}
+
+	return tx.Model(&slot).Update("booked", slot.Booked+1).Error
+}
+
+// CheckoutCart turns the cart into an order. An order with a slot is booked into it within slotCapacity
+// and waits to be released to the kitchen, any other order is released right away.
+func (c *cartRepositoryImpl) CheckoutCart(ctx context.Context, cartID, userID uint, order *entity.Order, slotCapacity int) (*entity.Order, error) {
 	err := c.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		var cart entity.Cart
-		if err := tx.Preload("CartMenu").Preload("CartMenu.Menu").First(&cart, cartID).Error; err != nil {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("User").
+			Where("user_id = ?", userID).First(&cart, cartID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return handling.ErrorIdNotFound
 			}
@@ -286,26 +296,75 @@ func (c *cartRepositoryImpl) CheckoutCart(ctx context.Context, cartID, userID ui
 			return handling.ErrCheckoutCart
 		}
 
+		//fee and discount were priced from these amounts, a cart edited in between has to be priced again
+		if cart.Subtotal != order.Subtotal || cart.Subtotal-cart.Amount != order.PromoDiscount {
+			return handling.ErrCartChanged
+		}
+
 		if err := tx.Model(&cart).UpdateColumn("status", constanta.Checkout).Error; err != nil {
 			return fmt.Errorf("update cart status: %w", err)
 		}
 
-		order = entity.Order{
-			CartID:    cartID,
-			UserID:    userID,
-			AmountPay: cart.Amount,
-			OrderDate: time.Now().UTC(),
-			Status:    constanta.Pending,
+		var items []entity.CartMenu
+		if err := tx.Preload("Menu").Where("cart_id = ?", cartID).Order("id").Find(&items).Error; err != nil {
+			return fmt.Errorf("find cart items: %w", err)
+		}
+
+		order.Items = make([]entity.OrderItem, 0, len(items))
+		for _, v := range items {
+			order.Items = append(order.Items, entity.OrderItem{
+				MenuID:    v.MenuID,
+				Name:      v.Menu.Name,
+				Category:  v.Menu.Category,
+				UnitPrice: v.UnitPrice,
+				Qty:       v.Qty,
+			})
+		}
+
+		var discounts []entity.CartDiscount
+		if err := tx.Where("cart_id = ?", cartID).Order("id").Find(&discounts).Error; err != nil {
+			return fmt.Errorf("find cart discounts: %w", err)
 		}
 
-		if err := tx.Create(&order).Error; err != nil {
+		//the cart may be priced again later, the order keeps the lines it was charged
+		order.Discounts = make([]entity.OrderDiscount, 0, len(discounts))
+		for _, v := range discounts {
+			order.Discounts = append(order.Discounts, entity.OrderDiscount{PromotionID: v.PromotionID, Name: v.Name, Amount: v.Amount})
+		}
+
+		order.CartID = cartID
+		order.UserID = userID
+		now := time.Now().UTC()
+		order.AmountPay = billing.Round(cart.Amount - order.Discount + order.ServiceCharge + order.Tax - order.TaxIncluded + order.DeliveryFee)
+		order.OrderDate = now
+		order.Status = constanta.Pending
+
+		if order.ScheduledFor != nil {
+			if err := bookSlot(tx, *order.ScheduledFor, slotCapacity); err != nil {
+				return err
+			}
+		} else {
+			order.ReleasedAt = &now
+		}
+
+		//users without a saved address still deliver to the one on their profile
+		if order.Delivery.Address == "" {
+			order.Delivery.Address = cart.User.Address
+		}
+
+		if err := tx.Omit("User", "Cart", "Courier").Create(order).Error; err != nil {
 			return fmt.Errorf("create order: %w", err)
 		}
 
-		if err := tx.Preload("User").Preload("Cart").
-			Preload("Cart.CartMenu").
-			Preload("Cart.CartMenu.Menu").
-			First(&order, order.ID).Error; err != nil {
+		if order.VoucherID != nil {
+			if err := redeemVoucher(tx, order, now); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Preload("User").Preload("Taxes").Preload("Discounts").Preload("Items").Preload("Cart").
+			Preload("Cart.Discounts").
+			First(order, order.ID).Error; err != nil {
 			return fmt.Errorf("preload order: %w", err)
 		}
 
@@ -316,5 +375,95 @@ func (c *cartRepositoryImpl) CheckoutCart(ctx context.Context, cartID, userID ui
 		return nil, err
 	}
 
-	return &order, nil
+	return order, nil
+}
+
+// Reprice runs the promotions again, a happy hour may have ended since the cart was last changed.
+func (c *cartRepositoryImpl) Reprice(ctx context.Context, cartID, userID uint, now time.Time) error {
+	return c.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cart entity.Cart
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&cart, cartID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrorIdNotFound
+			}
+			return err
+		}
+
+		if cart.Status == constanta.Checkout {
+			return nil
+		}
+
+		return recomputeCart(tx, cartID, now)
+	})
+}
+
+// recomputeCart prices the cart items, runs the promotion rules and stores the discount lines.
+// now has to be in the outlet's timezone, happy hours are read off its clock.
+func recomputeCart(tx *gorm.DB, cartID uint, now time.Time) error {
+	var items []entity.CartMenu
+	if err := tx.Preload("Menu").Where("cart_id = ?", cartID).Find(&items).Error; err != nil {
+		return fmt.Errorf("load cart items: %w", err)
+	}
+
+	var rules []*entity.Promotion
+	if err := tx.Where("active = ?", true).Order("id").Find(&rules).Error; err != nil {
+		return fmt.Errorf("load promotions: %w", err)
+	}
+
+	subtotal := 0.0
+	lines := make([]promotion.Line, 0, len(items))
+	for _, v := range items {
+		subtotal += v.UnitPrice * float64(v.Qty)
+		lines = append(lines, promotion.Line{
+			MenuID:    v.MenuID,
+			Category:  v.Menu.Category,
+			UnitPrice: v.UnitPrice,
+			Qty:       v.Qty,
+		})
+	}
+
+	if err := tx.Where("cart_id = ?", cartID).Delete(&entity.CartDiscount{}).Error; err != nil {
+		return fmt.Errorf("clear cart discounts: %w", err)
+	}
+
+	amount := subtotal
+	discounts := promotion.Apply(lines, rules, now)
+	for _, v := range discounts {
+		line := entity.CartDiscount{
+			CartID:      cartID,
+			PromotionID: v.PromotionID,
+			Name:        v.Name,
+			Amount:      v.Amount,
+		}
+
+		if err := tx.Create(&line).Error; err != nil {
+			return fmt.Errorf("create cart discount: %w", err)
+		}
+
+		amount -= v.Amount
+	}
+
+	return tx.Model(&entity.Cart{}).Where("id = ?", cartID).
+		Updates(map[string]interface{}{"subtotal": subtotal, "amount": amount}).Error
+}
+
+// FindOrderLines is what the user ordered in the order, with the menus as they are now. A deleted menu
+// is still loaded so the caller can tell it apart from one that never existed.
+func (c *cartRepositoryImpl) FindOrderLines(ctx context.Context, orderID, userID uint) ([]*entity.CartMenu, error) {
+	var order entity.Order
+	if err := c.Db.WithContext(ctx).Where("id = ? AND user_id = ?", orderID, userID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, err
+	}
+
+	var lines []*entity.CartMenu
+	if err := c.Db.WithContext(ctx).Preload("Menu", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("cart_id = ?", order.CartID).Order("id ASC").Find(&lines).Error; err != nil {
+		return nil, err
+	}
+
+	return lines, nil
 }
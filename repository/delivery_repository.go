@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DeliveryRepository interface {
+	FindLatestByOrderID(ctx context.Context, orderID uint) (*entity.Delivery, error)
+	FindActiveByCourierID(ctx context.Context, courierID uint) ([]*entity.Delivery, error)
+	Transition(ctx context.Context, delivery *entity.Delivery, from, orderFrom, orderTo string) error
+	SaveLocation(ctx context.Context, location *entity.CourierLocation) error
+	LatestLocation(ctx context.Context, courierID uint, since time.Time) (*entity.CourierLocation, error)
+	LatestLocations(ctx context.Context, since time.Time) ([]*entity.CourierLocation, error)
+}
+
+type deliveryRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewDeliveryRepositoryImpl(db *gorm.DB) *deliveryRepositoryImpl {
+	return &deliveryRepositoryImpl{
+		Db: db,
+	}
+}
+
+var activeDeliveries = []string{constanta.DeliveryAssigned, constanta.DeliveryPickedUp}
+
+// syncDelivery keeps the open delivery in step when the order status is changed directly.
+func syncDelivery(tx *gorm.DB, orderID uint, from, to string, now time.Time) error {
+	query := tx.Model(&entity.Delivery{}).Where("order_id = ? AND status IN ?", orderID, activeDeliveries)
+
+	switch {
+	case to == constanta.Delivering:
+		return query.Updates(map[string]interface{}{"status": constanta.DeliveryPickedUp, "picked_up_at": now}).Error
+	case to == constanta.Delivered:
+		return query.Updates(map[string]interface{}{
+			"status":       constanta.DeliveryDelivered,
+			"picked_up_at": gorm.Expr("COALESCE(picked_up_at, ?)", now),
+			"delivered_at": now,
+		}).Error
+	case to == constanta.Cancelled:
+		return query.Updates(map[string]interface{}{"status": constanta.DeliveryFailed, "failed_at": now}).Error
+	case from == constanta.Delivering && to == constanta.Ready:
+		//the food came back, the order waits for a new courier
+		if err := query.Updates(map[string]interface{}{"status": constanta.DeliveryFailed, "failed_at": now}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.Order{}).Where("id = ?", orderID).Update("courier_id", nil).Error
+	}
+
+	return nil
+}
+
+func (d *deliveryRepositoryImpl) FindLatestByOrderID(ctx context.Context, orderID uint) (*entity.Delivery, error) {
+	var delivery entity.Delivery
+	if err := d.Db.WithContext(ctx).Preload("Courier").Where("order_id = ?", orderID).
+		Order("id DESC").First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (d *deliveryRepositoryImpl) FindActiveByCourierID(ctx context.Context, courierID uint) ([]*entity.Delivery, error) {
+	var deliveries []*entity.Delivery
+	if err := d.Db.WithContext(ctx).Where("courier_id = ? AND status IN ?", courierID, activeDeliveries).
+		Order("assigned_at ASC").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Transition saves the delivery when it is still in from and moves its order from orderFrom to orderTo
+// in the same transaction, an empty orderTo leaves the order status alone. A failed delivery frees the order
+// for another courier.
+func (d *deliveryRepositoryImpl) Transition(ctx context.Context, delivery *entity.Delivery, from, orderFrom, orderTo string) error {
+	return d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Delivery{}).Where("id = ? AND status = ?", delivery.ID, from).
+			Select("Status", "PickedUpAt", "DeliveredAt", "FailedAt", "ProofNotes", "ProofPhoto").
+			Updates(delivery)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return handling.ErrInvalidDeliveryStatus
+		}
+
+		if orderTo != "" {
+			result = tx.Model(&entity.Order{}).Where("id = ? AND status = ?", delivery.OrderID, orderFrom).Update("status", orderTo)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if result.RowsAffected == 0 {
+				return handling.ErrInvalidOrderStatus
+			}
+		}
+
+		if delivery.Status == constanta.DeliveryFailed {
+			return tx.Model(&entity.Order{}).Where("id = ? AND courier_id = ?", delivery.OrderID, delivery.CourierID).
+				Update("courier_id", nil).Error
+		}
+
+		return nil
+	})
+}
+
+func (d *deliveryRepositoryImpl) SaveLocation(ctx context.Context, location *entity.CourierLocation) error {
+	return d.Db.WithContext(ctx).Create(location).Error
+}
+
+// LatestLocation is the newest fix of the courier recorded after since, nil when there is none.
+func (d *deliveryRepositoryImpl) LatestLocation(ctx context.Context, courierID uint, since time.Time) (*entity.CourierLocation, error) {
+	var locations []*entity.CourierLocation
+	if err := d.Db.WithContext(ctx).Where("courier_id = ? AND recorded_at >= ?", courierID, since).
+		Order("recorded_at DESC").Limit(1).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	if len(locations) == 0 {
+		return nil, nil
+	}
+
+	return locations[0], nil
+}
+
+// LatestLocations is the newest fix of every courier who sent one after since, ordered by courier.
+func (d *deliveryRepositoryImpl) LatestLocations(ctx context.Context, since time.Time) ([]*entity.CourierLocation, error) {
+	newest := d.Db.Model(&entity.CourierLocation{}).Select("courier_id, MAX(recorded_at) AS recorded_at").
+		Where("recorded_at >= ?", since).Group("courier_id")
+
+	var locations []*entity.CourierLocation
+	if err := d.Db.WithContext(ctx).Table("courier_locations AS cl").Select("cl.*").
+		Joins("JOIN (?) AS newest ON newest.courier_id = cl.courier_id AND newest.recorded_at = cl.recorded_at", newest).
+		Order("cl.courier_id ASC, cl.id DESC").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	//two fixes with the same time, the last one saved wins
+	latest := make([]*entity.CourierLocation, 0, len(locations))
+	for _, v := range locations {
+		if len(latest) > 0 && latest[len(latest)-1].CourierID == v.CourierID {
+			continue
+		}
+		latest = append(latest, v)
+	}
+
+	return latest, nil
+}
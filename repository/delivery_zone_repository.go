@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-food/entity"
+	"online-food/utils/handling"
+
+	"gorm.io/gorm"
+)
+
+type DeliveryZoneRepository interface {
+	Create(ctx context.Context, zone *entity.DeliveryZone) (*entity.DeliveryZone, error)
+	Update(ctx context.Context, zone *entity.DeliveryZone) (*entity.DeliveryZone, error)
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*entity.DeliveryZone, error)
+	FindAll(ctx context.Context) ([]*entity.DeliveryZone, error)
+	FindActive(ctx context.Context) ([]*entity.DeliveryZone, error)
+}
+
+type deliveryZoneRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewDeliveryZoneRepositoryImpl(db *gorm.DB) *deliveryZoneRepositoryImpl {
+	return &deliveryZoneRepositoryImpl{
+		Db: db,
+	}
+}
+
+func (d *deliveryZoneRepositoryImpl) Create(ctx context.Context, zone *entity.DeliveryZone) (*entity.DeliveryZone, error) {
+	if err := d.Db.WithContext(ctx).Create(zone).Error; err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (d *deliveryZoneRepositoryImpl) Update(ctx context.Context, zone *entity.DeliveryZone) (*entity.DeliveryZone, error) {
+	result := d.Db.WithContext(ctx).Model(zone).
+		Select("Name", "Type", "RadiusKm", "Polygon", "Fee", "MinOrder", "Active").
+		Updates(zone)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return d.FindByID(ctx, zone.ID)
+}
+
+func (d *deliveryZoneRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	result := d.Db.WithContext(ctx).Delete(&entity.DeliveryZone{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return handling.ErrZoneNotFound
+	}
+
+	return nil
+}
+
+func (d *deliveryZoneRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.DeliveryZone, error) {
+	var zone entity.DeliveryZone
+	if err := d.Db.WithContext(ctx).First(&zone, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrZoneNotFound
+		}
+		return nil, err
+	}
+
+	return &zone, nil
+}
+
+func (d *deliveryZoneRepositoryImpl) FindAll(ctx context.Context) ([]*entity.DeliveryZone, error) {
+	var zones []*entity.DeliveryZone
+	if err := d.Db.WithContext(ctx).Order("fee, id").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+func (d *deliveryZoneRepositoryImpl) FindActive(ctx context.Context) ([]*entity.DeliveryZone, error) {
+	var zones []*entity.DeliveryZone
+	if err := d.Db.WithContext(ctx).Where("active = ?", true).Order("fee, id").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DispatchRepository interface {
+	FindWaitingOrders(ctx context.Context) ([]*entity.Order, error)
+	FindCouriers(ctx context.Context, ids []uint) ([]*entity.User, error)
+	CourierLoads(ctx context.Context, ids []uint) (map[uint]int, error)
+	LastAssigned(ctx context.Context, ids []uint) (map[uint]time.Time, error)
+	FindOrderOffers(ctx context.Context, orderID uint) ([]*entity.DeliveryOffer, error)
+	FindBusyCouriers(ctx context.Context) ([]uint, error)
+	CreateOffer(ctx context.Context, offer *entity.DeliveryOffer) error
+	ExpireOffers(ctx context.Context, now time.Time) (int64, error)
+	FindPendingOffers(ctx context.Context, courierID uint, now time.Time) ([]*entity.DeliveryOffer, error)
+	AcceptOffer(ctx context.Context, id, courierID uint, now time.Time) (*entity.DeliveryOffer, error)
+	DeclineOffer(ctx context.Context, id, courierID uint, now time.Time) (*entity.DeliveryOffer, error)
+}
+
+type dispatchRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewDispatchRepositoryImpl(db *gorm.DB) *dispatchRepositoryImpl {
+	return &dispatchRepositoryImpl{
+		Db: db,
+	}
+}
+
+// FindWaitingOrders is every ready order without a courier or an open offer, oldest first.
+func (d *dispatchRepositoryImpl) FindWaitingOrders(ctx context.Context) ([]*entity.Order, error) {
+	pending := d.Db.Model(&entity.DeliveryOffer{}).Select("order_id").Where("status = ?", constanta.OfferPending)
+
+	var orders []*entity.Order
+	if err := d.Db.WithContext(ctx).Where("status = ? AND courier_id IS NULL AND id NOT IN (?)", constanta.Ready, pending).
+		Order("order_date ASC, id ASC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// FindCouriers keeps the ids that belong to couriers who may work.
+func (d *dispatchRepositoryImpl) FindCouriers(ctx context.Context, ids []uint) ([]*entity.User, error) {
+	var users []*entity.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	if err := d.Db.WithContext(ctx).Where("id IN ? AND role = ? AND suspended_at IS NULL", ids, constanta.Courier).
+		Order("id ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (d *dispatchRepositoryImpl) CourierLoads(ctx context.Context, ids []uint) (map[uint]int, error) {
+	var rows []struct {
+		CourierID uint
+		Total     int
+	}
+
+	if err := d.Db.WithContext(ctx).Model(&entity.Delivery{}).Select("courier_id, COUNT(*) AS total").
+		Where("courier_id IN ? AND status IN ?", ids, activeDeliveries).Group("courier_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	loads := make(map[uint]int, len(rows))
+	for _, v := range rows {
+		loads[v.CourierID] = v.Total
+	}
+
+	return loads, nil
+}
+
+func (d *dispatchRepositoryImpl) LastAssigned(ctx context.Context, ids []uint) (map[uint]time.Time, error) {
+	var rows []struct {
+		CourierID  uint
+		AssignedAt time.Time
+	}
+
+	if err := d.Db.WithContext(ctx).Model(&entity.Delivery{}).Select("courier_id, MAX(assigned_at) AS assigned_at").
+		Where("courier_id IN ?", ids).Group("courier_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	assigned := make(map[uint]time.Time, len(rows))
+	for _, v := range rows {
+		assigned[v.CourierID] = v.AssignedAt
+	}
+
+	return assigned, nil
+}
+
+// FindOrderOffers is every offer made for the order, oldest first.
+func (d *dispatchRepositoryImpl) FindOrderOffers(ctx context.Context, orderID uint) ([]*entity.DeliveryOffer, error) {
+	var offers []*entity.DeliveryOffer
+	if err := d.Db.WithContext(ctx).Where("order_id = ?", orderID).Order("offered_at ASC, id ASC").Find(&offers).Error; err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// FindBusyCouriers is everyone holding an open offer, a courier answers one offer at a time.
+func (d *dispatchRepositoryImpl) FindBusyCouriers(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	if err := d.Db.WithContext(ctx).Model(&entity.DeliveryOffer{}).Distinct("courier_id").
+		Where("status = ?", constanta.OfferPending).Pluck("courier_id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// CreateOffer saves the offer only while the order still waits and the courier has no open offer, so two
+// dispatchers running at once can't offer the same order or courier twice.
+func (d *dispatchRepositoryImpl) CreateOffer(ctx context.Context, offer *entity.DeliveryOffer) error {
+	return d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, offer.OrderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrOfferClosed
+			}
+			return err
+		}
+
+		if order.Status != constanta.Ready || order.CourierID != nil {
+			return handling.ErrOfferClosed
+		}
+
+		var open int64
+		if err := tx.Model(&entity.DeliveryOffer{}).Where("status = ? AND (order_id = ? OR courier_id = ?)",
+			constanta.OfferPending, offer.OrderID, offer.CourierID).Count(&open).Error; err != nil {
+			return err
+		}
+
+		if open > 0 {
+			return handling.ErrOfferClosed
+		}
+
+		return tx.Create(offer).Error
+	})
+}
+
+func (d *dispatchRepositoryImpl) ExpireOffers(ctx context.Context, now time.Time) (int64, error) {
+	result := d.Db.WithContext(ctx).Model(&entity.DeliveryOffer{}).
+		Where("status = ? AND expires_at <= ?", constanta.OfferPending, now).
+		Updates(map[string]interface{}{"status": constanta.OfferExpired, "responded_at": now})
+	return result.RowsAffected, result.Error
+}
+
+func (d *dispatchRepositoryImpl) FindPendingOffers(ctx context.Context, courierID uint, now time.Time) ([]*entity.DeliveryOffer, error) {
+	var offers []*entity.DeliveryOffer
+	if err := d.Db.WithContext(ctx).Preload("Order").Where("courier_id = ? AND status = ? AND expires_at > ?", courierID, constanta.OfferPending, now).
+		Order("offered_at ASC").Find(&offers).Error; err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// respond locks an open offer of the courier, an offer past its time counts as closed even before it is swept.
+func respond(tx *gorm.DB, id, courierID uint, now time.Time) (*entity.DeliveryOffer, error) {
+	var offer entity.DeliveryOffer
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Order").Where("id = ? AND courier_id = ?", id, courierID).
+		First(&offer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrOfferNotFound
+		}
+		return nil, err
+	}
+
+	if offer.Status != constanta.OfferPending || !now.Before(offer.ExpiresAt) {
+		return nil, handling.ErrOfferClosed
+	}
+
+	return &offer, nil
+}
+
+// AcceptOffer assigns the courier in the same transaction, the order must still be ready and unassigned.
+func (d *dispatchRepositoryImpl) AcceptOffer(ctx context.Context, id, courierID uint, now time.Time) (*entity.DeliveryOffer, error) {
+	var offer *entity.DeliveryOffer
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if offer, err = respond(tx, id, courierID, now); err != nil {
+			return err
+		}
+
+		var order entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, offer.OrderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrOfferClosed
+			}
+			return err
+		}
+
+		if order.Status != constanta.Ready || order.CourierID != nil {
+			return handling.ErrOfferClosed
+		}
+
+		offer.Status = constanta.OfferAccepted
+		offer.RespondedAt = &now
+		if err := tx.Model(&entity.DeliveryOffer{}).Where("id = ?", offer.ID).
+			Updates(map[string]interface{}{"status": offer.Status, "responded_at": now}).Error; err != nil {
+			return err
+		}
+
+		return assignCourier(tx, &order, courierID, now)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+func (d *dispatchRepositoryImpl) DeclineOffer(ctx context.Context, id, courierID uint, now time.Time) (*entity.DeliveryOffer, error) {
+	var offer *entity.DeliveryOffer
+	err := d.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if offer, err = respond(tx, id, courierID, now); err != nil {
+			return err
+		}
+
+		offer.Status = constanta.OfferDeclined
+		offer.RespondedAt = &now
+		return tx.Model(&entity.DeliveryOffer{}).Where("id = ?", offer.ID).
+			Updates(map[string]interface{}{"status": offer.Status, "responded_at": now}).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeStatement is one exec the repository sent to the database.
+type fakeStatement struct {
+	query string
+	args  []driver.NamedValue
+}
+
+// fakeDB records every exec and answers queries with the rows of the first table whose name is in the query.
+// Every exec affects one row and inserts id 1, it is enough for the repositories that only check RowsAffected.
+type fakeDB struct {
+	tables map[string]fakeTable
+	execs  []fakeStatement
+}
+
+type fakeTable struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func openFakeDB(t *testing.T, tables map[string]fakeTable) (*gorm.DB, *fakeDB) {
+	t.Helper()
+
+	fake := &fakeDB{tables: tables}
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sql.OpenDB(fake), SkipInitializeWithVersion: true}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open fake db: %v", err)
+	}
+
+	return db, fake
+}
+
+// executed reports whether an exec containing every part was sent with want among its args, whatever its int type.
+func (f *fakeDB) executed(want driver.Value, parts ...string) bool {
+	for _, v := range f.execs {
+		matched := true
+		for _, part := range parts {
+			matched = matched && strings.Contains(v.query, part)
+		}
+
+		if !matched {
+			continue
+		}
+
+		for _, arg := range v.args {
+			if fmt.Sprint(arg.Value) == fmt.Sprint(want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *fakeDB) Connect(ctx context.Context) (driver.Conn, error) {
+	return f, nil
+}
+
+func (f *fakeDB) Driver() driver.Driver {
+	return nil
+}
+
+func (f *fakeDB) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake db: prepared statements are not supported")
+}
+
+func (f *fakeDB) Close() error {
+	return nil
+}
+
+func (f *fakeDB) Begin() (driver.Tx, error) {
+	return f, nil
+}
+
+func (f *fakeDB) Commit() error {
+	return nil
+}
+
+func (f *fakeDB) Rollback() error {
+	return nil
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	f.execs = append(f.execs, fakeStatement{query: query, args: args})
+	return fakeResult{}, nil
+}
+
+type fakeResult struct{}
+
+func (fakeResult) LastInsertId() (int64, error) {
+	return 1, nil
+}
+
+func (fakeResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	for name, table := range f.tables {
+		if strings.Contains(query, "FROM `"+name+"`") {
+			return &fakeRows{columns: table.columns, rows: table.rows}, nil
+		}
+	}
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (f *fakeRows) Columns() []string {
+	return f.columns
+}
+
+func (f *fakeRows) Close() error {
+	return nil
+}
+
+func (f *fakeRows) Next(dest []driver.Value) error {
+	if len(f.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, f.rows[0])
+	f.rows = f.rows[1:]
+	return nil
+}
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-food/entity"
+	"online-food/utils/handling"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FavoriteRepository interface {
+	AddFavorite(ctx context.Context, userID, menuID uint) error
+	RemoveFavorite(ctx context.Context, userID, menuID uint) error
+	FindFavorites(ctx context.Context, userID uint) ([]*entity.Favorite, error)
+	FavoriteMenuIDs(ctx context.Context, userID uint, menuIDs []uint) (map[uint]bool, error)
+	CreateList(ctx context.Context, list *entity.SavedList) (*entity.SavedList, error)
+	UpdateList(ctx context.Context, list *entity.SavedList) (*entity.SavedList, error)
+	DeleteList(ctx context.Context, userID, id uint) error
+	FindList(ctx context.Context, userID, id uint) (*entity.SavedList, error)
+	FindLists(ctx context.Context, userID uint) ([]*entity.SavedList, error)
+}
+
+type favoriteRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewFavoriteRepositoryImpl(db *gorm.DB) *favoriteRepositoryImpl {
+	return &favoriteRepositoryImpl{
+		Db: db,
+	}
+}
+
+// AddFavorite stars a menu, starring it again changes nothing.
+func (f *favoriteRepositoryImpl) AddFavorite(ctx context.Context, userID, menuID uint) error {
+	return f.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findMenus(tx, []uint{menuID}); err != nil {
+			return err
+		}
+
+		favorite := entity.Favorite{UserID: userID, MenuID: menuID}
+		return tx.Omit("Menu").Clauses(clause.OnConflict{DoNothing: true}).Create(&favorite).Error
+	})
+}
+
+func (f *favoriteRepositoryImpl) RemoveFavorite(ctx context.Context, userID, menuID uint) error {
+	result := f.Db.WithContext(ctx).Where("user_id = ? AND menu_id = ?", userID, menuID).Delete(&entity.Favorite{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return handling.ErrFavoriteNotFound
+	}
+
+	return nil
+}
+
+// FindFavorites leaves out menus that were deleted since, newest first.
+func (f *favoriteRepositoryImpl) FindFavorites(ctx context.Context, userID uint) ([]*entity.Favorite, error) {
+	menus := f.Db.Model(&entity.Menu{}).Select("id")
+
+	var favorites []*entity.Favorite
+	if err := f.Db.WithContext(ctx).Preload("Menu").Where("user_id = ? AND menu_id IN (?)", userID, menus).
+		Order("created_at DESC, menu_id DESC").Find(&favorites).Error; err != nil {
+		return nil, err
+	}
+
+	return favorites, nil
+}
+
+// FavoriteMenuIDs tells which of the menus the user starred.
+func (f *favoriteRepositoryImpl) FavoriteMenuIDs(ctx context.Context, userID uint, menuIDs []uint) (map[uint]bool, error) {
+	starred := map[uint]bool{}
+	if len(menuIDs) == 0 {
+		return starred, nil
+	}
+
+	var ids []uint
+	if err := f.Db.WithContext(ctx).Model(&entity.Favorite{}).Where("user_id = ? AND menu_id IN ?", userID, menuIDs).
+		Pluck("menu_id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	for _, v := range ids {
+		starred[v] = true
+	}
+
+	return starred, nil
+}
+
+func listMenuIDs(list *entity.SavedList) []uint {
+	ids := make([]uint, 0, len(list.Items))
+	for _, v := range list.Items {
+		ids = append(ids, v.MenuID)
+	}
+	return ids
+}
+
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+func (f *favoriteRepositoryImpl) CreateList(ctx context.Context, list *entity.SavedList) (*entity.SavedList, error) {
+	err := f.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := findMenus(tx, listMenuIDs(list)); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Items.Menu").Create(list).Error; err != nil {
+			if isDuplicate(err) {
+				return handling.ErrListExist
+			}
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f.FindList(ctx, list.UserID, list.ID)
+}
+
+// UpdateList renames the list and replaces its items.
+func (f *favoriteRepositoryImpl) UpdateList(ctx context.Context, list *entity.SavedList) (*entity.SavedList, error) {
+	err := f.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entity.SavedList
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", list.UserID).First(&current, list.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrListNotFound
+			}
+			return err
+		}
+
+		if _, err := findMenus(tx, listMenuIDs(list)); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&current).Update("name", list.Name).Error; err != nil {
+			if isDuplicate(err) {
+				return handling.ErrListExist
+			}
+			return err
+		}
+
+		if err := tx.Where("saved_list_id = ?", list.ID).Delete(&entity.SavedListItem{}).Error; err != nil {
+			return err
+		}
+
+		for i := range list.Items {
+			list.Items[i].SavedListID = list.ID
+		}
+
+		return tx.Omit("Menu").Create(&list.Items).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return f.FindList(ctx, list.UserID, list.ID)
+}
+
+func (f *favoriteRepositoryImpl) DeleteList(ctx context.Context, userID, id uint) error {
+	return f.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var list entity.SavedList
+		if err := tx.Where("user_id = ?", userID).First(&list, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrListNotFound
+			}
+			return err
+		}
+
+		if err := tx.Where("saved_list_id = ?", list.ID).Delete(&entity.SavedListItem{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&list).Error
+	})
+}
+
+// FindList loads deleted menus too, so the list shows what can no longer be ordered.
+func (f *favoriteRepositoryImpl) FindList(ctx context.Context, userID, id uint) (*entity.SavedList, error) {
+	var list entity.SavedList
+	if err := f.Db.WithContext(ctx).Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Items.Menu", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("user_id = ?", userID).First(&list, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrListNotFound
+		}
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (f *favoriteRepositoryImpl) FindLists(ctx context.Context, userID uint) ([]*entity.SavedList, error) {
+	var lists []*entity.SavedList
+	if err := f.Db.WithContext(ctx).Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Items.Menu", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("user_id = ?", userID).Order("name ASC").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
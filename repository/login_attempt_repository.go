@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type LoginAttemptRepository interface {
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	AddFailure(ctx context.Context, key string, window time.Duration) (int64, error)
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	Clear(ctx context.Context, key string) error
+}
+
+type loginAttemptRepositoryImpl struct {
+	Rdb *redis.Client
+}
+
+func NewLoginAttemptRepositoryImpl(rdb *redis.Client) *loginAttemptRepositoryImpl {
+	return &loginAttemptRepositoryImpl{
+		Rdb: rdb,
+	}
+}
+
+func failKey(key string) string {
+	return "login:fail:" + key
+}
+
+func lockKey(key string) string {
+	return "login:lock:" + key
+}
+
+func (l *loginAttemptRepositoryImpl) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := l.Rdb.PTTL(ctx, lockKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	//negative ttl means the key does not exist or never expires
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (l *loginAttemptRepositoryImpl) AddFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	count, err := l.Rdb.Incr(ctx, failKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if count == 1 {
+		if err := l.Rdb.Expire(ctx, failKey(key), window).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+func (l *loginAttemptRepositoryImpl) Lock(ctx context.Context, key string, duration time.Duration) error {
+	return l.Rdb.Set(ctx, lockKey(key), 1, duration).Err()
+}
+
+func (l *loginAttemptRepositoryImpl) Clear(ctx context.Context, key string) error {
+	return l.Rdb.Del(ctx, failKey(key), lockKey(key)).Err()
+}
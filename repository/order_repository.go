@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/invoice"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderRepository interface {
+	FindByID(ctx context.Context, id uint) (*entity.Order, error)
+	FindAll(ctx context.Context) ([]*entity.Order, error)
+	FindByUserID(ctx context.Context, userID uint) ([]*entity.Order, error)
+	FindByStatus(ctx context.Context, statuses ...string) ([]*entity.Order, error)
+	FindByCourierID(ctx context.Context, courierID uint, statuses ...string) ([]*entity.Order, error)
+	FindKitchenQueue(ctx context.Context) ([]*entity.Order, error)
+	ReleaseDue(ctx context.Context, until, now time.Time) ([]uint, error)
+	UpdateStatus(ctx context.Context, id uint, from, to string) (*entity.Order, error)
+	AssignCourier(ctx context.Context, id, courierID uint) (*entity.Order, error)
+	AssignInvoiceNo(ctx context.Context, id uint, now time.Time) (*entity.Order, error)
+	SetItemsReady(ctx context.Context, id uint, itemID *uint, readyAt *time.Time) ([]uint, error)
+}
+
+type orderRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewOrderRepositoryImpl(db *gorm.DB) *orderRepositoryImpl {
+	return &orderRepositoryImpl{
+		Db: db,
+	}
+}
+
+func (o *orderRepositoryImpl) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").Preload("Taxes").Preload("Discounts").Preload("Items").Preload("Cart").Preload("Cart.Discounts")
+}
+
+func (o *orderRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.Order, error) {
+	var order entity.Order
+	if err := o.preload(o.Db.WithContext(ctx)).First(&order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (o *orderRepositoryImpl) FindAll(ctx context.Context) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	if err := o.preload(o.Db.WithContext(ctx)).Order("order_date DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (o *orderRepositoryImpl) FindByUserID(ctx context.Context, userID uint) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	if err := o.preload(o.Db.WithContext(ctx)).Where("user_id = ?", userID).
+		Order("order_date DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (o *orderRepositoryImpl) FindByStatus(ctx context.Context, statuses ...string) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	if err := o.preload(o.Db.WithContext(ctx)).Where("status IN ?", statuses).
+		Order("order_date ASC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (o *orderRepositoryImpl) FindByCourierID(ctx context.Context, courierID uint, statuses ...string) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	if err := o.preload(o.Db.WithContext(ctx)).Where("courier_id = ? AND status IN ?", courierID, statuses).
+		Order("order_date ASC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// FindKitchenQueue is every paid or preparing order released to the kitchen, by the time it is wanted.
+func (o *orderRepositoryImpl) FindKitchenQueue(ctx context.Context) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	if err := o.preload(o.Db.WithContext(ctx)).Where("status IN ? AND released_at IS NOT NULL", []string{constanta.Paid, constanta.Preparing}).
+		Order("COALESCE(scheduled_for, order_date) ASC, id ASC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// ReleaseDue releases scheduled orders whose slot starts by until and returns the ids this call released,
+// an order released by another instance in between is left out.
+func (o *orderRepositoryImpl) ReleaseDue(ctx context.Context, until, now time.Time) ([]uint, error) {
+	var due []uint
+	if err := o.Db.WithContext(ctx).Model(&entity.Order{}).
+		Where("released_at IS NULL AND scheduled_for <= ? AND status <> ?", until, constanta.Cancelled).
+		Order("scheduled_for ASC, id ASC").Pluck("id", &due).Error; err != nil {
+		return nil, err
+	}
+
+	released := make([]uint, 0, len(due))
+	for _, id := range due {
+		result := o.Db.WithContext(ctx).Model(&entity.Order{}).Where("id = ? AND released_at IS NULL", id).Update("released_at", now)
+		if result.Error != nil {
+			return released, result.Error
+		}
+
+		if result.RowsAffected > 0 {
+			released = append(released, id)
+		}
+	}
+
+	return released, nil
+}
+
+func (o *orderRepositoryImpl) UpdateStatus(ctx context.Context, id uint, from, to string) (*entity.Order, error) {
+	err := o.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		//only moves the order when nobody changed it in between
+		result := tx.Model(&entity.Order{}).
+			Where("id = ? AND status = ?", id, from).
+			Update("status", to)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return handling.ErrInvalidOrderStatus
+		}
+
+		//a cancelled pre-order gives its place in the slot back
+		if to == constanta.Cancelled {
+			if err := tx.Exec("UPDATE schedule_slots s JOIN orders o ON o.scheduled_for = s.start "+
+				"SET s.booked = s.booked - 1 WHERE o.id = ? AND s.booked > 0", id).Error; err != nil {
+				return fmt.Errorf("free schedule slot: %w", err)
+			}
+
+			if err := releaseVoucher(tx, id); err != nil {
+				return fmt.Errorf("release voucher: %w", err)
+			}
+		}
+
+		return syncDelivery(tx, id, from, to, time.Now())
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.FindByID(ctx, id)
+}
+
+// assignCourier opens a delivery for the courier on an order locked by the caller. An attempt that wasn't
+// picked up yet is failed and replaced, one already on the road has to be finished or failed by its courier first.
+// Offers still open for the order are withdrawn.
+func assignCourier(tx *gorm.DB, order *entity.Order, courierID uint, now time.Time) error {
+	var active entity.Delivery
+	err := tx.Where("order_id = ? AND status IN ?", order.ID, activeDeliveries).Order("id DESC").First(&active).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err == nil {
+		if active.Status == constanta.DeliveryPickedUp {
+			return handling.ErrDeliveryInProgress
+		}
+
+		if active.CourierID == courierID {
+			return nil
+		}
+
+		if err := tx.Model(&active).Updates(map[string]interface{}{
+			"status":      constanta.DeliveryFailed,
+			"failed_at":   now,
+			"proof_notes": "reassigned to another courier",
+		}).Error; err != nil {
+			return fmt.Errorf("fail previous delivery: %w", err)
+		}
+	}
+
+	if err := tx.Model(&entity.DeliveryOffer{}).Where("order_id = ? AND status = ?", order.ID, constanta.OfferPending).
+		Updates(map[string]interface{}{"status": constanta.OfferWithdrawn, "responded_at": now}).Error; err != nil {
+		return fmt.Errorf("withdraw offers: %w", err)
+	}
+
+	if err := tx.Model(order).Update("courier_id", courierID).Error; err != nil {
+		return err
+	}
+
+	delivery := entity.Delivery{
+		OrderID:    order.ID,
+		CourierID:  courierID,
+		Status:     constanta.DeliveryAssigned,
+		AssignedAt: now,
+	}
+	return tx.Create(&delivery).Error
+}
+
+func (o *orderRepositoryImpl) AssignCourier(ctx context.Context, id, courierID uint) (*entity.Order, error) {
+	err := o.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrorIdNotFound
+			}
+			return err
+		}
+
+		return assignCourier(tx, &order, courierID, time.Now())
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.FindByID(ctx, id)
+}
+
+// AssignInvoiceNo gives a paid order the next number of the year on its first invoice, later calls keep that number.
+// Orders only get a number once paid, so an order cancelled before payment never leaves a gap.
+func (o *orderRepositoryImpl) AssignInvoiceNo(ctx context.Context, id uint, now time.Time) (*entity.Order, error) {
+	err := o.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrorIdNotFound
+			}
+			return err
+		}
+
+		if order.InvoiceNo != nil {
+			return nil
+		}
+
+		if !invoice.Numbered(order.Status) {
+			return handling.ErrInvoiceUnavailable
+		}
+
+		sequence := entity.InvoiceSequence{Year: now.Year()}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sequence).Error; err != nil {
+			return fmt.Errorf("create invoice sequence: %w", err)
+		}
+
+		//the row lock keeps two invoices from taking the same number
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sequence, "year = ?", now.Year()).Error; err != nil {
+			return fmt.Errorf("lock invoice sequence: %w", err)
+		}
+
+		sequence.Last++
+		if err := tx.Model(&sequence).Update("last", sequence.Last).Error; err != nil {
+			return fmt.Errorf("update invoice sequence: %w", err)
+		}
+
+		number := invoice.Number(sequence.Year, sequence.Last)
+		return tx.Model(&order).Updates(map[string]interface{}{"invoice_no": number, "invoiced_at": now}).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.FindByID(ctx, id)
+}
+
+// SetItemsReady bumps the items of an order, or recalls them when readyAt is nil. Without an item id every
+// item is changed, items already in that state are left alone and the ids that changed are returned.
+func (o *orderRepositoryImpl) SetItemsReady(ctx context.Context, id uint, itemID *uint, readyAt *time.Time) ([]uint, error) {
+	var changed []uint
+	err := o.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order entity.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrorIdNotFound
+			}
+			return err
+		}
+
+		query := tx.Model(&entity.OrderItem{}).Where("order_id = ?", id)
+		if itemID != nil {
+			var count int64
+			if err := tx.Model(&entity.OrderItem{}).Where("order_id = ? AND id = ?", id, *itemID).Count(&count).Error; err != nil {
+				return err
+			}
+
+			if count == 0 {
+				return handling.ErrOrderItemNotFound
+			}
+
+			query = query.Where("id = ?", *itemID)
+		}
+
+		if readyAt != nil {
+			query = query.Where("ready_at IS NULL")
+		} else {
+			query = query.Where("ready_at IS NOT NULL")
+		}
+
+		if err := query.Pluck("id", &changed).Error; err != nil {
+			return err
+		}
+
+		if len(changed) == 0 {
+			return nil
+		}
+
+		return tx.Model(&entity.OrderItem{}).Where("id IN ?", changed).Update("ready_at", readyAt).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"online-food/utils/constanta"
+	"testing"
+)
+
+func TestUpdateStatusReleasesVoucher(t *testing.T) {
+	order := fakeTable{columns: []string{"id", "status"}, rows: [][]driver.Value{{int64(7), constanta.Cancelled}}}
+	redemption := fakeTable{columns: []string{"id", "voucher_id", "user_id", "order_id"}, rows: [][]driver.Value{{int64(3), int64(9), int64(1), int64(7)}}}
+
+	tests := []struct {
+		name         string
+		from, to     string
+		redeemed     bool
+		wantReleased bool
+	}{
+		{"cancelled with a voucher", constanta.Paid, constanta.Cancelled, true, true},
+		{"cancelled without a voucher", constanta.Pending, constanta.Cancelled, false, false},
+		{"not cancelled", constanta.Paid, constanta.Preparing, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables := map[string]fakeTable{"orders": order}
+			if tt.redeemed {
+				tables["voucher_redemptions"] = redemption
+			}
+
+			db, fake := openFakeDB(t, tables)
+			if _, err := NewOrderRepositoryImpl(db).UpdateStatus(context.Background(), 7, tt.from, tt.to); err != nil {
+				t.Fatalf("update status: %v", err)
+			}
+
+			deleted := fake.executed(3, "DELETE FROM `voucher_redemptions`")
+			decremented := fake.executed(9, "UPDATE `vouchers`", "used_count - 1", "used_count > 0")
+
+			if deleted != tt.wantReleased || decremented != tt.wantReleased {
+				t.Fatalf("redemption deleted %v, used count decremented %v, want %v", deleted, decremented, tt.wantReleased)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/entity"
+	"online-food/utils/handling"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset *entity.PasswordReset) (*entity.PasswordReset, error)
+	ResetPassword(ctx context.Context, tokenHash, password string, now time.Time) error
+}
+
+type passwordResetRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewPasswordResetRepositoryImpl(db *gorm.DB) *passwordResetRepositoryImpl {
+	return &passwordResetRepositoryImpl{
+		Db: db,
+	}
+}
+
+func (p *passwordResetRepositoryImpl) Create(ctx context.Context, reset *entity.PasswordReset) (*entity.PasswordReset, error) {
+	err := p.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		//only the latest link stays usable
+		if err := tx.Model(&entity.PasswordReset{}).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", reset.CreatedAt).Error; err != nil {
+			return fmt.Errorf("expire old reset: %w", err)
+		}
+
+		if err := tx.Create(reset).Error; err != nil {
+			return fmt.Errorf("create reset: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reset, nil
+}
+
+func (p *passwordResetRepositoryImpl) ResetPassword(ctx context.Context, tokenHash, password string, now time.Time) error {
+	return p.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reset entity.PasswordReset
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			Take(&reset).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrInvalidResetToken
+			}
+			return fmt.Errorf("find reset: %w", err)
+		}
+
+		used := tx.Model(&entity.PasswordReset{}).Where("id = ? AND used_at IS NULL", reset.ID).Update("used_at", now)
+		if used.Error != nil {
+			return fmt.Errorf("mark reset used: %w", used.Error)
+		}
+
+		if used.RowsAffected == 0 {
+			return handling.ErrInvalidResetToken
+		}
+
+		//bumping session version revokes every token issued before the reset
+		update := map[string]interface{}{
+			"password":          password,
+			"session_version":   gorm.Expr("session_version + 1"),
+			"email_verified_at": gorm.Expr("COALESCE(email_verified_at, ?)", now),
+		}
+
+		result := tx.Model(&entity.User{}).Where("id = ?", reset.UserID).Updates(update)
+		if result.Error != nil {
+			return fmt.Errorf("update password: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return handling.ErrInvalidResetToken
+		}
+
+		return nil
+	})
+}
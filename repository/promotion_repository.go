@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-food/entity"
+	"online-food/utils/handling"
+
+	"gorm.io/gorm"
+)
+
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error)
+	Update(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error)
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*entity.Promotion, error)
+	FindAll(ctx context.Context) ([]*entity.Promotion, error)
+}
+
+type promotionRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewPromotionRepositoryImpl(db *gorm.DB) *promotionRepositoryImpl {
+	return &promotionRepositoryImpl{
+		Db: db,
+	}
+}
+
+func findPromotionMenu(tx *gorm.DB, menuID *uint) error {
+	if menuID == nil {
+		return nil
+	}
+
+	var menu entity.Menu
+	if err := tx.First(&menu, *menuID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handling.ErrMenuNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (p *promotionRepositoryImpl) Create(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error) {
+	db := p.Db.WithContext(ctx)
+	if err := findPromotionMenu(db, promotion.MenuID); err != nil {
+		return nil, err
+	}
+
+	if err := db.Create(promotion).Error; err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (p *promotionRepositoryImpl) Update(ctx context.Context, promotion *entity.Promotion) (*entity.Promotion, error) {
+	db := p.Db.WithContext(ctx)
+	if err := findPromotionMenu(db, promotion.MenuID); err != nil {
+		return nil, err
+	}
+
+	result := db.Model(promotion).
+		Select("Name", "Type", "MenuID", "Category", "BuyQty", "FreeQty", "Percent", "HourStart", "HourEnd",
+			"SpendAmount", "SaveAmount", "StartsAt", "EndsAt", "Active").
+		Updates(promotion)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return p.FindByID(ctx, promotion.ID)
+}
+
+func (p *promotionRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	result := p.Db.WithContext(ctx).Delete(&entity.Promotion{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return handling.ErrPromotionNotFound
+	}
+
+	return nil
+}
+
+func (p *promotionRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.Promotion, error) {
+	var promotion entity.Promotion
+	if err := p.Db.WithContext(ctx).First(&promotion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrPromotionNotFound
+		}
+		return nil, err
+	}
+
+	return &promotion, nil
+}
+
+func (p *promotionRepositoryImpl) FindAll(ctx context.Context) ([]*entity.Promotion, error) {
+	var promotions []*entity.Promotion
+	if err := p.Db.WithContext(ctx).Order("id").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"online-food/utils/recommend"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RecommendationRepository interface {
+	FindBaskets(ctx context.Context, since time.Time) ([]recommend.Line, error)
+	Replace(ctx context.Context, result recommend.Result) error
+	FindForUser(ctx context.Context, userID uint, limit int) ([]*entity.UserRecommendation, error)
+	FindRelated(ctx context.Context, menuID uint, limit int) ([]*entity.MenuPair, error)
+	FindBestSellers(ctx context.Context, limit int, exclude []uint) ([]*entity.MenuPopularity, error)
+}
+
+type recommendationRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewRecommendationRepositoryImpl(db *gorm.DB) *recommendationRepositoryImpl {
+	return &recommendationRepositoryImpl{
+		Db: db,
+	}
+}
+
+// FindBaskets is every line of the carts checked out since, cancelled orders don't count.
+func (r *recommendationRepositoryImpl) FindBaskets(ctx context.Context, since time.Time) ([]recommend.Line, error) {
+	var lines []recommend.Line
+	if err := r.Db.WithContext(ctx).Table("cart_menus cm").
+		Select("c.user_id, cm.cart_id, cm.menu_id, cm.qty").
+		Joins("JOIN carts c ON c.id = cm.cart_id").
+		Joins("JOIN orders o ON o.cart_id = c.id").
+		Where("cm.deleted_at IS NULL AND c.status = ? AND o.status <> ? AND o.order_date >= ?", constanta.Checkout, constanta.Cancelled, since).
+		Order("cm.cart_id, cm.menu_id").Scan(&lines).Error; err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// Replace swaps every precomputed table for the new result at once, readers see either the old or the new one.
+func (r *recommendationRepositoryImpl) Replace(ctx context.Context, result recommend.Result) error {
+	pairs := make([]entity.MenuPair, 0, len(result.Pairs))
+	for _, v := range result.Pairs {
+		pairs = append(pairs, entity.MenuPair{MenuID: v.MenuID, RelatedID: v.RelatedID, Count: v.Count, Score: v.Score})
+	}
+
+	users := make([]entity.UserRecommendation, 0, len(result.Users))
+	for _, v := range result.Users {
+		users = append(users, entity.UserRecommendation{UserID: v.UserID, MenuID: v.MenuID, Score: v.Score})
+	}
+
+	popular := make([]entity.MenuPopularity, 0, len(result.Popular))
+	for _, v := range result.Popular {
+		popular = append(popular, entity.MenuPopularity{MenuID: v.MenuID, Orders: v.Orders, Qty: v.Qty})
+	}
+
+	return r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&entity.MenuPair{}, &entity.UserRecommendation{}, &entity.MenuPopularity{}} {
+			if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(pairs) > 0 {
+			if err := tx.Omit("Related").CreateInBatches(pairs, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(users) > 0 {
+			if err := tx.Omit("Menu").CreateInBatches(users, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(popular) > 0 {
+			if err := tx.Omit("Menu").CreateInBatches(popular, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// orderable keeps menus that are neither deleted nor sold out.
+func (r *recommendationRepositoryImpl) orderable() *gorm.DB {
+	return r.Db.Model(&entity.Menu{}).Select("id").Where("stock > 0")
+}
+
+func (r *recommendationRepositoryImpl) FindForUser(ctx context.Context, userID uint, limit int) ([]*entity.UserRecommendation, error) {
+	var picks []*entity.UserRecommendation
+	if err := r.Db.WithContext(ctx).Preload("Menu").Where("user_id = ? AND menu_id IN (?)", userID, r.orderable()).
+		Order("score DESC, menu_id ASC").Limit(limit).Find(&picks).Error; err != nil {
+		return nil, err
+	}
+
+	return picks, nil
+}
+
+func (r *recommendationRepositoryImpl) FindRelated(ctx context.Context, menuID uint, limit int) ([]*entity.MenuPair, error) {
+	var pairs []*entity.MenuPair
+	if err := r.Db.WithContext(ctx).Preload("Related").Where("menu_id = ? AND related_id IN (?)", menuID, r.orderable()).
+		Order("score DESC, related_id ASC").Limit(limit).Find(&pairs).Error; err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
+
+func (r *recommendationRepositoryImpl) FindBestSellers(ctx context.Context, limit int, exclude []uint) ([]*entity.MenuPopularity, error) {
+	query := r.Db.WithContext(ctx).Preload("Menu").Where("menu_id IN (?)", r.orderable())
+	if len(exclude) > 0 {
+		query = query.Where("menu_id NOT IN ?", exclude)
+	}
+
+	var popular []*entity.MenuPopularity
+	if err := query.Order("orders DESC, qty DESC, menu_id ASC").Limit(limit).Find(&popular).Error; err != nil {
+		return nil, err
+	}
+
+	return popular, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// revenueStatuses are the orders that were paid for, pending and cancelled ones earn nothing.
+var revenueStatuses = []string{constanta.Paid, constanta.Preparing, constanta.Ready, constanta.Delivering, constanta.Delivered}
+
+type RevenueRow struct {
+	Bucket      string
+	Orders      int
+	Revenue     float64
+	Discount    float64
+	Tax         float64
+	DeliveryFee float64
+}
+
+type StatusRow struct {
+	Status string
+	Orders int
+}
+
+type MenuSalesRow struct {
+	MenuID  uint
+	Name    string
+	Qty     int
+	Revenue float64
+}
+
+type CustomerRow struct {
+	Orders       int
+	Revenue      float64
+	Customers    int
+	NewCustomers int
+}
+
+type ReportRepository interface {
+	Revenue(ctx context.Context, from, to time.Time, group, dbOffset, offset string) ([]RevenueRow, error)
+	OrderCounts(ctx context.Context, from, to time.Time) ([]StatusRow, error)
+	TopMenus(ctx context.Context, from, to time.Time, sort string, limit int) ([]MenuSalesRow, error)
+	Customers(ctx context.Context, from, to time.Time) (*CustomerRow, error)
+}
+
+type reportRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewReportRepositoryImpl(db *gorm.DB) *reportRepositoryImpl {
+	return &reportRepositoryImpl{
+		Db: db,
+	}
+}
+
+// bucket is the first day of the period an order falls in, read on the report's clock. Both offsets are
+// "+HH:MM" made by the service, order_date is stored in the server's zone.
+func bucket(group, dbOffset, offset string) string {
+	local := fmt.Sprintf("CONVERT_TZ(o.order_date, '%s', '%s')", dbOffset, offset)
+	switch group {
+	case constanta.ReportWeek:
+		return fmt.Sprintf("DATE_FORMAT(DATE_SUB(%s, INTERVAL WEEKDAY(%s) DAY), '%%Y-%%m-%%d')", local, local)
+	case constanta.ReportMonth:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-01')", local)
+	default:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%%Y-%%m-%%d')", local)
+	}
+}
+
+func (r *reportRepositoryImpl) Revenue(ctx context.Context, from, to time.Time, group, dbOffset, offset string) ([]RevenueRow, error) {
+	var rows []RevenueRow
+	if err := r.Db.WithContext(ctx).Model(&entity.Order{}).Table("orders o").
+		Select(bucket(group, dbOffset, offset)+" AS bucket, COUNT(*) AS orders, SUM(o.amount_pay) AS revenue, "+
+			"SUM(o.promo_discount + o.discount) AS discount, SUM(o.tax) AS tax, SUM(o.delivery_fee) AS delivery_fee").
+		Where("o.status IN ? AND o.order_date >= ? AND o.order_date < ?", revenueStatuses, from, to).
+		Group("bucket").Order("bucket").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *reportRepositoryImpl) OrderCounts(ctx context.Context, from, to time.Time) ([]StatusRow, error) {
+	var rows []StatusRow
+	if err := r.Db.WithContext(ctx).Model(&entity.Order{}).Select("status, COUNT(*) AS orders").
+		Where("order_date >= ? AND order_date < ?", from, to).Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// TopMenus ranks menus by qty or by revenue, revenue is the cart line before order discounts.
+func (r *reportRepositoryImpl) TopMenus(ctx context.Context, from, to time.Time, sort string, limit int) ([]MenuSalesRow, error) {
+	order := "qty DESC, revenue DESC"
+	if sort == "revenue" {
+		order = "revenue DESC, qty DESC"
+	}
+
+	var rows []MenuSalesRow
+	if err := r.Db.WithContext(ctx).Table("cart_menus cm").
+		Select("cm.menu_id, m.name, SUM(cm.qty) AS qty, SUM(cm.qty * cm.unit_price) AS revenue").
+		Joins("JOIN orders o ON o.cart_id = cm.cart_id").
+		Joins("JOIN menus m ON m.id = cm.menu_id").
+		Where("cm.deleted_at IS NULL AND o.deleted_at IS NULL AND o.status IN ? AND o.order_date >= ? AND o.order_date < ?", revenueStatuses, from, to).
+		Group("cm.menu_id, m.name").Order(order + ", cm.menu_id ASC").Limit(limit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Customers counts who ordered in the range, a customer is new when their first paid order ever is in it.
+func (r *reportRepositoryImpl) Customers(ctx context.Context, from, to time.Time) (*CustomerRow, error) {
+	first := r.Db.Model(&entity.Order{}).Select("user_id, MIN(order_date) AS first_order").
+		Where("status IN ?", revenueStatuses).Group("user_id")
+
+	var row CustomerRow
+	if err := r.Db.WithContext(ctx).Table("orders o").
+		Select("COUNT(*) AS orders, COALESCE(SUM(o.amount_pay), 0) AS revenue, COUNT(DISTINCT o.user_id) AS customers, "+
+			"COUNT(DISTINCT CASE WHEN f.first_order >= ? THEN o.user_id END) AS new_customers", from).
+		Joins("JOIN (?) f ON f.user_id = o.user_id", first).
+		Where("o.deleted_at IS NULL AND o.status IN ? AND o.order_date >= ? AND o.order_date < ?", revenueStatuses, from, to).
+		Scan(&row).Error; err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
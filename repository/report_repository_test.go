@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"online-food/utils/constanta"
+	"testing"
+)
+
+func TestBucket(t *testing.T) {
+	local := "CONVERT_TZ(o.order_date, '+00:00', '+07:00')"
+
+	tests := []struct {
+		group string
+		want  string
+	}{
+		{constanta.ReportDay, "DATE_FORMAT(" + local + ", '%Y-%m-%d')"},
+		{constanta.ReportWeek, "DATE_FORMAT(DATE_SUB(" + local + ", INTERVAL WEEKDAY(" + local + ") DAY), '%Y-%m-%d')"},
+		{constanta.ReportMonth, "DATE_FORMAT(" + local + ", '%Y-%m-01')"},
+	}
+
+	for _, tt := range tests {
+		if got := bucket(tt.group, "+00:00", "+07:00"); got != tt.want {
+			t.Errorf("bucket(%q) = %s, want %s", tt.group, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/entity"
+	"online-food/utils/handling"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	Create(ctx context.Context, role *entity.Role, permissions []string) (*entity.Role, error)
+	Update(ctx context.Context, role *entity.Role, permissions []string) (*entity.Role, error)
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*entity.Role, error)
+	FindByName(ctx context.Context, name string) (*entity.Role, error)
+	FindAll(ctx context.Context) ([]*entity.Role, error)
+	FindAllPermissions(ctx context.Context) ([]*entity.Permission, error)
+	PermissionsByRole(ctx context.Context, name string) ([]string, error)
+}
+
+type roleRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewRoleRepositoryImpl(db *gorm.DB) *roleRepositoryImpl {
+	return &roleRepositoryImpl{
+		Db: db,
+	}
+}
+
+func findPermissions(tx *gorm.DB, names []string) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	if err := tx.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, fmt.Errorf("find permissions: %w", err)
+	}
+
+	unique := map[string]bool{}
+	for _, v := range names {
+		unique[v] = true
+	}
+
+	if len(permissions) != len(unique) {
+		return nil, handling.ErrUnknownPermission
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepositoryImpl) Create(ctx context.Context, role *entity.Role, permissions []string) (*entity.Role, error) {
+	err := r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		perms, err := findPermissions(tx, permissions)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Permissions").Create(role).Error; err != nil {
+			var mysqlErr *mysql.MySQLError
+			if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+				return handling.ErrRoleExist
+			}
+			return fmt.Errorf("create role: %w", err)
+		}
+
+		if err := tx.Model(role).Association("Permissions").Replace(perms); err != nil {
+			return fmt.Errorf("assign permissions: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, role.ID)
+}
+
+func (r *roleRepositoryImpl) Update(ctx context.Context, role *entity.Role, permissions []string) (*entity.Role, error) {
+	err := r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Omit("Permissions").Update("description", role.Description).Error; err != nil {
+			return fmt.Errorf("update role: %w", err)
+		}
+
+		if permissions == nil {
+			return nil
+		}
+
+		perms, err := findPermissions(tx, permissions)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(role).Association("Permissions").Replace(perms); err != nil {
+			return fmt.Errorf("assign permissions: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindByID(ctx, role.ID)
+}
+
+func (r *roleRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	return r.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var role entity.Role
+		if err := tx.First(&role, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrRoleNotFound
+			}
+			return err
+		}
+
+		var users int64
+		if err := tx.Model(&entity.User{}).Where("role = ?", role.Name).Count(&users).Error; err != nil {
+			return fmt.Errorf("count users: %w", err)
+		}
+
+		if users > 0 {
+			return handling.ErrRoleInUse
+		}
+
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return fmt.Errorf("clear permissions: %w", err)
+		}
+
+		return tx.Delete(&role).Error
+	})
+}
+
+func (r *roleRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.Role, error) {
+	var role entity.Role
+	if err := r.Db.WithContext(ctx).Preload("Permissions").First(&role, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (r *roleRepositoryImpl) FindByName(ctx context.Context, name string) (*entity.Role, error) {
+	var role entity.Role
+	if err := r.Db.WithContext(ctx).Preload("Permissions").Where("name = ?", name).Take(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (r *roleRepositoryImpl) FindAll(ctx context.Context) ([]*entity.Role, error) {
+	var roles []*entity.Role
+	if err := r.Db.WithContext(ctx).Preload("Permissions").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepositoryImpl) FindAllPermissions(ctx context.Context) ([]*entity.Permission, error) {
+	var permissions []*entity.Permission
+	if err := r.Db.WithContext(ctx).Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepositoryImpl) PermissionsByRole(ctx context.Context, name string) ([]string, error) {
+	var permissions []string
+	if err := r.Db.WithContext(ctx).Model(&entity.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name = ?", name).
+		Pluck("permissions.name", &permissions).Error; err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"online-food/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ScheduleRepository interface {
+	FindSlots(ctx context.Context, from, to time.Time) ([]*entity.ScheduleSlot, error)
+}
+
+type scheduleRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewScheduleRepositoryImpl(db *gorm.DB) *scheduleRepositoryImpl {
+	return &scheduleRepositoryImpl{
+		Db: db,
+	}
+}
+
+// FindSlots is every slot starting in [from, to) that was booked at least once.
+func (s *scheduleRepositoryImpl) FindSlots(ctx context.Context, from, to time.Time) ([]*entity.ScheduleSlot, error) {
+	var slots []*entity.ScheduleSlot
+	if err := s.Db.WithContext(ctx).Where("start >= ? AND start < ?", from, to).Order("start ASC").Find(&slots).Error; err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-food/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SettingRepository interface {
+	Get(ctx context.Context, key, fallback string) (string, error)
+	Set(ctx context.Context, key, value string) error
+}
+
+type settingRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewSettingRepositoryImpl(db *gorm.DB) *settingRepositoryImpl {
+	return &settingRepositoryImpl{
+		Db: db,
+	}
+}
+
+func (s *settingRepositoryImpl) Get(ctx context.Context, key, fallback string) (string, error) {
+	var setting entity.Setting
+	if err := s.Db.WithContext(ctx).Where("`key` = ?", key).Take(&setting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fallback, nil
+		}
+		return "", err
+	}
+
+	return setting.Value, nil
+}
+
+func (s *settingRepositoryImpl) Set(ctx context.Context, key, value string) error {
+	setting := entity.Setting{
+		Key:   key,
+		Value: value,
+	}
+
+	return s.Db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&setting).Error
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"online-food/entity"
+	"online-food/utils/handling"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository interface {
+	SetSecret(ctx context.Context, userID uint, secret string) error
+	Enable(ctx context.Context, userID uint, step int64, codeHashes []string) error
+	Disable(ctx context.Context, userID uint) error
+	UseStep(ctx context.Context, userID uint, step int64) error
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string, now time.Time) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	RevokeUnenrolled(ctx context.Context, role string) error
+}
+
+type twoFactorRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewTwoFactorRepositoryImpl(db *gorm.DB) *twoFactorRepositoryImpl {
+	return &twoFactorRepositoryImpl{
+		Db: db,
+	}
+}
+
+func (t *twoFactorRepositoryImpl) SetSecret(ctx context.Context, userID uint, secret string) error {
+	result := t.Db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND two_factor = ?", userID, false).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_last_step": 0})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return handling.ErrTwoFactorEnabled
+	}
+
+	return nil
+}
+
+func (t *twoFactorRepositoryImpl) Enable(ctx context.Context, userID uint, step int64, codeHashes []string) error {
+	return t.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		//sessions from before two factor was turned on are revoked
+		result := tx.Model(&entity.User{}).
+			Where("id = ? AND two_factor = ? AND totp_last_step < ?", userID, false, step).
+			Updates(map[string]interface{}{
+				"two_factor":      true,
+				"totp_last_step":  step,
+				"session_version": gorm.Expr("session_version + 1"),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("enable two factor: %w", result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return handling.ErrInvalidTwoFactorCode
+		}
+
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func (t *twoFactorRepositoryImpl) Disable(ctx context.Context, userID uint) error {
+	return t.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"two_factor": false, "totp_secret": "", "totp_last_step": 0}).Error; err != nil {
+			return fmt.Errorf("disable two factor: %w", err)
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("delete recovery codes: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (t *twoFactorRepositoryImpl) UseStep(ctx context.Context, userID uint, step int64) error {
+	//conditional update so the same code can't be used twice, even by concurrent requests
+	result := t.Db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return handling.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (t *twoFactorRepositoryImpl) UseRecoveryCode(ctx context.Context, userID uint, codeHash string, now time.Time) error {
+	result := t.Db.WithContext(ctx).Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Limit(1).
+		Update("used_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return handling.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (t *twoFactorRepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	return t.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	codes := make([]entity.RecoveryCode, 0, len(codeHashes))
+	for _, v := range codeHashes {
+		codes = append(codes, entity.RecoveryCode{
+			UserID:   userID,
+			CodeHash: v,
+		})
+	}
+
+	if len(codes) == 0 {
+		return nil
+	}
+
+	if err := tx.Create(&codes).Error; err != nil {
+		return fmt.Errorf("create recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+func (t *twoFactorRepositoryImpl) RevokeUnenrolled(ctx context.Context, role string) error {
+	return t.Db.WithContext(ctx).Model(&entity.User{}).
+		Where("role = ? AND two_factor = ?", role, false).
+		Update("session_version", gorm.Expr("session_version + 1")).Error
+}
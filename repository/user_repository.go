@@ -5,9 +5,11 @@ import (
 	"errors"
 	"online-food/entity"
 	"online-food/utils/handling"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
@@ -17,6 +19,13 @@ type UserRepository interface {
 	FindByID(ctx context.Context, id uint) (*entity.User, error)
 	FindAll(ctx context.Context) ([]*entity.User, error)
 	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	VerifyEmail(ctx context.Context, id uint, email string, at time.Time) error
+	UpdateRole(ctx context.Context, id uint, role string) (*entity.User, error)
+	Suspend(ctx context.Context, id uint, reason string, at time.Time) (*entity.User, error)
+	Unsuspend(ctx context.Context, id uint) (*entity.User, error)
+	FindDeleted(ctx context.Context) ([]*entity.User, error)
+	Restore(ctx context.Context, id uint) (*entity.User, error)
+	Purge(ctx context.Context, id uint) error
 }
 
 type userRepositoryImpl struct {
@@ -125,3 +134,160 @@ func (u *userRepositoryImpl) FindByEmail(ctx context.Context, email string) (*en
 
 	return &user, nil
 }
+
+func (u *userRepositoryImpl) VerifyEmail(ctx context.Context, id uint, email string, at time.Time) error {
+	result := u.Db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND email = ? AND email_verified_at IS NULL", id, email).
+		Update("email_verified_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return handling.ErrInvalidVerifyToken
+	}
+
+	return nil
+}
+
+func (u *userRepositoryImpl) UpdateRole(ctx context.Context, id uint, role string) (*entity.User, error) {
+	result := u.Db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, handling.ErrorIdNotFound
+	}
+
+	return u.FindByID(ctx, id)
+}
+
+func (u *userRepositoryImpl) Suspend(ctx context.Context, id uint, reason string, at time.Time) (*entity.User, error) {
+	//bumping the session version also kills refresh tokens issued before the suspension
+	result := u.Db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND suspended_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"suspended_at":    at,
+			"suspend_reason":  reason,
+			"session_version": gorm.Expr("session_version + 1"),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	//suspending twice keeps the original reason and time
+	return u.FindByID(ctx, id)
+}
+
+func (u *userRepositoryImpl) Unsuspend(ctx context.Context, id uint) (*entity.User, error) {
+	result := u.Db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND suspended_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"suspended_at":   nil,
+			"suspend_reason": "",
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	user, err := u.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, handling.ErrUserNotSuspended
+	}
+
+	return user, nil
+}
+
+func (u *userRepositoryImpl) FindDeleted(ctx context.Context) ([]*entity.User, error) {
+	var user []*entity.User
+	if err := u.Db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Find(&user).Error; err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (u *userRepositoryImpl) Restore(ctx context.Context, id uint) (*entity.User, error) {
+	var user entity.User
+	if err := u.Db.WithContext(ctx).Unscoped().First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, err
+	}
+
+	if !user.DeletedAt.Valid {
+		return nil, handling.ErrUserNotDeleted
+	}
+
+	if err := u.Db.WithContext(ctx).Unscoped().Model(&user).Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+
+	return u.FindByID(ctx, id)
+}
+
+func (u *userRepositoryImpl) Purge(ctx context.Context, id uint) error {
+	return u.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return handling.ErrorIdNotFound
+			}
+			return err
+		}
+
+		//orders are financial records, they keep the user alive
+		var orders int64
+		if err := tx.Unscoped().Model(&entity.Order{}).Where("user_id = ?", id).Count(&orders).Error; err != nil {
+			return err
+		}
+
+		if orders > 0 {
+			return handling.ErrUserHasOrders
+		}
+
+		//so are the deliveries a courier made
+		var deliveries int64
+		if err := tx.Model(&entity.Delivery{}).Where("courier_id = ?", id).Count(&deliveries).Error; err != nil {
+			return err
+		}
+
+		if deliveries > 0 {
+			return handling.ErrUserHasOrders
+		}
+
+		for _, model := range []interface{}{&entity.CourierLocation{}, &entity.DeliveryOffer{}} {
+			if err := tx.Where("courier_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Unscoped().Model(&entity.Order{}).Where("courier_id = ?", id).Update("courier_id", nil).Error; err != nil {
+			return err
+		}
+
+		lists := tx.Model(&entity.SavedList{}).Select("id").Where("user_id = ?", id)
+		if err := tx.Where("saved_list_id IN (?)", lists).Delete(&entity.SavedListItem{}).Error; err != nil {
+			return err
+		}
+
+		carts := tx.Unscoped().Model(&entity.Cart{}).Select("id").Where("user_id = ?", id)
+		if err := tx.Unscoped().Where("cart_id IN (?)", carts).Delete(&entity.CartMenu{}).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&entity.Cart{}, &entity.Address{}, &entity.PasswordReset{}, &entity.RecoveryCode{}, &entity.Favorite{}, &entity.SavedList{}, &entity.UserRecommendation{}} {
+			if err := tx.Unscoped().Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Unscoped().Delete(&user).Error
+	})
+}
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"online-food/entity"
+	"online-food/utils/handling"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VoucherRepository interface {
+	Create(ctx context.Context, voucher *entity.Voucher, menuIDs []uint) (*entity.Voucher, error)
+	Update(ctx context.Context, voucher *entity.Voucher, menuIDs []uint) (*entity.Voucher, error)
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*entity.Voucher, error)
+	FindByCode(ctx context.Context, code string) (*entity.Voucher, error)
+	FindAll(ctx context.Context) ([]*entity.Voucher, error)
+	CountRedemptions(ctx context.Context, voucherID, userID uint) (int64, error)
+}
+
+type voucherRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func NewVoucherRepositoryImpl(db *gorm.DB) *voucherRepositoryImpl {
+	return &voucherRepositoryImpl{
+		Db: db,
+	}
+}
+
+func findMenus(tx *gorm.DB, ids []uint) ([]entity.Menu, error) {
+	menus := []entity.Menu{}
+	if len(ids) == 0 {
+		return menus, nil
+	}
+
+	unique := map[uint]bool{}
+	for _, v := range ids {
+		unique[v] = true
+	}
+
+	if err := tx.Where("id IN ?", ids).Find(&menus).Error; err != nil {
+		return nil, err
+	}
+
+	if len(menus) != len(unique) {
+		return nil, handling.ErrMenuNotFound
+	}
+
+	return menus, nil
+}
+
+// Create restores a deleted voucher with the same code instead of adding a new row, its redemptions
+// stay with it and keep counting against the limits.
+func (v *voucherRepositoryImpl) Create(ctx context.Context, voucher *entity.Voucher, menuIDs []uint) (*entity.Voucher, error) {
+	err := v.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		menus, err := findMenus(tx, menuIDs)
+		if err != nil {
+			return err
+		}
+
+		voucher.Menus = menus
+
+		var deleted entity.Voucher
+		err = tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("code = ? AND deleted_at IS NOT NULL", voucher.Code).Take(&deleted).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err == nil {
+			voucher.ID = deleted.ID
+			voucher.UsedCount = deleted.UsedCount
+			voucher.CreatedAt = deleted.CreatedAt
+			if err := tx.Unscoped().Omit("Menus").Save(voucher).Error; err != nil {
+				return err
+			}
+
+			return tx.Model(voucher).Association("Menus").Replace(menus)
+		}
+
+		if err := tx.Omit("Menus.*").Create(voucher).Error; err != nil {
+			var mysqlErr *mysql.MySQLError
+			if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+				return handling.ErrVoucherExist
+			}
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return voucher, nil
+}
+
+// Update replaces the menu list only when menuIDs is not nil.
+func (v *voucherRepositoryImpl) Update(ctx context.Context, voucher *entity.Voucher, menuIDs []uint) (*entity.Voucher, error) {
+	err := v.Db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(voucher).Omit("Menus").
+			Select("Code", "Description", "Type", "Value", "MinSpend", "MaxDiscount", "StartsAt", "EndsAt", "UsageLimit", "PerUserLimit", "Categories", "Active").
+			Updates(voucher).Error; err != nil {
+			var mysqlErr *mysql.MySQLError
+			if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+				return handling.ErrVoucherExist
+			}
+			return err
+		}
+
+		if menuIDs == nil {
+			return nil
+		}
+
+		menus, err := findMenus(tx, menuIDs)
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(voucher).Association("Menus").Replace(menus)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.FindByID(ctx, voucher.ID)
+}
+
+func (v *voucherRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	result := v.Db.WithContext(ctx).Delete(&entity.Voucher{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return handling.ErrVoucherNotFound
+	}
+
+	return nil
+}
+
+func (v *voucherRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+	if err := v.Db.WithContext(ctx).Preload("Menus").First(&voucher, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrVoucherNotFound
+		}
+		return nil, err
+	}
+
+	return &voucher, nil
+}
+
+func (v *voucherRepositoryImpl) FindByCode(ctx context.Context, code string) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+	if err := v.Db.WithContext(ctx).Preload("Menus").Where("code = ?", code).Take(&voucher).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, handling.ErrVoucherNotFound
+		}
+		return nil, err
+	}
+
+	return &voucher, nil
+}
+
+func (v *voucherRepositoryImpl) FindAll(ctx context.Context) ([]*entity.Voucher, error) {
+	var vouchers []*entity.Voucher
+	if err := v.Db.WithContext(ctx).Preload("Menus").Order("id DESC").Find(&vouchers).Error; err != nil {
+		return nil, err
+	}
+
+	return vouchers, nil
+}
+
+func (v *voucherRepositoryImpl) CountRedemptions(ctx context.Context, voucherID, userID uint) (int64, error) {
+	var count int64
+	if err := v.Db.WithContext(ctx).Model(&entity.VoucherRedemption{}).
+		Where("voucher_id = ? AND user_id = ?", voucherID, userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// redeemVoucher runs inside the checkout transaction. The voucher row lock serializes concurrent
+// checkouts, so the usage limits are checked against committed redemptions only.
+func redeemVoucher(tx *gorm.DB, order *entity.Order, now time.Time) error {
+	var voucher entity.Voucher
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&voucher, *order.VoucherID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return handling.ErrVoucherInvalid
+		}
+		return err
+	}
+
+	if !voucher.Running(now) {
+		return handling.ErrVoucherInvalid
+	}
+
+	if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+		return handling.ErrVoucherExhausted
+	}
+
+	if voucher.PerUserLimit > 0 {
+		var used int64
+		if err := tx.Model(&entity.VoucherRedemption{}).
+			Where("voucher_id = ? AND user_id = ?", voucher.ID, order.UserID).Count(&used).Error; err != nil {
+			return err
+		}
+
+		if used >= int64(voucher.PerUserLimit) {
+			return handling.ErrVoucherUserLimit
+		}
+	}
+
+	if err := tx.Model(&voucher).UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+		return err
+	}
+
+	redemption := entity.VoucherRedemption{
+		VoucherID: voucher.ID,
+		UserID:    order.UserID,
+		OrderID:   order.ID,
+		Discount:  order.Discount,
+	}
+
+	return tx.Omit("Voucher", "User", "Order").Create(&redemption).Error
+}
+
+// releaseVoucher runs inside the cancel transaction, the redemption of the order is removed so it no longer
+// counts against the usage limits. A voucher deleted in the meantime still gets its count back.
+func releaseVoucher(tx *gorm.DB, orderID uint) error {
+	var redemption entity.VoucherRedemption
+	if err := tx.Where("order_id = ?", orderID).Take(&redemption).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := tx.Delete(&redemption).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(&entity.Voucher{}).
+		Where("id = ? AND used_count > 0", redemption.VoucherID).
+		UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+}
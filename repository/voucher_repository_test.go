@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"online-food/entity"
+	"testing"
+	"time"
+)
+
+func TestCreateRestoresDeletedVoucher(t *testing.T) {
+	deletedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	deleted := fakeTable{
+		columns: []string{"id", "code", "used_count", "deleted_at"},
+		rows:    [][]driver.Value{{int64(4), "HEMAT10", int64(12), deletedAt}},
+	}
+
+	tests := []struct {
+		name         string
+		deleted      bool
+		wantID       uint
+		wantUsed     int
+		wantRestored bool
+	}{
+		{"new code", false, 1, 0, false},
+		{"code of a deleted voucher", true, 4, 12, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables := map[string]fakeTable{}
+			if tt.deleted {
+				tables["vouchers"] = deleted
+			}
+
+			db, fake := openFakeDB(t, tables)
+			voucher := &entity.Voucher{Code: "HEMAT10", Type: "fixed", Value: 10000, Active: true}
+			if _, err := NewVoucherRepositoryImpl(db).Create(context.Background(), voucher, nil); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+
+			if voucher.ID != tt.wantID || voucher.UsedCount != tt.wantUsed {
+				t.Fatalf("voucher id %d used %d, want %d used %d", voucher.ID, voucher.UsedCount, tt.wantID, tt.wantUsed)
+			}
+
+			restored := fake.executed(4, "UPDATE `vouchers`", "`deleted_at`=")
+			inserted := fake.executed("HEMAT10", "INSERT INTO `vouchers`")
+			if restored != tt.wantRestored || inserted == tt.wantRestored {
+				t.Fatalf("restored %v inserted %v, want restored %v", restored, inserted, tt.wantRestored)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AddressRouter(router *gin.Engine, auth gin.HandlerFunc, AddressHandler handler.AddressHandler) {
+	address := router.Group("/api/v1/users/me/addresses")
+	address.Use(auth)
+	{
+		address.GET("/", middleware.RequirePermission(constanta.PermProfileRead), AddressHandler.FindAll)
+		address.GET("/:addressId", middleware.RequirePermission(constanta.PermProfileRead), AddressHandler.FindByID)
+		address.POST("/", middleware.RequirePermission(constanta.PermProfileWrite), AddressHandler.Create)
+		address.PUT("/:addressId", middleware.RequirePermission(constanta.PermProfileWrite), AddressHandler.Update)
+		address.DELETE("/:addressId", middleware.RequirePermission(constanta.PermProfileWrite), AddressHandler.Delete)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func BillingRouter(router *gin.Engine, auth gin.HandlerFunc, BillingHandler handler.BillingHandler) {
+	billing := router.Group("/api/v1/settings/billing")
+	billing.Use(auth)
+	{
+		billing.GET("/", middleware.RequirePermission(constanta.PermSettingRead), BillingHandler.GetConfig)
+		billing.PUT("/", middleware.RequirePermission(constanta.PermSettingWrite), BillingHandler.UpdateConfig)
+	}
+}
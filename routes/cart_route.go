@@ -3,28 +3,30 @@ package routes
 import (
 	"online-food/handler"
 	"online-food/middleware"
+	"online-food/utils/constanta"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CartRouter(router *gin.Engine, CartHandler handler.CartHandler) {
+func CartRouter(router *gin.Engine, auth gin.HandlerFunc, CartHandler handler.CartHandler) {
 	cart := router.Group("/api/v1/")
-	cart.Use(middleware.Authentication())
+	cart.Use(auth)
 	{
 		cust := cart.Group("/carts")
-		cust.Use(middleware.RoleAccessMiddleware("customer", "admin"))
 		{
-			cust.POST("/", CartHandler.CreateCart)
-			cust.PUT("/:cartId", CartHandler.UpdateCart)
-			cust.GET("/users", CartHandler.GetCartByUserID)
-			cust.POST("/checkout/:cartId", CartHandler.CheckoutCart)
+			cust.POST("/", middleware.RequirePermission(constanta.PermCartWrite), CartHandler.CreateCart)
+			cust.PUT("/:cartId", middleware.RequirePermission(constanta.PermCartWrite), CartHandler.UpdateCart)
+			cust.GET("/users", middleware.RequirePermission(constanta.PermCartRead), CartHandler.GetCartByUserID)
+			cust.POST("/checkout/:cartId", middleware.RequirePermission(constanta.PermCartWrite), CartHandler.CheckoutCart)
 		}
 
+		cart.POST("/orders/:orderId/reorder", middleware.RequirePermission(constanta.PermCartWrite), CartHandler.Reorder)
+
 		admin := cart.Group("/carts")
-		admin.Use(middleware.RoleAccessMiddleware("admin"))
+		admin.Use(middleware.RequirePermission(constanta.PermCartReadAll))
 		{
-			cust.GET("/:cartId", CartHandler.GetCartByID)
-			cust.GET("/", CartHandler.GetAllCarts)
+			admin.GET("/:cartId", CartHandler.GetCartByID)
+			admin.GET("/", CartHandler.GetAllCarts)
 		}
 	}
 }
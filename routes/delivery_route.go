@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DeliveryRouter(router *gin.Engine, auth gin.HandlerFunc, DeliveryHandler handler.DeliveryHandler) {
+	delivery := router.Group("/api/v1")
+	delivery.Use(auth)
+	{
+		orders := delivery.Group("/orders/:orderId/delivery")
+		orders.Use(middleware.RequireAnyPermission(constanta.PermOrderRead, constanta.PermOrderReadAll))
+		{
+			orders.GET("/", DeliveryHandler.Track)
+			orders.GET("/photo", DeliveryHandler.Photo)
+		}
+
+		courier := delivery.Group("/courier")
+		courier.Use(middleware.RequirePermission(constanta.PermOrderDeliver))
+		{
+			courier.PUT("/orders/:orderId/delivery", DeliveryHandler.UpdateDelivery)
+			courier.PUT("/location", DeliveryHandler.UpdateLocation)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DeliveryZoneRouter(router *gin.Engine, auth gin.HandlerFunc, DeliveryZoneHandler handler.DeliveryZoneHandler) {
+	zone := router.Group("/api/v1/delivery-zones")
+	zone.Use(auth)
+	{
+		zone.GET("/", middleware.RequirePermission(constanta.PermZoneRead), DeliveryZoneHandler.FindAll)
+		zone.GET("/:zoneId", middleware.RequirePermission(constanta.PermZoneRead), DeliveryZoneHandler.FindByID)
+		zone.POST("/", middleware.RequirePermission(constanta.PermZoneWrite), DeliveryZoneHandler.Create)
+		zone.PUT("/:zoneId", middleware.RequirePermission(constanta.PermZoneWrite), DeliveryZoneHandler.Update)
+		zone.DELETE("/:zoneId", middleware.RequirePermission(constanta.PermZoneWrite), DeliveryZoneHandler.Delete)
+	}
+}
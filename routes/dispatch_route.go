@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func DispatchRouter(router *gin.Engine, auth gin.HandlerFunc, DispatchHandler handler.DispatchHandler) {
+	dispatch := router.Group("/api/v1")
+	dispatch.Use(auth)
+	{
+		dispatch.POST("/orders/dispatch", middleware.RequirePermission(constanta.PermOrderManage), DispatchHandler.Dispatch)
+
+		offers := dispatch.Group("/courier/offers")
+		offers.Use(middleware.RequirePermission(constanta.PermOrderDeliver))
+		{
+			offers.GET("/", DispatchHandler.Offers)
+			offers.PUT("/:offerId/accept", DispatchHandler.Accept)
+			offers.PUT("/:offerId/decline", DispatchHandler.Decline)
+		}
+	}
+}
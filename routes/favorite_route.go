@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func FavoriteRouter(router *gin.Engine, auth gin.HandlerFunc, FavoriteHandler handler.FavoriteHandler) {
+	me := router.Group("/api/v1/users/me")
+	me.Use(auth)
+	{
+		favorites := me.Group("/favorites")
+		{
+			favorites.GET("/", middleware.RequirePermission(constanta.PermProfileRead), FavoriteHandler.FindFavorites)
+			favorites.POST("/:menuId", middleware.RequirePermission(constanta.PermProfileWrite), FavoriteHandler.AddFavorite)
+			favorites.DELETE("/:menuId", middleware.RequirePermission(constanta.PermProfileWrite), FavoriteHandler.RemoveFavorite)
+		}
+
+		lists := me.Group("/lists")
+		{
+			lists.GET("/", middleware.RequirePermission(constanta.PermProfileRead), FavoriteHandler.FindLists)
+			lists.GET("/:listId", middleware.RequirePermission(constanta.PermProfileRead), FavoriteHandler.FindList)
+			lists.POST("/", middleware.RequirePermission(constanta.PermProfileWrite), FavoriteHandler.CreateList)
+			lists.PUT("/:listId", middleware.RequirePermission(constanta.PermProfileWrite), FavoriteHandler.UpdateList)
+			lists.DELETE("/:listId", middleware.RequirePermission(constanta.PermProfileWrite), FavoriteHandler.DeleteList)
+			lists.POST("/:listId/cart", middleware.RequirePermission(constanta.PermCartWrite), FavoriteHandler.AddListToCart)
+		}
+	}
+}
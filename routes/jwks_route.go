@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"online-food/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func JwksRouter(router *gin.Engine, JwksHandler handler.JwksHandler) {
+	router.GET("/.well-known/jwks.json", JwksHandler.Keys)
+}
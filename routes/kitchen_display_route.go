@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func KitchenDisplayRouter(router *gin.Engine, auth gin.HandlerFunc, KitchenDisplayHandler handler.KitchenDisplayHandler) {
+	//browsers can't set headers on a websocket, the token may come as the subprotocol after "bearer"
+	display := router.Group("/api/v1/kitchen")
+	display.Use(middleware.ProtocolToken(constanta.KitchenProtocol), auth, middleware.RequirePermission(constanta.PermOrderPrepare))
+	{
+		display.GET("/ws", KitchenDisplayHandler.Connect)
+	}
+}
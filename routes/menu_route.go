@@ -3,28 +3,28 @@ package routes
 import (
 	"online-food/handler"
 	"online-food/middleware"
+	"online-food/utils/constanta"
 
 	"github.com/gin-gonic/gin"
 )
 
-func MenuRouter(router *gin.Engine, MenuHandler handler.MenuHandler) {
+func MenuRouter(router *gin.Engine, auth gin.HandlerFunc, MenuHandler handler.MenuHandler) {
 	menu := router.Group("/api/v1")
-	menu.Use(middleware.Authentication())
+	menu.Use(auth)
 	{
-		admin := menu.Group("/menus")
-		admin.Use(middleware.RoleAccessMiddleware("admin"))
+		write := menu.Group("/menus")
+		write.Use(middleware.RequirePermission(constanta.PermMenuWrite))
 		{
-			admin.POST("/", MenuHandler.Create)
-			admin.PUT("/:menuId", MenuHandler.Update)
-			admin.DELETE("/:menuId", MenuHandler.Delete)
-			admin.GET("/:menuId", MenuHandler.FindByID)
-
+			write.POST("/", MenuHandler.Create)
+			write.PUT("/:menuId", MenuHandler.Update)
+			write.DELETE("/:menuId", MenuHandler.Delete)
 		}
 
-		cust := menu.Group("/menus")
-		cust.Use(middleware.RoleAccessMiddleware("customer", "admin"))
+		read := menu.Group("/menus")
+		read.Use(middleware.RequirePermission(constanta.PermMenuRead))
 		{
-			cust.GET("/", MenuHandler.FindAll)
+			read.GET("/", MenuHandler.FindAll)
+			read.GET("/:menuId", MenuHandler.FindByID)
 		}
 	}
 
@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func OrderRouter(router *gin.Engine, auth gin.HandlerFunc, OrderHandler handler.OrderHandler) {
+	order := router.Group("/api/v1")
+	order.Use(auth)
+	{
+		orders := order.Group("/orders")
+		{
+			orders.GET("/me", middleware.RequirePermission(constanta.PermOrderRead), OrderHandler.FindMine)
+			orders.GET("/stream", middleware.RequireAnyPermission(constanta.PermOrderRead, constanta.PermOrderReadAll, constanta.PermOrderPrepare, constanta.PermOrderPay, constanta.PermOrderDeliver), OrderHandler.Stream)
+			orders.GET("/", middleware.RequirePermission(constanta.PermOrderReadAll), OrderHandler.FindAll)
+			orders.GET("/:orderId", middleware.RequirePermission(constanta.PermOrderReadAll), OrderHandler.FindByID)
+			orders.GET("/:orderId/invoice", middleware.RequireAnyPermission(constanta.PermOrderRead, constanta.PermOrderReadAll, constanta.PermOrderPay), OrderHandler.Invoice)
+			orders.PUT("/:orderId/status", middleware.RequirePermission(constanta.PermOrderManage), OrderHandler.UpdateStatus)
+			orders.PUT("/:orderId/courier", middleware.RequirePermission(constanta.PermOrderManage), OrderHandler.AssignCourier)
+		}
+
+		kitchen := order.Group("/kitchen/orders")
+		kitchen.Use(middleware.RequirePermission(constanta.PermOrderPrepare))
+		{
+			kitchen.GET("/", OrderHandler.KitchenOrders)
+			kitchen.PUT("/:orderId/status", OrderHandler.KitchenUpdateStatus)
+		}
+
+		cashier := order.Group("/cashier/orders")
+		cashier.Use(middleware.RequirePermission(constanta.PermOrderPay))
+		{
+			cashier.GET("/", OrderHandler.CashierOrders)
+			cashier.PUT("/:orderId/paid", OrderHandler.CashierMarkPaid)
+		}
+
+		courier := order.Group("/courier/orders")
+		courier.Use(middleware.RequirePermission(constanta.PermOrderDeliver))
+		{
+			courier.GET("/", OrderHandler.CourierOrders)
+			courier.PUT("/:orderId/status", OrderHandler.CourierUpdateStatus)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func PromotionRouter(router *gin.Engine, auth gin.HandlerFunc, PromotionHandler handler.PromotionHandler) {
+	promotion := router.Group("/api/v1/promotions")
+	promotion.Use(auth, middleware.RequirePermission(constanta.PermPromotionManage))
+	{
+		promotion.POST("/", PromotionHandler.Create)
+		promotion.PUT("/:promotionId", PromotionHandler.Update)
+		promotion.DELETE("/:promotionId", PromotionHandler.Delete)
+		promotion.GET("/:promotionId", PromotionHandler.FindByID)
+		promotion.GET("/", PromotionHandler.FindAll)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RecommendationRouter(router *gin.Engine, auth gin.HandlerFunc, RecommendationHandler handler.RecommendationHandler) {
+	recommendations := router.Group("/api/v1/menus/recommendations")
+	recommendations.Use(auth)
+	{
+		recommendations.GET("", middleware.RequirePermission(constanta.PermMenuRead), RecommendationHandler.Recommend)
+		recommendations.POST("/refresh", middleware.RequirePermission(constanta.PermMenuWrite), RecommendationHandler.Refresh)
+	}
+}
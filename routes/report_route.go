@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ReportRouter(router *gin.Engine, auth gin.HandlerFunc, ReportHandler handler.ReportHandler) {
+	reports := router.Group("/api/v1/reports")
+	reports.Use(auth, middleware.RequirePermission(constanta.PermReportRead))
+	{
+		reports.GET("/revenue", ReportHandler.Revenue)
+		reports.GET("/orders", ReportHandler.OrderCounts)
+		reports.GET("/menus", ReportHandler.TopMenus)
+		reports.GET("/summary", ReportHandler.Summary)
+	}
+}
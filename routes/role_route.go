@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RoleRouter(router *gin.Engine, auth gin.HandlerFunc, RoleHandler handler.RoleHandler) {
+	role := router.Group("/api/v1")
+	role.Use(auth, middleware.RequirePermission(constanta.PermRoleManage))
+	{
+		role.GET("/permissions", RoleHandler.FindAllPermissions)
+
+		roles := role.Group("/roles")
+		{
+			roles.POST("/", RoleHandler.Create)
+			roles.PUT("/:roleId", RoleHandler.Update)
+			roles.DELETE("/:roleId", RoleHandler.Delete)
+			roles.GET("/:roleId", RoleHandler.FindByID)
+			roles.GET("/", RoleHandler.FindAll)
+		}
+	}
+}
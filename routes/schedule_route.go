@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ScheduleRouter(router *gin.Engine, auth gin.HandlerFunc, ScheduleHandler handler.ScheduleHandler) {
+	settings := router.Group("/api/v1/settings/schedule")
+	settings.Use(auth)
+	{
+		settings.GET("/", middleware.RequirePermission(constanta.PermSettingRead), ScheduleHandler.GetConfig)
+		settings.PUT("/", middleware.RequirePermission(constanta.PermSettingWrite), ScheduleHandler.UpdateConfig)
+	}
+
+	slots := router.Group("/api/v1/schedule")
+	slots.Use(auth)
+	{
+		slots.GET("/slots", middleware.RequirePermission(constanta.PermCartWrite), ScheduleHandler.Slots)
+	}
+}
@@ -7,15 +7,48 @@ import (
 )
 
 func SetupRouter(
+	auth gin.HandlerFunc,
 	UserHandler handler.UserHandler,
 	MenuHandler handler.MenuHandler,
 	CartHandler handler.CartHandler,
+	JwksHandler handler.JwksHandler,
+	TwoFactorHandler handler.TwoFactorHandler,
+	RoleHandler handler.RoleHandler,
+	OrderHandler handler.OrderHandler,
+	AddressHandler handler.AddressHandler,
+	DeliveryZoneHandler handler.DeliveryZoneHandler,
+	VoucherHandler handler.VoucherHandler,
+	PromotionHandler handler.PromotionHandler,
+	BillingHandler handler.BillingHandler,
+	KitchenDisplayHandler handler.KitchenDisplayHandler,
+	DeliveryHandler handler.DeliveryHandler,
+	DispatchHandler handler.DispatchHandler,
+	ScheduleHandler handler.ScheduleHandler,
+	FavoriteHandler handler.FavoriteHandler,
+	RecommendationHandler handler.RecommendationHandler,
+	ReportHandler handler.ReportHandler,
 ) *gin.Engine {
 
 	router := gin.Default()
-	UserRouter(router, UserHandler)
-	MenuRouter(router, MenuHandler)
-	CartRouter(router, CartHandler)
+	UserRouter(router, auth, UserHandler)
+	MenuRouter(router, auth, MenuHandler)
+	CartRouter(router, auth, CartHandler)
+	JwksRouter(router, JwksHandler)
+	TwoFactorRouter(router, auth, TwoFactorHandler)
+	RoleRouter(router, auth, RoleHandler)
+	OrderRouter(router, auth, OrderHandler)
+	AddressRouter(router, auth, AddressHandler)
+	DeliveryZoneRouter(router, auth, DeliveryZoneHandler)
+	VoucherRouter(router, auth, VoucherHandler)
+	PromotionRouter(router, auth, PromotionHandler)
+	BillingRouter(router, auth, BillingHandler)
+	KitchenDisplayRouter(router, auth, KitchenDisplayHandler)
+	DeliveryRouter(router, auth, DeliveryHandler)
+	DispatchRouter(router, auth, DispatchHandler)
+	ScheduleRouter(router, auth, ScheduleHandler)
+	FavoriteRouter(router, auth, FavoriteHandler)
+	RecommendationRouter(router, auth, RecommendationHandler)
+	ReportRouter(router, auth, ReportHandler)
 
 	return router
 }
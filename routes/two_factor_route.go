@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TwoFactorRouter(router *gin.Engine, auth gin.HandlerFunc, TwoFactorHandler handler.TwoFactorHandler) {
+	public := router.Group("/api/v1/auth")
+	{
+		public.POST("/login/2fa", TwoFactorHandler.Login)
+		public.POST("/2fa/setup", TwoFactorHandler.ChallengeSetup)
+		public.POST("/2fa/confirm", TwoFactorHandler.ChallengeConfirm)
+	}
+
+	twoFactor := router.Group("/api/v1")
+	twoFactor.Use(auth)
+	{
+		customers := twoFactor.Group("/users/me/2fa")
+		customers.Use(middleware.RequirePermission(constanta.PermProfileWrite))
+		{
+			customers.POST("/setup", TwoFactorHandler.Setup)
+			customers.POST("/confirm", TwoFactorHandler.Confirm)
+			customers.POST("/disable", TwoFactorHandler.Disable)
+			customers.POST("/recovery-codes", TwoFactorHandler.RegenerateRecoveryCodes)
+		}
+
+		admin := twoFactor.Group("/settings/two-factor")
+		{
+			admin.GET("/", middleware.RequirePermission(constanta.PermSettingRead), TwoFactorHandler.GetPolicy)
+			admin.PUT("/", middleware.RequirePermission(constanta.PermSettingWrite), TwoFactorHandler.UpdatePolicy)
+		}
+	}
+}
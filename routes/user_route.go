@@ -3,35 +3,42 @@ package routes
 import (
 	"online-food/handler"
 	"online-food/middleware"
+	"online-food/utils/constanta"
 
 	"github.com/gin-gonic/gin"
 )
 
-func UserRouter(router *gin.Engine, UserHandler handler.UserHandler) {
+func UserRouter(router *gin.Engine, auth gin.HandlerFunc, UserHandler handler.UserHandler) {
 	public := router.Group("/api/v1/auth")
 	{
 		public.POST("/login", UserHandler.Login)
 		public.POST("/refresh-token", UserHandler.RefreshToken)
 		public.POST("/register", UserHandler.Create)
+		public.GET("/verify-email", UserHandler.VerifyEmail)
+		public.POST("/resend-verification", UserHandler.ResendVerification)
+		public.POST("/forgot-password", UserHandler.ForgotPassword)
+		public.POST("/reset-password", UserHandler.ResetPassword)
 	}
 
 	user := router.Group("/api/v1")
-	user.Use(middleware.Authentication())
+	user.Use(auth)
 	{
-		customers := user.Group("/users")
-		customers.Use(middleware.RoleAccessMiddleware("customer", "admin"))
+		users := user.Group("/users")
 		{
-			customers.PUT("/me", UserHandler.Update)
-			customers.GET("/me", UserHandler.Profile)
-		}
+			users.PUT("/me", middleware.RequirePermission(constanta.PermProfileWrite), UserHandler.Update)
+			users.GET("/me", middleware.RequirePermission(constanta.PermProfileRead), UserHandler.Profile)
 
-		admin := user.Group("/users")
-		admin.Use(middleware.RoleAccessMiddleware("admin"))
-		{
-			admin.DELETE("/:userId", UserHandler.Delete)
-			admin.GET("/", UserHandler.FindAll)
-			admin.GET("/email/:email", UserHandler.FindByEmail)
-			admin.GET("/:userId", UserHandler.FindByID)
+			users.DELETE("/:userId", middleware.RequirePermission(constanta.PermUserWrite), UserHandler.Delete)
+			users.POST("/:userId/unlock", middleware.RequirePermission(constanta.PermUserWrite), UserHandler.Unlock)
+			users.PUT("/:userId/role", middleware.RequirePermission(constanta.PermUserWrite, constanta.PermRoleManage), UserHandler.ChangeRole)
+			users.POST("/:userId/suspend", middleware.RequirePermission(constanta.PermUserWrite), UserHandler.Suspend)
+			users.POST("/:userId/unsuspend", middleware.RequirePermission(constanta.PermUserWrite), UserHandler.Unsuspend)
+			users.POST("/:userId/restore", middleware.RequirePermission(constanta.PermUserWrite), UserHandler.Restore)
+			users.DELETE("/:userId/purge", middleware.RequirePermission(constanta.PermUserWrite), UserHandler.Purge)
+			users.GET("/deleted", middleware.RequirePermission(constanta.PermUserRead), UserHandler.FindDeleted)
+			users.GET("/", middleware.RequirePermission(constanta.PermUserRead), UserHandler.FindAll)
+			users.GET("/email/:email", middleware.RequirePermission(constanta.PermUserRead), UserHandler.FindByEmail)
+			users.GET("/:userId", middleware.RequirePermission(constanta.PermUserRead), UserHandler.FindByID)
 		}
 	}
 }
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/utils/constanta"
+
+	"github.com/gin-gonic/gin"
+)
+
+func VoucherRouter(router *gin.Engine, auth gin.HandlerFunc, VoucherHandler handler.VoucherHandler) {
+	voucher := router.Group("/api/v1")
+	voucher.Use(auth)
+	{
+		voucher.POST("/carts/:cartId/voucher", middleware.RequirePermission(constanta.PermCartWrite), VoucherHandler.Preview)
+
+		admin := voucher.Group("/vouchers")
+		admin.Use(middleware.RequirePermission(constanta.PermVoucherManage))
+		{
+			admin.POST("/", VoucherHandler.Create)
+			admin.PUT("/:voucherId", VoucherHandler.Update)
+			admin.DELETE("/:voucherId", VoucherHandler.Delete)
+			admin.GET("/:voucherId", VoucherHandler.FindByID)
+			admin.GET("/", VoucherHandler.FindAll)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"online-food/config"
+	"online-food/fixtures"
+)
+
+func seed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	file := flags.String("file", "", "fixture file, the bundled demo dataset when empty")
+	flags.Parse(args)
+
+	fixture, err := fixtures.Demo()
+	if *file != "" {
+		fixture, err = fixtures.Load(*file)
+	}
+	if err != nil {
+		return err
+	}
+
+	database := config.Database()
+	if err := config.Migrate(database); err != nil {
+		return err
+	}
+
+	result, err := fixture.Apply(database)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("seeded %d users, %d menus, %d carts and %d orders", result.Users, result.Menus, result.Carts, result.Orders)
+	return nil
+}
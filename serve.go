@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"online-food/config"
+	"online-food/handler"
+	"online-food/middleware"
+	"online-food/repository"
+	"online-food/routes"
+	"online-food/service"
+	"online-food/utils/token"
+	"os"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	skipMigrate := flags.Bool("skip-migrate", false, "don't migrate the database on start")
+	flags.Parse(args)
+
+	if err := token.LoadKeys(); err != nil {
+		return err
+	}
+
+	database := config.Database()
+	if !*skipMigrate {
+		if err := config.Migrate(database); err != nil {
+			return err
+		}
+	}
+
+	redis := config.RedisCLient()
+	validate := validator.New()
+	mailer := config.Mailer()
+	events := config.Broker(redis)
+
+	//user
+	userRepo := repository.NewUserRepositoryImpl(database)
+	resetRepo := repository.NewPasswordResetRepositoryImpl(database)
+	attemptRepo := repository.NewLoginAttemptRepositoryImpl(redis)
+	settingRepo := repository.NewSettingRepositoryImpl(database)
+	roleRepo := repository.NewRoleRepositoryImpl(database)
+	userService := service.NewUserServiceImpl(userRepo, resetRepo, settingRepo, roleRepo, attemptRepo, mailer, validate)
+	userHandler := handler.NewUserHandlerImpl(userService)
+
+	//menu
+	menuRepo := repository.NewMenuRepositoryImpl(database)
+	favoriteRepo := repository.NewFavoriteRepositoryImpl(database)
+	menuService := service.NewMenuServiceImpl(menuRepo, favoriteRepo, validate)
+	menuHandler := handler.NewMenuHandlerImpl(menuService)
+
+	//address
+	addressRepo := repository.NewAddressRepositoryImpl(database)
+	addressService := service.NewAddressServiceImpl(addressRepo, validate)
+	addressHandler := handler.NewAddressHandlerImpl(addressService)
+
+	//delivery zone
+	zoneRepo := repository.NewDeliveryZoneRepositoryImpl(database)
+	if err := service.CheckOutletLocation(context.Background(), zoneRepo); err != nil {
+		return err
+	}
+	zoneService := service.NewDeliveryZoneServiceImpl(zoneRepo, validate)
+	zoneHandler := handler.NewDeliveryZoneHandlerImpl(zoneService)
+
+	//cart
+	cartRepo := repository.NewCartRepositoryImpl(database)
+	voucherRepo := repository.NewVoucherRepositoryImpl(database)
+	cartService := service.NewCartServiceImpl(cartRepo, addressRepo, zoneRepo, voucherRepo, settingRepo, events, validate)
+	cartHandler := handler.NewCartHandlerImpl(cartService)
+
+	//favorite
+	favoriteService := service.NewFavoriteServiceImpl(favoriteRepo, cartService, validate)
+	favoriteHandler := handler.NewFavoriteHandlerImpl(favoriteService)
+
+	//recommendation
+	recommendationRepo := repository.NewRecommendationRepositoryImpl(database)
+	recommendationService := service.NewRecommendationServiceImpl(recommendationRepo, validate)
+	go recommendationService.Run(context.Background())
+	recommendationHandler := handler.NewRecommendationHandlerImpl(recommendationService)
+
+	//report
+	reportRepo := repository.NewReportRepositoryImpl(database)
+	reportService := service.NewReportServiceImpl(reportRepo, validate)
+	reportHandler := handler.NewReportHandlerImpl(reportService)
+
+	//voucher
+	voucherService := service.NewVoucherServiceImpl(voucherRepo, cartRepo, validate)
+	voucherHandler := handler.NewVoucherHandlerImpl(voucherService)
+
+	//promotion
+	promotionRepo := repository.NewPromotionRepositoryImpl(database)
+	promotionService := service.NewPromotionServiceImpl(promotionRepo, validate)
+	promotionHandler := handler.NewPromotionHandlerImpl(promotionService)
+
+	//billing
+	billingService := service.NewBillingServiceImpl(settingRepo, validate)
+	billingHandler := handler.NewBillingHandlerImpl(billingService)
+
+	//order
+	orderRepo := repository.NewOrderRepositoryImpl(database)
+	orderService := service.NewOrderServiceImpl(orderRepo, userRepo, events, validate)
+	orderHandler := handler.NewOrderHandlerImpl(orderService)
+
+	//kitchen display
+	kitchenDisplayService := service.NewKitchenDisplayServiceImpl(orderRepo, events)
+	go kitchenDisplayService.Run(context.Background())
+	kitchenDisplayHandler := handler.NewKitchenDisplayHandlerImpl(orderService, kitchenDisplayService, commaList(os.Getenv("KDS_ALLOWED_ORIGINS")))
+
+	//delivery
+	deliveryRepo := repository.NewDeliveryRepositoryImpl(database)
+	deliveryService := service.NewDeliveryServiceImpl(deliveryRepo, orderRepo, events, validate)
+	deliveryHandler := handler.NewDeliveryHandlerImpl(deliveryService)
+
+	//dispatch
+	dispatchRepo := repository.NewDispatchRepositoryImpl(database)
+	dispatchService := service.NewDispatchServiceImpl(dispatchRepo, orderRepo, deliveryRepo, events, validate)
+	if os.Getenv("DISPATCH_ENABLED") == "true" {
+		go dispatchService.Run(context.Background())
+	}
+	dispatchHandler := handler.NewDispatchHandlerImpl(dispatchService)
+
+	//schedule
+	scheduleRepo := repository.NewScheduleRepositoryImpl(database)
+	scheduleService := service.NewScheduleServiceImpl(scheduleRepo, orderRepo, settingRepo, events, validate)
+	go scheduleService.Run(context.Background())
+	scheduleHandler := handler.NewScheduleHandlerImpl(scheduleService)
+
+	//role
+	roleService := service.NewRoleServiceImpl(roleRepo, validate)
+	roleHandler := handler.NewRoleHandlerImpl(roleService)
+
+	//auth
+	auth := middleware.Authentication(userRepo, roleRepo)
+
+	//two factor
+	twoFactorRepo := repository.NewTwoFactorRepositoryImpl(database)
+	twoFactorService := service.NewTwoFactorServiceImpl(userRepo, twoFactorRepo, settingRepo, attemptRepo, validate)
+	twoFactorHandler := handler.NewTwoFactorHandlerImpl(twoFactorService)
+
+	//jwks
+	jwksHandler := handler.NewJwksHandlerImpl()
+
+	routes := routes.SetupRouter(auth, userHandler, menuHandler, cartHandler, jwksHandler, twoFactorHandler, roleHandler, orderHandler, addressHandler, zoneHandler, voucherHandler, promotionHandler, billingHandler, kitchenDisplayHandler, deliveryHandler, dispatchHandler, scheduleHandler, favoriteHandler, recommendationHandler, reportHandler)
+
+	//X-Forwarded-For is only read from these proxies, by default the client ip is the peer address
+	if err := routes.SetTrustedProxies(commaList(os.Getenv("TRUSTED_PROXIES"))); err != nil {
+		return err
+	}
+
+	port := os.Getenv("APP_PORT")
+	log.Println("server running on port " + port)
+	return routes.Run(port)
+}
+
+// commaList splits a comma separated list of origins or proxies, blanks are dropped.
+func commaList(value string) []string {
+	var origins []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimRight(strings.TrimSpace(v), "/"); v != "" {
+			origins = append(origins, v)
+		}
+	}
+	return origins
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/handling"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type AddressService interface {
+	Create(ctx context.Context, userID uint, req *dto.AddressCreateReq) (*dto.AddressResponse, error)
+	Update(ctx context.Context, userID uint, req *dto.AddressUpdateReq) (*dto.AddressResponse, error)
+	Delete(ctx context.Context, userID, id uint) error
+	FindByID(ctx context.Context, userID, id uint) (*dto.AddressResponse, error)
+	FindByUserID(ctx context.Context, userID uint) ([]*dto.AddressResponse, error)
+}
+
+type addressServiceImpl struct {
+	AddressRepo repository.AddressRepository
+	Validate    *validator.Validate
+}
+
+func NewAddressServiceImpl(addressRepo repository.AddressRepository, validate *validator.Validate) *addressServiceImpl {
+	return &addressServiceImpl{
+		AddressRepo: addressRepo,
+		Validate:    validate,
+	}
+}
+
+func (a *addressServiceImpl) Create(ctx context.Context, userID uint, req *dto.AddressCreateReq) (*dto.AddressResponse, error) {
+	if err := a.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	address := entity.Address{
+		UserID:    userID,
+		Label:     strings.TrimSpace(req.Label),
+		Address:   strings.TrimSpace(req.Address),
+		Latitude:  *req.Latitude,
+		Longitude: *req.Longitude,
+		Notes:     strings.TrimSpace(req.Notes),
+		IsDefault: req.IsDefault,
+	}
+
+	result, err := a.AddressRepo.Create(ctx, &address)
+	if err != nil {
+		return nil, fmt.Errorf("address service: create: %w", err)
+	}
+
+	response := dto.ToAddressResponse(result)
+	return response, nil
+}
+
+func (a *addressServiceImpl) Update(ctx context.Context, userID uint, req *dto.AddressUpdateReq) (*dto.AddressResponse, error) {
+	if err := a.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	address, err := a.AddressRepo.FindByID(ctx, userID, req.ID)
+	if err != nil {
+		if errors.Is(err, handling.ErrAddressNotFound) {
+			return nil, handling.ErrAddressNotFound
+		}
+		return nil, fmt.Errorf("address service: update: find address: %w", err)
+	}
+
+	if req.Label != nil {
+		address.Label = strings.TrimSpace(*req.Label)
+	}
+
+	if req.Address != nil {
+		address.Address = strings.TrimSpace(*req.Address)
+	}
+
+	if req.Latitude != nil {
+		address.Latitude = *req.Latitude
+	}
+
+	if req.Longitude != nil {
+		address.Longitude = *req.Longitude
+	}
+
+	if req.Notes != nil {
+		address.Notes = strings.TrimSpace(*req.Notes)
+	}
+
+	//the default only moves by picking another address, so a user always keeps one
+	makeDefault := req.IsDefault != nil && *req.IsDefault
+
+	result, err := a.AddressRepo.Update(ctx, address, makeDefault)
+	if err != nil {
+		if errors.Is(err, handling.ErrAddressNotFound) {
+			return nil, handling.ErrAddressNotFound
+		}
+		return nil, fmt.Errorf("address service: update: %w", err)
+	}
+
+	response := dto.ToAddressResponse(result)
+	return response, nil
+}
+
+func (a *addressServiceImpl) Delete(ctx context.Context, userID, id uint) error {
+	if err := a.AddressRepo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, handling.ErrAddressNotFound) {
+			return handling.ErrAddressNotFound
+		}
+		return fmt.Errorf("address service: delete: %w", err)
+	}
+
+	return nil
+}
+
+func (a *addressServiceImpl) FindByID(ctx context.Context, userID, id uint) (*dto.AddressResponse, error) {
+	address, err := a.AddressRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrAddressNotFound) {
+			return nil, handling.ErrAddressNotFound
+		}
+		return nil, fmt.Errorf("address service: find by id: %w", err)
+	}
+
+	response := dto.ToAddressResponse(address)
+	return response, nil
+}
+
+func (a *addressServiceImpl) FindByUserID(ctx context.Context, userID uint) ([]*dto.AddressResponse, error) {
+	addresses, err := a.AddressRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("address service: find by user id: %w", err)
+	}
+
+	responses := make([]*dto.AddressResponse, 0, len(addresses))
+	for _, v := range addresses {
+		responses = append(responses, dto.ToAddressResponse(v))
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"online-food/dto"
+	"online-food/repository"
+	"online-food/utils/billing"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"sort"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type BillingService interface {
+	GetConfig(ctx context.Context) (*dto.BillingConfigResponse, error)
+	UpdateConfig(ctx context.Context, req *dto.BillingConfigReq) (*dto.BillingConfigResponse, error)
+}
+
+type billingServiceImpl struct {
+	SettingRepo repository.SettingRepository
+	Validate    *validator.Validate
+}
+
+func NewBillingServiceImpl(settingRepo repository.SettingRepository, validate *validator.Validate) *billingServiceImpl {
+	return &billingServiceImpl{
+		SettingRepo: settingRepo,
+		Validate:    validate,
+	}
+}
+
+// loadBillingConfig reads the rates from the settings table, nothing configured means no service and no tax.
+func loadBillingConfig(ctx context.Context, settingRepo repository.SettingRepository) (billing.Config, error) {
+	config := billing.Config{Taxes: map[string]billing.Tax{}}
+
+	service, err := settingRepo.Get(ctx, constanta.SettingServiceCharge, "0")
+	if err != nil {
+		return config, err
+	}
+
+	config.ServiceCharge, err = strconv.ParseFloat(service, 64)
+	if err != nil {
+		return config, fmt.Errorf("parse service charge: %w", err)
+	}
+
+	taxes, err := settingRepo.Get(ctx, constanta.SettingTaxRates, "{}")
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal([]byte(taxes), &config.Taxes); err != nil {
+		return config, fmt.Errorf("parse tax rates: %w", err)
+	}
+
+	return config, nil
+}
+
+func toBillingConfigResponse(config billing.Config) *dto.BillingConfigResponse {
+	taxes := make([]dto.TaxRateDetails, 0, len(config.Taxes))
+	for category, v := range config.Taxes {
+		taxes = append(taxes, dto.TaxRateDetails{Category: category, Rate: v.Rate, Inclusive: v.Inclusive})
+	}
+
+	sort.Slice(taxes, func(i, j int) bool {
+		return taxes[i].Category < taxes[j].Category
+	})
+
+	return &dto.BillingConfigResponse{ServiceCharge: config.ServiceCharge, Taxes: taxes}
+}
+
+func (b *billingServiceImpl) GetConfig(ctx context.Context) (*dto.BillingConfigResponse, error) {
+	config, err := loadBillingConfig(ctx, b.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("billing service: get config: %w", err)
+	}
+
+	return toBillingConfigResponse(config), nil
+}
+
+// UpdateConfig replaces every rate, a category left out of the request is no longer taxed.
+func (b *billingServiceImpl) UpdateConfig(ctx context.Context, req *dto.BillingConfigReq) (*dto.BillingConfigResponse, error) {
+	if err := b.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	config := billing.Config{ServiceCharge: *req.ServiceCharge, Taxes: map[string]billing.Tax{}}
+	for _, v := range req.Taxes {
+		if _, ok := config.Taxes[v.Category]; ok {
+			return nil, handling.ErrorValidation
+		}
+		config.Taxes[v.Category] = billing.Tax{Rate: *v.Rate, Inclusive: v.Inclusive}
+	}
+
+	taxes, err := json.Marshal(config.Taxes)
+	if err != nil {
+		return nil, fmt.Errorf("billing service: update config: %w", err)
+	}
+
+	if err := b.SettingRepo.Set(ctx, constanta.SettingTaxRates, string(taxes)); err != nil {
+		return nil, fmt.Errorf("billing service: update config: %w", err)
+	}
+
+	if err := b.SettingRepo.Set(ctx, constanta.SettingServiceCharge, strconv.FormatFloat(config.ServiceCharge, 'f', -1, 64)); err != nil {
+		return nil, fmt.Errorf("billing service: update config: %w", err)
+	}
+
+	return toBillingConfigResponse(config), nil
+}
@@ -7,7 +7,11 @@ import (
 	"online-food/dto"
 	"online-food/entity"
 	"online-food/repository"
+	"online-food/utils/billing"
+	"online-food/utils/broker"
+	"online-food/utils/constanta"
 	"online-food/utils/handling"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -18,18 +22,29 @@ type CartService interface {
 	GetCartByUserID(ctx context.Context, userID uint) ([]*dto.CartResponse, error)
 	GetCartByID(ctx context.Context, cartID uint) (*dto.CartResponse, error)
 	GetAllCarts(ctx context.Context) ([]*dto.CartResponse, error)
-	CheckoutCart(ctx context.Context, cartID, userID uint) (*dto.OrderResponse, error)
+	CheckoutCart(ctx context.Context, cartID, userID uint, req *dto.CheckoutReq) (*dto.OrderResponse, error)
+	Reorder(ctx context.Context, req *dto.ReorderReq) (*dto.ReorderResponse, error)
 }
 
 type cartServiceImpl struct {
-	CartRepo repository.CartRepository
-	Validate *validator.Validate
+	CartRepo    repository.CartRepository
+	AddressRepo repository.AddressRepository
+	ZoneRepo    repository.DeliveryZoneRepository
+	VoucherRepo repository.VoucherRepository
+	SettingRepo repository.SettingRepository
+	Broker      broker.Broker
+	Validate    *validator.Validate
 }
 
-func NewCartServiceImpl(cartRepo repository.CartRepository, validate *validator.Validate) CartService {
+func NewCartServiceImpl(cartRepo repository.CartRepository, addressRepo repository.AddressRepository, zoneRepo repository.DeliveryZoneRepository, voucherRepo repository.VoucherRepository, settingRepo repository.SettingRepository, broker broker.Broker, validate *validator.Validate) CartService {
 	return &cartServiceImpl{
-		CartRepo: cartRepo,
-		Validate: validate,
+		CartRepo:    cartRepo,
+		AddressRepo: addressRepo,
+		ZoneRepo:    zoneRepo,
+		VoucherRepo: voucherRepo,
+		SettingRepo: settingRepo,
+		Broker:      broker,
+		Validate:    validate,
 	}
 }
 
@@ -50,7 +65,7 @@ func (c *cartServiceImpl) CreateCart(ctx context.Context, req *dto.CartCreateReq
 		}
 	}
 
-	result, err := c.CartRepo.CreateCart(ctx, &menus)
+	result, err := c.CartRepo.CreateCart(ctx, &menus, time.Now().In(outletTimezone()))
 	if err != nil {
 		if errors.Is(err, handling.ErrEmptyItems) {
 			return nil, handling.ErrEmptyItems
@@ -67,16 +82,99 @@ func (c *cartServiceImpl) CreateCart(ctx context.Context, req *dto.CartCreateReq
 		return nil, fmt.Errorf("create service: create cart: %w", err)
 	}
 
-	response := dto.ToCartResponse(result)
+	config, err := loadBillingConfig(ctx, c.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("create service: load billing: %w", err)
+	}
+
+	response := dto.ToCartResponse(result, config)
 	return response, nil
 }
 
+// Reorder fills a new cart with the items of a past order at today's prices. A deleted or sold out menu
+// is left out and a line is cut down to the stock left, every difference is reported back.
+func (c *cartServiceImpl) Reorder(ctx context.Context, req *dto.ReorderReq) (*dto.ReorderResponse, error) {
+	if err := c.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	lines, err := c.CartRepo.FindOrderLines(ctx, req.OrderID, req.UserID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("reorder service: find order lines: %w", err)
+	}
+
+	cart := dto.CartCreateReq{UserID: req.UserID}
+	var changes []dto.ReorderChange
+	cart.CartMenu, changes = reorderLines(lines)
+
+	if len(cart.CartMenu) == 0 {
+		return nil, handling.ErrNothingToReorder
+	}
+
+	//the stock is taken again when the cart is created, an item sold out in between fails the reorder
+	result, err := c.CreateCart(ctx, &cart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReorderResponse{OrderID: req.OrderID, Cart: result, Changes: changes}, nil
+}
+
+// reorderLines is what goes into the new cart and how it differs from the order, one change per line
+// with every reason that applies.
+func reorderLines(lines []*entity.CartMenu) ([]dto.CreateMenuItem, []dto.ReorderChange) {
+	items := []dto.CreateMenuItem{}
+	changes := []dto.ReorderChange{}
+	for _, v := range lines {
+		change := dto.ReorderChange{
+			MenuID:        v.MenuID,
+			Name:          v.Menu.Name,
+			Reasons:       []string{},
+			PreviousQty:   v.Qty,
+			UnitPrice:     v.Menu.Price,
+			PreviousPrice: v.UnitPrice,
+		}
+
+		if v.Menu.ID == 0 || v.Menu.DeletedAt.Valid {
+			change.Reasons = append(change.Reasons, constanta.ReorderRemoved)
+			changes = append(changes, change)
+			continue
+		}
+
+		if v.Menu.Stock <= 0 {
+			change.Reasons = append(change.Reasons, constanta.ReorderOutOfStock)
+			changes = append(changes, change)
+			continue
+		}
+
+		change.Qty = min(v.Qty, v.Menu.Stock)
+		if change.Qty < v.Qty {
+			change.Reasons = append(change.Reasons, constanta.ReorderQtyReduced)
+		}
+
+		if v.Menu.Price != v.UnitPrice {
+			change.Reasons = append(change.Reasons, constanta.ReorderPriceChanged)
+		}
+
+		if len(change.Reasons) > 0 {
+			changes = append(changes, change)
+		}
+
+		items = append(items, dto.CreateMenuItem{MenuID: v.MenuID, Qty: change.Qty})
+	}
+
+	return items, changes
+}
+
 func (c *cartServiceImpl) UpdateCart(ctx context.Context, req *dto.CartUpdateReq) (*dto.CartResponse, error) {
 	if err := c.Validate.Struct(req); err != nil {
 		return nil, handling.ErrorValidation
 	}
 
-	result, err := c.CartRepo.UpdateCart(ctx, req.CardID, req.MenuID, req.UserID, req.Qty)
+	result, err := c.CartRepo.UpdateCart(ctx, req.CardID, req.MenuID, req.UserID, req.Qty, time.Now().In(outletTimezone()))
 	if err != nil {
 		if errors.Is(err, handling.ErrMenuNotFound) {
 			return nil, handling.ErrMenuNotFound
@@ -97,7 +195,12 @@ func (c *cartServiceImpl) UpdateCart(ctx context.Context, req *dto.CartUpdateReq
 		return nil, fmt.Errorf("update service: update cart: %w", err)
 	}
 
-	response := dto.ToCartResponse(result)
+	config, err := loadBillingConfig(ctx, c.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("update service: load billing: %w", err)
+	}
+
+	response := dto.ToCartResponse(result, config)
 	return response, nil
 
 }
@@ -111,9 +214,14 @@ func (c *cartServiceImpl) GetCartByUserID(ctx context.Context, userID uint) ([]*
 		return nil, fmt.Errorf("get service: get cart by user id: %w", err)
 	}
 
+	config, err := loadBillingConfig(ctx, c.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("get service: load billing: %w", err)
+	}
+
 	responses := make([]*dto.CartResponse, 0, len(results))
 	for _, v := range results {
-		responses = append(responses, dto.ToCartResponse(v))
+		responses = append(responses, dto.ToCartResponse(v, config))
 	}
 
 	return responses, nil
@@ -128,7 +236,12 @@ func (c *cartServiceImpl) GetCartByID(ctx context.Context, cartID uint) (*dto.Ca
 		return nil, fmt.Errorf("get service: get cart by id: %w", err)
 	}
 
-	response := dto.ToCartResponse(result)
+	config, err := loadBillingConfig(ctx, c.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("get service: load billing: %w", err)
+	}
+
+	response := dto.ToCartResponse(result, config)
 	return response, nil
 }
 
@@ -138,27 +251,155 @@ func (c *cartServiceImpl) GetAllCarts(ctx context.Context) ([]*dto.CartResponse,
 		return nil, fmt.Errorf("get service: get all carts: %w", err)
 	}
 
+	config, err := loadBillingConfig(ctx, c.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("get service: load billing: %w", err)
+	}
+
 	responses := make([]*dto.CartResponse, 0, len(results))
 	for _, v := range results {
-		responses = append(responses, dto.ToCartResponse(v))
+		responses = append(responses, dto.ToCartResponse(v, config))
 	}
 
 	return responses, nil
 }
 
-func (c *cartServiceImpl) CheckoutCart(ctx context.Context, cartID, userID uint) (*dto.OrderResponse, error) {
-	result, err := c.CartRepo.CheckoutCart(ctx, cartID, userID)
+func (c *cartServiceImpl) CheckoutCart(ctx context.Context, cartID, userID uint, req *dto.CheckoutReq) (*dto.OrderResponse, error) {
+	if err := c.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	paymentMethod := req.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = constanta.Cash
+	}
+
+	order := entity.Order{
+		PaymentMethod: paymentMethod,
+	}
+
+	schedules, err := loadScheduleConfig(ctx, c.SettingRepo)
 	if err != nil {
+		return nil, fmt.Errorf("checkout service: load schedule: %w", err)
+	}
+
+	//a pre-order has to hit a slot that can still be booked, an order for now needs the outlet open
+	loc := outletTimezone()
+	if req.ScheduledFor != nil {
+		slot, ok := schedules.Find(req.ScheduledFor.In(loc), loc)
+		if !ok || !schedules.Bookable(slot, time.Now()) {
+			return nil, handling.ErrSlotUnavailable
+		}
+		order.ScheduledFor = &slot.Start
+	} else if !schedules.Open(time.Now().In(loc)) {
+		return nil, handling.ErrOutletClosed
+	}
+
+	//without an address id the default address is used, if the user saved any
+	var address *entity.Address
+	if req.AddressID != nil {
+		address, err = c.AddressRepo.FindByID(ctx, userID, *req.AddressID)
+	} else {
+		address, err = c.AddressRepo.FindDefault(ctx, userID)
+		if errors.Is(err, handling.ErrAddressNotFound) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, handling.ErrAddressNotFound) {
+			return nil, handling.ErrAddressNotFound
+		}
+		return nil, fmt.Errorf("checkout service: find address: %w", err)
+	}
+
+	if address != nil {
+		order.AddressID = &address.ID
+		order.Delivery = address.Snapshot()
+	}
+
+	//promotions are priced again at checkout time, a happy hour may have ended since the last change
+	if err := c.CartRepo.Reprice(ctx, cartID, userID, time.Now().In(loc)); err != nil {
 		if errors.Is(err, handling.ErrorIdNotFound) {
 			return nil, handling.ErrorIdNotFound
 		}
+		return nil, fmt.Errorf("checkout service: reprice cart: %w", err)
+	}
 
-		if errors.Is(err, handling.ErrCheckoutCart) {
-			return nil, handling.ErrCheckoutCart
+	cart, err := c.CartRepo.GetCartByID(ctx, cartID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("checkout service: find cart: %w", err)
+	}
+
+	if cart.UserID != userID {
+		return nil, handling.ErrorIdNotFound
+	}
+
+	quote, err := quoteDelivery(ctx, c.ZoneRepo, order.Delivery, cart.Amount)
+	if err != nil {
+		if errors.Is(err, handling.ErrLocationRequired) || errors.Is(err, handling.ErrOutsideDeliveryZone) || errors.Is(err, handling.ErrBelowMinimumOrder) || errors.Is(err, handling.ErrOutletLocationUnset) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("checkout service: quote delivery: %w", err)
+	}
+
+	order.Subtotal = cart.Subtotal
+	order.PromoDiscount = cart.Subtotal - cart.Amount
+	order.DeliveryZoneID = quote.ZoneID
+	order.DeliveryFee = quote.Fee
+	order.DistanceKm = quote.DistanceKm
+
+	if req.VoucherCode != "" {
+		voucher, discount, err := quoteVoucher(ctx, c.VoucherRepo, req.VoucherCode, userID, cart, time.Now())
+		if err != nil {
+			if isVoucherError(err) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("checkout service: quote voucher: %w", err)
+		}
+
+		order.VoucherID = &voucher.ID
+		order.VoucherCode = voucher.Code
+		order.Discount = discount.Discount
+	}
+
+	config, err := loadBillingConfig(ctx, c.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("checkout service: load billing: %w", err)
+	}
+
+	bill := billing.Compute(billing.CartLines(cart), order.PromoDiscount+order.Discount, order.DeliveryFee, config)
+	order.ServiceRate = config.ServiceCharge
+	order.ServiceCharge = bill.ServiceCharge
+	order.Tax = bill.Tax
+	order.TaxIncluded = bill.TaxIncluded
+	for _, v := range bill.Taxes {
+		order.Taxes = append(order.Taxes, entity.OrderTax{
+			Category:  v.Category,
+			Rate:      v.Rate,
+			Inclusive: v.Inclusive,
+			Base:      v.Base,
+			Amount:    v.Amount,
+		})
+	}
+
+	result, err := c.CartRepo.CheckoutCart(ctx, cartID, userID, &order, schedules.Capacity)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+
+		if errors.Is(err, handling.ErrCheckoutCart) || errors.Is(err, handling.ErrCartChanged) || errors.Is(err, handling.ErrSlotFull) || isVoucherError(err) {
+			return nil, err
 		}
 		return nil, fmt.Errorf("checkout service: checkout cart: %w", err)
 	}
 
+	publishOrder(ctx, c.Broker, broker.OrderCreated, result, "")
+
 	response := dto.ToOrderResponse(result)
 	return response, nil
 }
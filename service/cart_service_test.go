@@ -0,0 +1,66 @@
+package service
+
+import (
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestReorderLines(t *testing.T) {
+	menu := func(id uint, stock int, price float64) entity.Menu {
+		return entity.Menu{ID: id, Name: "menu", Stock: stock, Price: price}
+	}
+
+	deleted := menu(5, 10, 10000)
+	deleted.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name        string
+		line        entity.CartMenu
+		wantItem    *dto.CreateMenuItem
+		wantReasons []string
+	}{
+		{"unchanged", entity.CartMenu{MenuID: 1, Qty: 2, UnitPrice: 10000, Menu: menu(1, 10, 10000)}, &dto.CreateMenuItem{MenuID: 1, Qty: 2}, nil},
+		{"menu gone", entity.CartMenu{MenuID: 2, Qty: 2, UnitPrice: 10000}, nil, []string{constanta.ReorderRemoved}},
+		{"menu deleted", entity.CartMenu{MenuID: 5, Qty: 2, UnitPrice: 10000, Menu: deleted}, nil, []string{constanta.ReorderRemoved}},
+		{"sold out", entity.CartMenu{MenuID: 3, Qty: 2, UnitPrice: 10000, Menu: menu(3, 0, 12000)}, nil, []string{constanta.ReorderOutOfStock}},
+		{"less stock", entity.CartMenu{MenuID: 4, Qty: 3, UnitPrice: 10000, Menu: menu(4, 1, 10000)}, &dto.CreateMenuItem{MenuID: 4, Qty: 1}, []string{constanta.ReorderQtyReduced}},
+		{"new price", entity.CartMenu{MenuID: 6, Qty: 1, UnitPrice: 10000, Menu: menu(6, 5, 12000)}, &dto.CreateMenuItem{MenuID: 6, Qty: 1}, []string{constanta.ReorderPriceChanged}},
+		{"less stock and new price", entity.CartMenu{MenuID: 7, Qty: 4, UnitPrice: 10000, Menu: menu(7, 2, 9000)}, &dto.CreateMenuItem{MenuID: 7, Qty: 2}, []string{constanta.ReorderQtyReduced, constanta.ReorderPriceChanged}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, changes := reorderLines([]*entity.CartMenu{&tt.line})
+
+			if tt.wantItem == nil && len(items) != 0 {
+				t.Fatalf("items = %+v, want none", items)
+			}
+			if tt.wantItem != nil && (len(items) != 1 || items[0] != *tt.wantItem) {
+				t.Fatalf("items = %+v, want %+v", items, *tt.wantItem)
+			}
+
+			if tt.wantReasons == nil {
+				if len(changes) != 0 {
+					t.Fatalf("changes = %+v, want none", changes)
+				}
+				return
+			}
+
+			if len(changes) != 1 {
+				t.Fatalf("changes = %+v, want one entry", changes)
+			}
+			if !reflect.DeepEqual(changes[0].Reasons, tt.wantReasons) {
+				t.Fatalf("reasons = %v, want %v", changes[0].Reasons, tt.wantReasons)
+			}
+			if changes[0].MenuID != tt.line.MenuID || changes[0].PreviousQty != tt.line.Qty || changes[0].PreviousPrice != tt.line.UnitPrice {
+				t.Fatalf("change = %+v does not describe the line", changes[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/broker"
+	"online-food/utils/constanta"
+	"online-food/utils/geo"
+	"online-food/utils/handling"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// roadFactor turns the straight line between two points into a rough road distance.
+const roadFactor = 1.3
+
+type DeliveryService interface {
+	UpdateDelivery(ctx context.Context, req *dto.DeliveryUpdateReq) (*dto.DeliveryResponse, error)
+	UpdateLocation(ctx context.Context, req *dto.CourierLocationReq) error
+	Track(ctx context.Context, req *dto.DeliveryTrackReq) (*dto.DeliveryTrackingResponse, error)
+	Photo(ctx context.Context, req *dto.DeliveryTrackReq) (*dto.DeliveryPhoto, error)
+}
+
+type deliveryServiceImpl struct {
+	DeliveryRepo repository.DeliveryRepository
+	OrderRepo    repository.OrderRepository
+	Broker       broker.Broker
+	Validate     *validator.Validate
+}
+
+func NewDeliveryServiceImpl(deliveryRepo repository.DeliveryRepository, orderRepo repository.OrderRepository, broker broker.Broker, validate *validator.Validate) *deliveryServiceImpl {
+	return &deliveryServiceImpl{
+		DeliveryRepo: deliveryRepo,
+		OrderRepo:    orderRepo,
+		Broker:       broker,
+		Validate:     validate,
+	}
+}
+
+// photoDir is where proof of delivery photos are kept, they are only served through the api.
+func photoDir() string {
+	if dir := os.Getenv("DELIVERY_PHOTO_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join("uploads", "deliveries")
+}
+
+// courierSpeed is the average speed in km/h used for the eta.
+func courierSpeed() float64 {
+	speed, err := strconv.ParseFloat(os.Getenv("COURIER_SPEED_KMH"), 64)
+	if err != nil || speed <= 0 {
+		return 20
+	}
+	return speed
+}
+
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+func savePhoto(deliveryID uint, body []byte, now time.Time) (string, error) {
+	extension, ok := photoExtensions[http.DetectContentType(body)]
+	if !ok {
+		return "", handling.ErrInvalidPhoto
+	}
+
+	if err := os.MkdirAll(photoDir(), 0o755); err != nil {
+		return "", fmt.Errorf("create photo dir: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%d%s", deliveryID, now.UnixNano(), extension)
+	if err := os.WriteFile(filepath.Join(photoDir(), name), body, 0o644); err != nil {
+		return "", fmt.Errorf("write photo: %w", err)
+	}
+
+	return name, nil
+}
+
+// UpdateDelivery is the courier picking the order up, handing it over or giving up. Pick up and hand over
+// move the order through the state machine, a failed delivery sends a picked up order back to ready.
+func (d *deliveryServiceImpl) UpdateDelivery(ctx context.Context, req *dto.DeliveryUpdateReq) (*dto.DeliveryResponse, error) {
+	if err := d.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	delivery, err := d.DeliveryRepo.FindLatestByOrderID(ctx, req.OrderID)
+	if err != nil {
+		if errors.Is(err, handling.ErrDeliveryNotFound) {
+			return nil, handling.ErrOrderNotAssigned
+		}
+		return nil, fmt.Errorf("delivery service: update delivery: %w", err)
+	}
+
+	if delivery.CourierID != req.CourierID {
+		return nil, handling.ErrOrderNotAssigned
+	}
+
+	order, err := d.OrderRepo.FindByID(ctx, req.OrderID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("delivery service: update delivery: find order: %w", err)
+	}
+
+	from := delivery.Status
+	now := time.Now()
+	orderTo := ""
+
+	switch req.Status {
+	case constanta.DeliveryPickedUp:
+		if from != constanta.DeliveryAssigned {
+			return nil, handling.ErrInvalidDeliveryStatus
+		}
+		orderTo = constanta.Delivering
+		delivery.PickedUpAt = &now
+	case constanta.DeliveryDelivered:
+		if from != constanta.DeliveryPickedUp {
+			return nil, handling.ErrInvalidDeliveryStatus
+		}
+		orderTo = constanta.Delivered
+		delivery.DeliveredAt = &now
+	case constanta.DeliveryFailed:
+		if from != constanta.DeliveryAssigned && from != constanta.DeliveryPickedUp {
+			return nil, handling.ErrInvalidDeliveryStatus
+		}
+		//the notes are the reason, needed to decide whether to send the order out again
+		if req.Notes == "" {
+			return nil, handling.ErrFailReasonRequired
+		}
+		if from == constanta.DeliveryPickedUp {
+			orderTo = constanta.Ready
+		}
+		delivery.FailedAt = &now
+	}
+
+	if orderTo != "" && !canTransition(order.Status, orderTo) {
+		return nil, handling.ErrInvalidOrderStatus
+	}
+
+	delivery.Status = req.Status
+	delivery.ProofNotes = req.Notes
+
+	if len(req.Photo) > 0 {
+		name, err := savePhoto(delivery.ID, req.Photo, now)
+		if err != nil {
+			if errors.Is(err, handling.ErrInvalidPhoto) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("delivery service: update delivery: %w", err)
+		}
+		delivery.ProofPhoto = name
+	}
+
+	if err := d.DeliveryRepo.Transition(ctx, delivery, from, order.Status, orderTo); err != nil {
+		if delivery.ProofPhoto != "" {
+			os.Remove(filepath.Join(photoDir(), delivery.ProofPhoto))
+		}
+
+		if errors.Is(err, handling.ErrInvalidDeliveryStatus) || errors.Is(err, handling.ErrInvalidOrderStatus) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("delivery service: update delivery: %w", err)
+	}
+
+	if result, err := d.OrderRepo.FindByID(ctx, order.ID); err != nil {
+		log.Printf("delivery service: reload order %d: %v", order.ID, err)
+	} else if orderTo != "" {
+		publishOrder(ctx, d.Broker, broker.OrderStatus, result, order.Status)
+	} else {
+		publishOrder(ctx, d.Broker, broker.OrderCourier, result, "")
+	}
+
+	response := dto.ToDeliveryResponse(delivery)
+	return &response, nil
+}
+
+// UpdateLocation records a gps fix, a fix from the future is taken as now.
+func (d *deliveryServiceImpl) UpdateLocation(ctx context.Context, req *dto.CourierLocationReq) error {
+	if err := d.Validate.Struct(req); err != nil {
+		return handling.ErrorValidation
+	}
+
+	now := time.Now()
+	recordedAt := now
+	if req.RecordedAt != nil && req.RecordedAt.Before(now) {
+		recordedAt = *req.RecordedAt
+	}
+
+	location := entity.CourierLocation{
+		CourierID:  req.CourierID,
+		Latitude:   *req.Latitude,
+		Longitude:  *req.Longitude,
+		AccuracyM:  req.AccuracyM,
+		RecordedAt: recordedAt,
+	}
+
+	if err := d.DeliveryRepo.SaveLocation(ctx, &location); err != nil {
+		return fmt.Errorf("delivery service: update location: %w", err)
+	}
+
+	return nil
+}
+
+func (d *deliveryServiceImpl) findDelivery(ctx context.Context, req *dto.DeliveryTrackReq) (*entity.Order, *entity.Delivery, error) {
+	if err := d.Validate.Struct(req); err != nil {
+		return nil, nil, handling.ErrorValidation
+	}
+
+	order, err := d.OrderRepo.FindByID(ctx, req.OrderID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, nil, handling.ErrorIdNotFound
+		}
+		return nil, nil, fmt.Errorf("find order: %w", err)
+	}
+
+	if !req.All && order.UserID != req.UserID {
+		return nil, nil, handling.ErrorIdNotFound
+	}
+
+	delivery, err := d.DeliveryRepo.FindLatestByOrderID(ctx, order.ID)
+	if err != nil {
+		if errors.Is(err, handling.ErrDeliveryNotFound) {
+			return nil, nil, handling.ErrDeliveryNotFound
+		}
+		return nil, nil, fmt.Errorf("find delivery: %w", err)
+	}
+
+	return order, delivery, nil
+}
+
+// Track shows the delivery with the courier's last position and an eta. Before pick up the eta goes
+// through the outlet, it doesn't know how long the kitchen still needs.
+func (d *deliveryServiceImpl) Track(ctx context.Context, req *dto.DeliveryTrackReq) (*dto.DeliveryTrackingResponse, error) {
+	order, delivery, err := d.findDelivery(ctx, req)
+	if err != nil {
+		if errors.Is(err, handling.ErrorValidation) || errors.Is(err, handling.ErrorIdNotFound) || errors.Is(err, handling.ErrDeliveryNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("delivery service: track: %w", err)
+	}
+
+	response := &dto.DeliveryTrackingResponse{
+		OrderID:     order.ID,
+		OrderStatus: order.Status,
+		Delivery:    dto.ToDeliveryResponse(delivery),
+	}
+
+	if delivery.Status != constanta.DeliveryAssigned && delivery.Status != constanta.DeliveryPickedUp {
+		return response, nil
+	}
+
+	location, err := d.DeliveryRepo.LatestLocation(ctx, delivery.CourierID, delivery.AssignedAt)
+	if err != nil {
+		return nil, fmt.Errorf("delivery service: track: latest location: %w", err)
+	}
+
+	if location != nil {
+		response.Position = dto.ToCourierPosition(location)
+	}
+
+	if order.Delivery.Latitude == nil || order.Delivery.Longitude == nil {
+		return response, nil
+	}
+
+	stops := []geo.Point{}
+	if location != nil {
+		stops = append(stops, geo.Point{location.Latitude, location.Longitude})
+	}
+
+	if outletLat, outletLng, ok := outletLocation(); ok && delivery.Status == constanta.DeliveryAssigned {
+		stops = append(stops, geo.Point{outletLat, outletLng})
+	}
+
+	//without a fix the courier could be anywhere once the food has left
+	if len(stops) == 0 {
+		return response, nil
+	}
+
+	stops = append(stops, geo.Point{*order.Delivery.Latitude, *order.Delivery.Longitude})
+	distance := 0.0
+	for i := 1; i < len(stops); i++ {
+		distance += geo.Haversine(stops[i-1][0], stops[i-1][1], stops[i][0], stops[i][1])
+	}
+
+	distance = math.Round(distance*roadFactor*100) / 100
+	minutes := int(math.Ceil(distance / courierSpeed() * 60))
+	eta := time.Now().Add(time.Duration(minutes) * time.Minute)
+
+	response.DistanceKm = &distance
+	response.EtaMinutes = &minutes
+	response.EtaAt = &eta
+	return response, nil
+}
+
+func (d *deliveryServiceImpl) Photo(ctx context.Context, req *dto.DeliveryTrackReq) (*dto.DeliveryPhoto, error) {
+	_, delivery, err := d.findDelivery(ctx, req)
+	if err != nil {
+		if errors.Is(err, handling.ErrorValidation) || errors.Is(err, handling.ErrorIdNotFound) || errors.Is(err, handling.ErrDeliveryNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("delivery service: photo: %w", err)
+	}
+
+	if delivery.ProofPhoto == "" {
+		return nil, handling.ErrPhotoNotFound
+	}
+
+	body, err := os.ReadFile(filepath.Join(photoDir(), filepath.Base(delivery.ProofPhoto)))
+	if err != nil {
+		return nil, fmt.Errorf("delivery service: photo: %w", err)
+	}
+
+	return &dto.DeliveryPhoto{
+		Name:        delivery.ProofPhoto,
+		ContentType: http.DetectContentType(body),
+		Body:        body,
+	}, nil
+}
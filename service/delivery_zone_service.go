@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/geo"
+	"online-food/utils/handling"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type DeliveryZoneService interface {
+	Create(ctx context.Context, req *dto.DeliveryZoneCreateReq) (*dto.DeliveryZoneResponse, error)
+	Update(ctx context.Context, req *dto.DeliveryZoneUpdateReq) (*dto.DeliveryZoneResponse, error)
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*dto.DeliveryZoneResponse, error)
+	FindAll(ctx context.Context) ([]*dto.DeliveryZoneResponse, error)
+}
+
+type deliveryZoneServiceImpl struct {
+	ZoneRepo repository.DeliveryZoneRepository
+	Validate *validator.Validate
+}
+
+func NewDeliveryZoneServiceImpl(zoneRepo repository.DeliveryZoneRepository, validate *validator.Validate) *deliveryZoneServiceImpl {
+	return &deliveryZoneServiceImpl{
+		ZoneRepo: zoneRepo,
+		Validate: validate,
+	}
+}
+
+func validPolygon(polygon []geo.Point) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+
+	for _, v := range polygon {
+		if v[0] < -90 || v[0] > 90 || v[1] < -180 || v[1] > 180 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// applyShape keeps only the field that belongs to the zone type, so a zone never carries a stale shape.
+func applyShape(zone *entity.DeliveryZone, radiusKm float64, polygon []geo.Point) error {
+	switch zone.Type {
+	case constanta.ZoneRadius:
+		if radiusKm <= 0 {
+			return handling.ErrorValidation
+		}
+		zone.RadiusKm = radiusKm
+		zone.Polygon = ""
+	case constanta.ZonePolygon:
+		if !validPolygon(polygon) {
+			return handling.ErrorValidation
+		}
+		data, err := json.Marshal(polygon)
+		if err != nil {
+			return fmt.Errorf("encode polygon: %w", err)
+		}
+		zone.RadiusKm = 0
+		zone.Polygon = string(data)
+	default:
+		return handling.ErrorValidation
+	}
+
+	return nil
+}
+
+// checkOutlet refuses an active radius zone while the outlet location is unset, it has nothing to measure from.
+func checkOutlet(zone *entity.DeliveryZone) error {
+	if zone.Type != constanta.ZoneRadius || !zone.Active {
+		return nil
+	}
+
+	if _, _, ok := outletLocation(); !ok {
+		return handling.ErrOutletLocationUnset
+	}
+
+	return nil
+}
+
+// CheckOutletLocation runs on startup, so a missing or mistyped outlet location is found before the first checkout.
+func CheckOutletLocation(ctx context.Context, zoneRepo repository.DeliveryZoneRepository) error {
+	if _, _, ok := outletLocation(); ok {
+		return nil
+	}
+
+	if os.Getenv("OUTLET_LATITUDE") != "" || os.Getenv("OUTLET_LONGITUDE") != "" {
+		return errors.New("OUTLET_LATITUDE and OUTLET_LONGITUDE must both be set to valid coordinates")
+	}
+
+	zones, err := zoneRepo.FindActive(ctx)
+	if err != nil {
+		return fmt.Errorf("find delivery zones: %w", err)
+	}
+
+	for _, v := range zones {
+		if err := checkOutlet(v); err != nil {
+			return fmt.Errorf("delivery zone %d: %w", v.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (d *deliveryZoneServiceImpl) Create(ctx context.Context, req *dto.DeliveryZoneCreateReq) (*dto.DeliveryZoneResponse, error) {
+	if err := d.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	zone := entity.DeliveryZone{
+		Name:     strings.TrimSpace(req.Name),
+		Type:     req.Type,
+		Fee:      req.Fee,
+		MinOrder: req.MinOrder,
+		Active:   req.Active == nil || *req.Active,
+	}
+
+	if err := applyShape(&zone, req.RadiusKm, req.Polygon); err != nil {
+		return nil, err
+	}
+
+	if err := checkOutlet(&zone); err != nil {
+		return nil, err
+	}
+
+	result, err := d.ZoneRepo.Create(ctx, &zone)
+	if err != nil {
+		return nil, fmt.Errorf("delivery zone service: create: %w", err)
+	}
+
+	response := dto.ToDeliveryZoneResponse(result)
+	return response, nil
+}
+
+func (d *deliveryZoneServiceImpl) Update(ctx context.Context, req *dto.DeliveryZoneUpdateReq) (*dto.DeliveryZoneResponse, error) {
+	if err := d.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	zone, err := d.ZoneRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, handling.ErrZoneNotFound) {
+			return nil, handling.ErrZoneNotFound
+		}
+		return nil, fmt.Errorf("delivery zone service: update: find zone: %w", err)
+	}
+
+	if req.Name != nil {
+		zone.Name = strings.TrimSpace(*req.Name)
+	}
+
+	if req.Fee != nil {
+		zone.Fee = *req.Fee
+	}
+
+	if req.MinOrder != nil {
+		zone.MinOrder = *req.MinOrder
+	}
+
+	if req.Active != nil {
+		zone.Active = *req.Active
+	}
+
+	if req.Type != nil || req.RadiusKm != nil || req.Polygon != nil {
+		radiusKm := zone.RadiusKm
+		if req.RadiusKm != nil {
+			radiusKm = *req.RadiusKm
+		}
+
+		polygon := dto.ToDeliveryZoneResponse(zone).Polygon
+		if req.Polygon != nil {
+			polygon = req.Polygon
+		}
+
+		if req.Type != nil {
+			zone.Type = *req.Type
+		}
+
+		if err := applyShape(zone, radiusKm, polygon); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkOutlet(zone); err != nil {
+		return nil, err
+	}
+
+	result, err := d.ZoneRepo.Update(ctx, zone)
+	if err != nil {
+		if errors.Is(err, handling.ErrZoneNotFound) {
+			return nil, handling.ErrZoneNotFound
+		}
+		return nil, fmt.Errorf("delivery zone service: update: %w", err)
+	}
+
+	response := dto.ToDeliveryZoneResponse(result)
+	return response, nil
+}
+
+func (d *deliveryZoneServiceImpl) Delete(ctx context.Context, id uint) error {
+	if err := d.ZoneRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, handling.ErrZoneNotFound) {
+			return handling.ErrZoneNotFound
+		}
+		return fmt.Errorf("delivery zone service: delete: %w", err)
+	}
+
+	return nil
+}
+
+func (d *deliveryZoneServiceImpl) FindByID(ctx context.Context, id uint) (*dto.DeliveryZoneResponse, error) {
+	zone, err := d.ZoneRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrZoneNotFound) {
+			return nil, handling.ErrZoneNotFound
+		}
+		return nil, fmt.Errorf("delivery zone service: find by id: %w", err)
+	}
+
+	response := dto.ToDeliveryZoneResponse(zone)
+	return response, nil
+}
+
+func (d *deliveryZoneServiceImpl) FindAll(ctx context.Context) ([]*dto.DeliveryZoneResponse, error) {
+	zones, err := d.ZoneRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("delivery zone service: find all: %w", err)
+	}
+
+	responses := make([]*dto.DeliveryZoneResponse, 0, len(zones))
+	for _, v := range zones {
+		responses = append(responses, dto.ToDeliveryZoneResponse(v))
+	}
+
+	return responses, nil
+}
+
+type deliveryQuote struct {
+	ZoneID     *uint
+	Fee        float64
+	DistanceKm float64
+}
+
+// outletLocation is where radius zones are measured from.
+func outletLocation() (float64, float64, bool) {
+	lat, errLat := strconv.ParseFloat(os.Getenv("OUTLET_LATITUDE"), 64)
+	lng, errLng := strconv.ParseFloat(os.Getenv("OUTLET_LONGITUDE"), 64)
+	if errLat != nil || errLng != nil {
+		return 0, 0, false
+	}
+	return lat, lng, lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+// quoteDelivery picks the cheapest active zone containing the address whose minimum order is met.
+// Without any active zone delivery is free and unrestricted, so a fresh install can take orders.
+func quoteDelivery(ctx context.Context, zoneRepo repository.DeliveryZoneRepository, delivery entity.AddressSnapshot, subtotal float64) (*deliveryQuote, error) {
+	zones, err := zoneRepo.FindActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find delivery zones: %w", err)
+	}
+
+	quote := &deliveryQuote{}
+	outletLat, outletLng, hasOutlet := outletLocation()
+	hasLocation := delivery.Latitude != nil && delivery.Longitude != nil
+
+	if hasOutlet && hasLocation {
+		quote.DistanceKm = geo.Haversine(outletLat, outletLng, *delivery.Latitude, *delivery.Longitude)
+	}
+
+	if len(zones) == 0 {
+		return quote, nil
+	}
+
+	if !hasLocation {
+		return nil, handling.ErrLocationRequired
+	}
+
+	belowMinimum := false
+	for _, v := range zones {
+		inside := false
+		switch v.Type {
+		case constanta.ZoneRadius:
+			if !hasOutlet {
+				return nil, handling.ErrOutletLocationUnset
+			}
+			inside = quote.DistanceKm <= v.RadiusKm
+		case constanta.ZonePolygon:
+			var polygon []geo.Point
+			if err := json.Unmarshal([]byte(v.Polygon), &polygon); err != nil {
+				return nil, fmt.Errorf("decode polygon of zone %d: %w", v.ID, err)
+			}
+			inside = geo.InPolygon(*delivery.Latitude, *delivery.Longitude, polygon)
+		}
+
+		if !inside {
+			continue
+		}
+
+		if subtotal < v.MinOrder {
+			belowMinimum = true
+			continue
+		}
+
+		id := v.ID
+		quote.ZoneID = &id
+		quote.Fee = v.Fee
+		return quote, nil
+	}
+
+	if belowMinimum {
+		return nil, handling.ErrBelowMinimumOrder
+	}
+
+	return nil, handling.ErrOutsideDeliveryZone
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/geo"
+	"online-food/utils/handling"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type fakeZoneRepo struct {
+	repository.DeliveryZoneRepository
+	zones []*entity.DeliveryZone
+}
+
+func (f *fakeZoneRepo) Create(ctx context.Context, zone *entity.DeliveryZone) (*entity.DeliveryZone, error) {
+	zone.ID = uint(len(f.zones) + 1)
+	f.zones = append(f.zones, zone)
+	return zone, nil
+}
+
+func (f *fakeZoneRepo) FindActive(ctx context.Context) ([]*entity.DeliveryZone, error) {
+	var zones []*entity.DeliveryZone
+	for _, v := range f.zones {
+		if v.Active {
+			zones = append(zones, v)
+		}
+	}
+	return zones, nil
+}
+
+func TestRadiusZoneNeedsOutlet(t *testing.T) {
+	inactive := false
+	square := []geo.Point{{-6.1, 106.7}, {-6.1, 106.9}, {-6.3, 106.9}, {-6.3, 106.7}}
+
+	tests := []struct {
+		name     string
+		lat, lng string
+		req      dto.DeliveryZoneCreateReq
+		wantErr  error
+	}{
+		{"radius with outlet", "-6.2", "106.8", dto.DeliveryZoneCreateReq{Name: "near", Type: constanta.ZoneRadius, RadiusKm: 3}, nil},
+		{"radius without outlet", "", "", dto.DeliveryZoneCreateReq{Name: "near", Type: constanta.ZoneRadius, RadiusKm: 3}, handling.ErrOutletLocationUnset},
+		{"radius with a bad outlet", "-600", "106.8", dto.DeliveryZoneCreateReq{Name: "near", Type: constanta.ZoneRadius, RadiusKm: 3}, handling.ErrOutletLocationUnset},
+		{"inactive radius without outlet", "", "", dto.DeliveryZoneCreateReq{Name: "near", Type: constanta.ZoneRadius, RadiusKm: 3, Active: &inactive}, nil},
+		{"polygon without outlet", "", "", dto.DeliveryZoneCreateReq{Name: "city", Type: constanta.ZonePolygon, Polygon: square}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OUTLET_LATITUDE", tt.lat)
+			t.Setenv("OUTLET_LONGITUDE", tt.lng)
+
+			zones := &fakeZoneRepo{}
+			_, err := NewDeliveryZoneServiceImpl(zones, validator.New()).Create(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("create: %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckOutletLocation(t *testing.T) {
+	radius := &entity.DeliveryZone{ID: 1, Type: constanta.ZoneRadius, RadiusKm: 3, Active: true}
+
+	tests := []struct {
+		name     string
+		lat, lng string
+		zones    []*entity.DeliveryZone
+		wantErr  bool
+	}{
+		{"no zones, no outlet", "", "", nil, false},
+		{"active radius, no outlet", "", "", []*entity.DeliveryZone{radius}, true},
+		{"active radius with outlet", "-6.2", "106.8", []*entity.DeliveryZone{radius}, false},
+		{"only half the outlet", "-6.2", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OUTLET_LATITUDE", tt.lat)
+			t.Setenv("OUTLET_LONGITUDE", tt.lng)
+
+			err := CheckOutletLocation(context.Background(), &fakeZoneRepo{zones: tt.zones})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("check outlet: %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/broker"
+	"online-food/utils/constanta"
+	"online-food/utils/dispatch"
+	"online-food/utils/geo"
+	"online-food/utils/handling"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// LocationSource gives the newest fix of every courier seen since a time, the delivery repository in production.
+type LocationSource interface {
+	LatestLocations(ctx context.Context, since time.Time) ([]*entity.CourierLocation, error)
+}
+
+type DispatchService interface {
+	Run(ctx context.Context)
+	Dispatch(ctx context.Context) (*dto.DispatchResponse, error)
+	Offers(ctx context.Context, courierID uint) ([]dto.OfferResponse, error)
+	Accept(ctx context.Context, req *dto.OfferReq) (*dto.OrderResponse, error)
+	Decline(ctx context.Context, req *dto.OfferReq) (*dto.OfferResponse, error)
+}
+
+// dispatchServiceImpl offers ready orders to couriers. Clock and Locations are fields so a test can
+// replace them, with both fixed a round always makes the same offers.
+type dispatchServiceImpl struct {
+	DispatchRepo   repository.DispatchRepository
+	OrderRepo      repository.OrderRepository
+	Locations      LocationSource
+	Broker         broker.Broker
+	Validate       *validator.Validate
+	Clock          func() time.Time
+	Weights        dispatch.Weights
+	OfferTimeout   time.Duration
+	DeclineCooling time.Duration
+	MaxLoad        int
+	LocationMaxAge time.Duration
+	Interval       time.Duration
+
+	mu   sync.Mutex
+	wake chan struct{}
+}
+
+func NewDispatchServiceImpl(dispatchRepo repository.DispatchRepository, orderRepo repository.OrderRepository, locations LocationSource, broker broker.Broker, validate *validator.Validate) *dispatchServiceImpl {
+	return &dispatchServiceImpl{
+		DispatchRepo:   dispatchRepo,
+		OrderRepo:      orderRepo,
+		Locations:      locations,
+		Broker:         broker,
+		Validate:       validate,
+		Clock:          time.Now,
+		Weights:        dispatch.DefaultWeights,
+		OfferTimeout:   time.Duration(envInt("DISPATCH_OFFER_SECONDS", 60)) * time.Second,
+		DeclineCooling: time.Duration(envInt("DISPATCH_DECLINE_MINUTES", 5)) * time.Minute,
+		MaxLoad:        envInt("DISPATCH_MAX_LOAD", 2),
+		LocationMaxAge: time.Duration(envInt("DISPATCH_LOCATION_MINUTES", 5)) * time.Minute,
+		Interval:       time.Duration(envInt("DISPATCH_INTERVAL_SECONDS", 10)) * time.Second,
+		wake:           make(chan struct{}, 1),
+	}
+}
+
+// Run dispatches every Interval, and right away when an order becomes ready, loses its courier or an offer
+// is declined. Offers that time out are picked up by the next round.
+func (d *dispatchServiceImpl) Run(ctx context.Context) {
+	events, cancel := d.Broker.Subscribe()
+	defer cancel()
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			ready := event.Type == broker.OrderStatus && event.Status == constanta.Ready
+			unassigned := event.Type == broker.OrderCourier && event.CourierID == nil
+			if !ready && !unassigned {
+				continue
+			}
+		}
+
+		if _, err := d.Dispatch(ctx); err != nil {
+			log.Printf("dispatch service: %v", err)
+		}
+	}
+}
+
+func (d *dispatchServiceImpl) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// candidates is every courier who may take an order now: a recent fix, no open offer and room in the bag.
+func (d *dispatchServiceImpl) candidates(ctx context.Context, now time.Time) ([]dispatch.Candidate, error) {
+	locations, err := d.Locations.LatestLocations(ctx, now.Add(-d.LocationMaxAge))
+	if err != nil {
+		return nil, fmt.Errorf("latest locations: %w", err)
+	}
+
+	ids := make([]uint, 0, len(locations))
+	for _, v := range locations {
+		ids = append(ids, v.CourierID)
+	}
+
+	couriers, err := d.DispatchRepo.FindCouriers(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("find couriers: %w", err)
+	}
+
+	eligible := make(map[uint]bool, len(couriers))
+	for _, v := range couriers {
+		eligible[v.ID] = true
+	}
+
+	busy, err := d.DispatchRepo.FindBusyCouriers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("find busy couriers: %w", err)
+	}
+
+	for _, v := range busy {
+		delete(eligible, v)
+	}
+
+	loads, err := d.DispatchRepo.CourierLoads(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("courier loads: %w", err)
+	}
+
+	assigned, err := d.DispatchRepo.LastAssigned(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("last assigned: %w", err)
+	}
+
+	//without an outlet location everyone is as far, load and waiting time decide
+	outletLat, outletLng, hasOutlet := outletLocation()
+
+	candidates := []dispatch.Candidate{}
+	for _, v := range locations {
+		if !eligible[v.CourierID] || loads[v.CourierID] >= d.MaxLoad {
+			continue
+		}
+
+		candidate := dispatch.Candidate{CourierID: v.CourierID, Load: loads[v.CourierID]}
+		if hasOutlet {
+			candidate.DistanceKm = geo.Haversine(v.Latitude, v.Longitude, outletLat, outletLng)
+		}
+
+		if at, ok := assigned[v.CourierID]; ok {
+			candidate.LastAssigned = &at
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// Dispatch runs one round: open offers past their time expire, then every waiting order, oldest first,
+// is offered to the best courier who hasn't been asked for it yet. Once everyone was asked the order goes
+// round again, skipping couriers who declined it within DeclineCooling. A courier holds one offer at a time.
+func (d *dispatchServiceImpl) Dispatch(ctx context.Context) (*dto.DispatchResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.Clock()
+	expired, err := d.DispatchRepo.ExpireOffers(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch service: expire offers: %w", err)
+	}
+
+	result := &dto.DispatchResponse{Expired: expired, Offers: []dto.OfferResponse{}, Waiting: []uint{}}
+
+	orders, err := d.DispatchRepo.FindWaitingOrders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch service: find waiting orders: %w", err)
+	}
+
+	if len(orders) == 0 {
+		return result, nil
+	}
+
+	candidates, err := d.candidates(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch service: %w", err)
+	}
+
+	taken := map[uint]bool{}
+	for _, order := range orders {
+		offered, err := d.DispatchRepo.FindOrderOffers(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("dispatch service: find order offers: %w", err)
+		}
+
+		//the newest offer per courier decides, offers come oldest first
+		last := make(map[uint]*entity.DeliveryOffer, len(offered))
+		for _, v := range offered {
+			last[v.CourierID] = v
+		}
+
+		pool, again := []dispatch.Candidate{}, []dispatch.Candidate{}
+		for _, v := range candidates {
+			asked, ok := last[v.CourierID]
+			switch {
+			case taken[v.CourierID]:
+			case !ok:
+				pool = append(pool, v)
+			case asked.Status == constanta.OfferPending:
+			case asked.Status == constanta.OfferDeclined && asked.RespondedAt != nil && now.Before(asked.RespondedAt.Add(d.DeclineCooling)):
+			default:
+				again = append(again, v)
+			}
+		}
+
+		if len(pool) == 0 {
+			pool = again
+		}
+
+		var offer *entity.DeliveryOffer
+		for _, v := range dispatch.Rank(pool, d.Weights, now) {
+			next := &entity.DeliveryOffer{
+				OrderID:    order.ID,
+				CourierID:  v.CourierID,
+				Status:     constanta.OfferPending,
+				Score:      v.Score,
+				DistanceKm: v.DistanceKm,
+				OfferedAt:  now,
+				ExpiresAt:  now.Add(d.OfferTimeout),
+			}
+
+			//another instance got there first, the courier or the order is gone
+			if err := d.DispatchRepo.CreateOffer(ctx, next); err != nil {
+				if errors.Is(err, handling.ErrOfferClosed) {
+					taken[v.CourierID] = true
+					continue
+				}
+				return nil, fmt.Errorf("dispatch service: create offer: %w", err)
+			}
+
+			offer = next
+			taken[v.CourierID] = true
+			break
+		}
+
+		if offer == nil {
+			result.Waiting = append(result.Waiting, order.ID)
+			continue
+		}
+
+		offer.Order = *order
+		result.Offers = append(result.Offers, dto.ToOfferResponse(offer))
+
+		//only the courier is told, the customer doesn't need to see offers
+		event := broker.OrderEvent{
+			Type:      broker.OrderOffered,
+			OrderID:   order.ID,
+			CourierID: &offer.CourierID,
+			OfferID:   offer.ID,
+			Status:    order.Status,
+			At:        now.UTC(),
+		}
+		if err := d.Broker.Publish(ctx, event); err != nil {
+			log.Printf("publish %s for order %d: %v", event.Type, order.ID, err)
+		}
+	}
+
+	return result, nil
+}
+
+func (d *dispatchServiceImpl) Offers(ctx context.Context, courierID uint) ([]dto.OfferResponse, error) {
+	offers, err := d.DispatchRepo.FindPendingOffers(ctx, courierID, d.Clock())
+	if err != nil {
+		return nil, fmt.Errorf("dispatch service: offers: %w", err)
+	}
+
+	responses := make([]dto.OfferResponse, 0, len(offers))
+	for _, v := range offers {
+		responses = append(responses, dto.ToOfferResponse(v))
+	}
+
+	return responses, nil
+}
+
+// Accept assigns the order to the courier, the same as a manager assigning it.
+func (d *dispatchServiceImpl) Accept(ctx context.Context, req *dto.OfferReq) (*dto.OrderResponse, error) {
+	if err := d.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	offer, err := d.DispatchRepo.AcceptOffer(ctx, req.ID, req.CourierID, d.Clock())
+	if err != nil {
+		if errors.Is(err, handling.ErrOfferNotFound) || errors.Is(err, handling.ErrOfferClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("dispatch service: accept: %w", err)
+	}
+
+	order, err := d.OrderRepo.FindByID(ctx, offer.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch service: accept: find order: %w", err)
+	}
+
+	publishOrder(ctx, d.Broker, broker.OrderCourier, order, "")
+
+	response := dto.ToOrderResponse(order)
+	return response, nil
+}
+
+// Decline frees the courier and has the order offered to the next one straight away.
+func (d *dispatchServiceImpl) Decline(ctx context.Context, req *dto.OfferReq) (*dto.OfferResponse, error) {
+	if err := d.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	offer, err := d.DispatchRepo.DeclineOffer(ctx, req.ID, req.CourierID, d.Clock())
+	if err != nil {
+		if errors.Is(err, handling.ErrOfferNotFound) || errors.Is(err, handling.ErrOfferClosed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("dispatch service: decline: %w", err)
+	}
+
+	d.signal()
+
+	response := dto.ToOfferResponse(offer)
+	return &response, nil
+}
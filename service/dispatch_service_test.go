@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/broker"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// fakeDispatchRepo keeps orders and offers in memory with the same rules as the database version.
+type fakeDispatchRepo struct {
+	repository.DispatchRepository
+	orders []*entity.Order
+	offers []*entity.DeliveryOffer
+}
+
+func (f *fakeDispatchRepo) pending(match func(*entity.DeliveryOffer) bool) bool {
+	for _, v := range f.offers {
+		if v.Status == constanta.OfferPending && match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeDispatchRepo) FindWaitingOrders(ctx context.Context) ([]*entity.Order, error) {
+	var orders []*entity.Order
+	for _, order := range f.orders {
+		offered := f.pending(func(v *entity.DeliveryOffer) bool { return v.OrderID == order.ID })
+		if order.Status == constanta.Ready && order.CourierID == nil && !offered {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (f *fakeDispatchRepo) FindCouriers(ctx context.Context, ids []uint) ([]*entity.User, error) {
+	users := make([]*entity.User, 0, len(ids))
+	for _, v := range ids {
+		users = append(users, &entity.User{ID: v, Role: constanta.Courier})
+	}
+	return users, nil
+}
+
+func (f *fakeDispatchRepo) CourierLoads(ctx context.Context, ids []uint) (map[uint]int, error) {
+	return map[uint]int{}, nil
+}
+
+func (f *fakeDispatchRepo) LastAssigned(ctx context.Context, ids []uint) (map[uint]time.Time, error) {
+	return map[uint]time.Time{}, nil
+}
+
+func (f *fakeDispatchRepo) FindOrderOffers(ctx context.Context, orderID uint) ([]*entity.DeliveryOffer, error) {
+	var offers []*entity.DeliveryOffer
+	for _, v := range f.offers {
+		if v.OrderID == orderID {
+			offers = append(offers, v)
+		}
+	}
+	return offers, nil
+}
+
+func (f *fakeDispatchRepo) FindBusyCouriers(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	for _, v := range f.offers {
+		if v.Status == constanta.OfferPending {
+			ids = append(ids, v.CourierID)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeDispatchRepo) CreateOffer(ctx context.Context, offer *entity.DeliveryOffer) error {
+	if f.pending(func(v *entity.DeliveryOffer) bool {
+		return v.OrderID == offer.OrderID || v.CourierID == offer.CourierID
+	}) {
+		return handling.ErrOfferClosed
+	}
+
+	offer.ID = uint(len(f.offers) + 1)
+	f.offers = append(f.offers, offer)
+	return nil
+}
+
+func (f *fakeDispatchRepo) ExpireOffers(ctx context.Context, now time.Time) (int64, error) {
+	var expired int64
+	for _, v := range f.offers {
+		if v.Status == constanta.OfferPending && !v.ExpiresAt.After(now) {
+			v.Status = constanta.OfferExpired
+			v.RespondedAt = &now
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (f *fakeDispatchRepo) DeclineOffer(ctx context.Context, id, courierID uint, now time.Time) (*entity.DeliveryOffer, error) {
+	for _, v := range f.offers {
+		if v.ID != id || v.CourierID != courierID {
+			continue
+		}
+
+		if v.Status != constanta.OfferPending || !now.Before(v.ExpiresAt) {
+			return nil, handling.ErrOfferClosed
+		}
+
+		v.Status = constanta.OfferDeclined
+		v.RespondedAt = &now
+		return v, nil
+	}
+	return nil, handling.ErrOfferNotFound
+}
+
+// fakeLocations reports the couriers in seen as long as their fix is newer than since.
+type fakeLocations struct {
+	seen map[uint]*entity.CourierLocation
+}
+
+func (f *fakeLocations) LatestLocations(ctx context.Context, since time.Time) ([]*entity.CourierLocation, error) {
+	var locations []*entity.CourierLocation
+	for _, id := range []uint{1, 2} {
+		if v, ok := f.seen[id]; ok && v.RecordedAt.After(since) {
+			locations = append(locations, v)
+		}
+	}
+	return locations, nil
+}
+
+func TestDispatchOfferTimeoutAndReoffer(t *testing.T) {
+	t.Setenv("OUTLET_LATITUDE", "-6.2")
+	t.Setenv("OUTLET_LONGITUDE", "106.8")
+
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	//courier 1 waits at the outlet, courier 2 is about a km away
+	locations := &fakeLocations{seen: map[uint]*entity.CourierLocation{
+		1: {CourierID: 1, Latitude: -6.2, Longitude: 106.8, RecordedAt: start},
+		2: {CourierID: 2, Latitude: -6.209, Longitude: 106.8, RecordedAt: start},
+	}}
+	repo := &fakeDispatchRepo{orders: []*entity.Order{{ID: 7, Status: constanta.Ready}}}
+
+	service := NewDispatchServiceImpl(repo, nil, locations, broker.NewMemoryBroker(), validator.New())
+	service.Clock = func() time.Time { return now }
+	service.OfferTimeout = time.Minute
+	service.DeclineCooling = 5 * time.Minute
+	service.LocationMaxAge = time.Hour
+
+	tests := []struct {
+		name        string
+		after       time.Duration
+		setup       func(t *testing.T)
+		wantExpired int64
+		wantCourier uint
+	}{
+		{name: "nearest courier first", wantCourier: 1},
+		{name: "still open", after: 30 * time.Second},
+		{name: "timed out, next courier", after: 61 * time.Second, wantExpired: 1, wantCourier: 2},
+		{
+			name:  "declined, everyone asked, goes round again",
+			after: 70 * time.Second,
+			setup: func(t *testing.T) {
+				if _, err := service.Decline(context.Background(), &dto.OfferReq{ID: 2, CourierID: 2}); err != nil {
+					t.Fatalf("decline: %v", err)
+				}
+			},
+			wantCourier: 1,
+		},
+		{
+			name:  "only a courier who just declined is left",
+			after: 131 * time.Second,
+			setup: func(t *testing.T) {
+				locations.seen[1].RecordedAt = start.Add(-2 * time.Hour)
+			},
+			wantExpired: 1,
+		},
+		{name: "declined long enough ago", after: 70*time.Second + 5*time.Minute, wantCourier: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = start.Add(tt.after)
+			if tt.setup != nil {
+				tt.setup(t)
+			}
+
+			result, err := service.Dispatch(context.Background())
+			if err != nil {
+				t.Fatalf("dispatch: %v", err)
+			}
+
+			if result.Expired != tt.wantExpired {
+				t.Fatalf("expired = %d, want %d", result.Expired, tt.wantExpired)
+			}
+
+			if tt.wantCourier == 0 {
+				if len(result.Offers) != 0 {
+					t.Fatalf("offers = %+v, want none", result.Offers)
+				}
+				return
+			}
+
+			if len(result.Offers) != 1 || result.Offers[0].CourierID != tt.wantCourier || result.Offers[0].OrderID != 7 {
+				t.Fatalf("offers = %+v, want order 7 to courier %d", result.Offers, tt.wantCourier)
+			}
+
+			if want := now.Add(time.Minute); !result.Offers[0].ExpiresAt.Equal(want) {
+				t.Fatalf("expires at %v, want %v", result.Offers[0].ExpiresAt, want)
+			}
+		})
+	}
+}
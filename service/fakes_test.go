@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/handling"
+	"online-food/utils/token"
+	"testing"
+	"time"
+)
+
+func loadTestKeys(t *testing.T) {
+	t.Helper()
+	t.Setenv("JWT_KEYS", "")
+	t.Setenv("JWT_SECRET", "test-access-secret")
+	t.Setenv("JWT_REFRESH_SECRET", "test-refresh-secret")
+	t.Setenv("EMAIL_VERIFY_SECRET", "test-verify-secret")
+	if err := token.LoadKeys(); err != nil {
+		t.Fatalf("load keys: %v", err)
+	}
+}
+
+// fakeAttemptRepo keeps failures and locks in memory, unlock drops the locks as if the backoff had passed.
+type fakeAttemptRepo struct {
+	failures map[string]int64
+	locks    map[string]time.Duration
+}
+
+func newFakeAttemptRepo() *fakeAttemptRepo {
+	return &fakeAttemptRepo{failures: map[string]int64{}, locks: map[string]time.Duration{}}
+}
+
+func (f *fakeAttemptRepo) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	return f.locks[key], nil
+}
+
+func (f *fakeAttemptRepo) AddFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	f.failures[key]++
+	return f.failures[key], nil
+}
+
+func (f *fakeAttemptRepo) Lock(ctx context.Context, key string, duration time.Duration) error {
+	f.locks[key] = duration
+	return nil
+}
+
+func (f *fakeAttemptRepo) Clear(ctx context.Context, key string) error {
+	delete(f.failures, key)
+	delete(f.locks, key)
+	return nil
+}
+
+func (f *fakeAttemptRepo) unlock() {
+	f.locks = map[string]time.Duration{}
+}
+
+// fakeUserRepo only answers the lookups, every other method panics through the nil interface.
+type fakeUserRepo struct {
+	repository.UserRepository
+	users []*entity.User
+}
+
+func (f *fakeUserRepo) FindByID(ctx context.Context, id uint) (*entity.User, error) {
+	for _, v := range f.users {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return nil, handling.ErrorIdNotFound
+}
+
+func (f *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, v := range f.users {
+		if v.Email == email {
+			return v, nil
+		}
+	}
+	return nil, handling.ErrorEmailNotFound
+}
+
+func (f *fakeUserRepo) UpdateRole(ctx context.Context, id uint, role string) (*entity.User, error) {
+	user, err := f.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+	return user, nil
+}
+
+// fakeRoleRepo knows the roles in permissions and nothing else.
+type fakeRoleRepo struct {
+	repository.RoleRepository
+	permissions map[string][]string
+}
+
+func (f *fakeRoleRepo) FindByName(ctx context.Context, name string) (*entity.Role, error) {
+	if _, ok := f.permissions[name]; !ok {
+		return nil, handling.ErrRoleNotFound
+	}
+	return &entity.Role{Name: name}, nil
+}
+
+func (f *fakeRoleRepo) PermissionsByRole(ctx context.Context, name string) ([]string, error) {
+	return f.permissions[name], nil
+}
+
+type fakeSettingRepo struct {
+	values map[string]string
+}
+
+func (f *fakeSettingRepo) Get(ctx context.Context, key, fallback string) (string, error) {
+	if value, ok := f.values[key]; ok {
+		return value, nil
+	}
+	return fallback, nil
+}
+
+func (f *fakeSettingRepo) Set(ctx context.Context, key, value string) error {
+	f.values[key] = value
+	return nil
+}
+
+// fakeTwoFactorRepo rejects every recovery code and records revocations.
+type fakeTwoFactorRepo struct {
+	repository.TwoFactorRepository
+	revoked []string
+}
+
+func (f *fakeTwoFactorRepo) UseRecoveryCode(ctx context.Context, userID uint, codeHash string, now time.Time) error {
+	return handling.ErrInvalidTwoFactorCode
+}
+
+func (f *fakeTwoFactorRepo) RevokeUnenrolled(ctx context.Context, role string) error {
+	f.revoked = append(f.revoked, role)
+	return nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/handling"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type FavoriteService interface {
+	AddFavorite(ctx context.Context, req *dto.FavoriteReq) error
+	RemoveFavorite(ctx context.Context, req *dto.FavoriteReq) error
+	FindFavorites(ctx context.Context, userID uint) ([]dto.FavoriteResponse, error)
+	CreateList(ctx context.Context, userID uint, req *dto.SavedListReq) (*dto.SavedListResponse, error)
+	UpdateList(ctx context.Context, userID uint, req *dto.SavedListReq) (*dto.SavedListResponse, error)
+	DeleteList(ctx context.Context, userID, id uint) error
+	FindList(ctx context.Context, userID, id uint) (*dto.SavedListResponse, error)
+	FindLists(ctx context.Context, userID uint) ([]dto.SavedListResponse, error)
+	AddListToCart(ctx context.Context, userID, id uint) (*dto.CartResponse, error)
+}
+
+type favoriteServiceImpl struct {
+	FavoriteRepo repository.FavoriteRepository
+	CartService  CartService
+	Validate     *validator.Validate
+}
+
+func NewFavoriteServiceImpl(favoriteRepo repository.FavoriteRepository, cartService CartService, validate *validator.Validate) *favoriteServiceImpl {
+	return &favoriteServiceImpl{
+		FavoriteRepo: favoriteRepo,
+		CartService:  cartService,
+		Validate:     validate,
+	}
+}
+
+func (f *favoriteServiceImpl) AddFavorite(ctx context.Context, req *dto.FavoriteReq) error {
+	if err := f.Validate.Struct(req); err != nil {
+		return handling.ErrorValidation
+	}
+
+	if err := f.FavoriteRepo.AddFavorite(ctx, req.UserID, req.MenuID); err != nil {
+		if errors.Is(err, handling.ErrMenuNotFound) {
+			return handling.ErrMenuNotFound
+		}
+		return fmt.Errorf("favorite service: add favorite: %w", err)
+	}
+
+	return nil
+}
+
+func (f *favoriteServiceImpl) RemoveFavorite(ctx context.Context, req *dto.FavoriteReq) error {
+	if err := f.Validate.Struct(req); err != nil {
+		return handling.ErrorValidation
+	}
+
+	if err := f.FavoriteRepo.RemoveFavorite(ctx, req.UserID, req.MenuID); err != nil {
+		if errors.Is(err, handling.ErrFavoriteNotFound) {
+			return handling.ErrFavoriteNotFound
+		}
+		return fmt.Errorf("favorite service: remove favorite: %w", err)
+	}
+
+	return nil
+}
+
+func (f *favoriteServiceImpl) FindFavorites(ctx context.Context, userID uint) ([]dto.FavoriteResponse, error) {
+	favorites, err := f.FavoriteRepo.FindFavorites(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("favorite service: find favorites: %w", err)
+	}
+
+	responses := make([]dto.FavoriteResponse, 0, len(favorites))
+	for _, v := range favorites {
+		responses = append(responses, dto.ToFavoriteResponse(v))
+	}
+
+	return responses, nil
+}
+
+// toSavedList checks the request, a menu can only be once in a list.
+func (f *favoriteServiceImpl) toSavedList(userID uint, req *dto.SavedListReq) (*entity.SavedList, error) {
+	if err := f.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	list := entity.SavedList{ID: req.ID, UserID: userID, Name: strings.TrimSpace(req.Name)}
+	seen := map[uint]bool{}
+	for _, v := range req.Items {
+		if seen[v.MenuID] {
+			return nil, handling.ErrorValidation
+		}
+		seen[v.MenuID] = true
+
+		list.Items = append(list.Items, entity.SavedListItem{MenuID: v.MenuID, Qty: v.Qty})
+	}
+
+	if list.Name == "" {
+		return nil, handling.ErrorValidation
+	}
+
+	return &list, nil
+}
+
+func (f *favoriteServiceImpl) CreateList(ctx context.Context, userID uint, req *dto.SavedListReq) (*dto.SavedListResponse, error) {
+	list, err := f.toSavedList(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := f.FavoriteRepo.CreateList(ctx, list)
+	if err != nil {
+		if errors.Is(err, handling.ErrMenuNotFound) || errors.Is(err, handling.ErrListExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("favorite service: create list: %w", err)
+	}
+
+	response := dto.ToSavedListResponse(result)
+	return &response, nil
+}
+
+func (f *favoriteServiceImpl) UpdateList(ctx context.Context, userID uint, req *dto.SavedListReq) (*dto.SavedListResponse, error) {
+	list, err := f.toSavedList(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := f.FavoriteRepo.UpdateList(ctx, list)
+	if err != nil {
+		if errors.Is(err, handling.ErrMenuNotFound) || errors.Is(err, handling.ErrListExist) || errors.Is(err, handling.ErrListNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("favorite service: update list: %w", err)
+	}
+
+	response := dto.ToSavedListResponse(result)
+	return &response, nil
+}
+
+func (f *favoriteServiceImpl) DeleteList(ctx context.Context, userID, id uint) error {
+	if err := f.FavoriteRepo.DeleteList(ctx, userID, id); err != nil {
+		if errors.Is(err, handling.ErrListNotFound) {
+			return handling.ErrListNotFound
+		}
+		return fmt.Errorf("favorite service: delete list: %w", err)
+	}
+
+	return nil
+}
+
+func (f *favoriteServiceImpl) FindList(ctx context.Context, userID, id uint) (*dto.SavedListResponse, error) {
+	list, err := f.FavoriteRepo.FindList(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrListNotFound) {
+			return nil, handling.ErrListNotFound
+		}
+		return nil, fmt.Errorf("favorite service: find list: %w", err)
+	}
+
+	response := dto.ToSavedListResponse(list)
+	return &response, nil
+}
+
+func (f *favoriteServiceImpl) FindLists(ctx context.Context, userID uint) ([]dto.SavedListResponse, error) {
+	lists, err := f.FavoriteRepo.FindLists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("favorite service: find lists: %w", err)
+	}
+
+	responses := make([]dto.SavedListResponse, 0, len(lists))
+	for _, v := range lists {
+		responses = append(responses, dto.ToSavedListResponse(v))
+	}
+
+	return responses, nil
+}
+
+// AddListToCart opens a cart with the whole list, it goes through CreateCart so a deleted menu or
+// missing stock fails it the same way.
+func (f *favoriteServiceImpl) AddListToCart(ctx context.Context, userID, id uint) (*dto.CartResponse, error) {
+	list, err := f.FavoriteRepo.FindList(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrListNotFound) {
+			return nil, handling.ErrListNotFound
+		}
+		return nil, fmt.Errorf("favorite service: add list to cart: %w", err)
+	}
+
+	req := dto.CartCreateReq{UserID: userID, CartMenu: make([]dto.CreateMenuItem, 0, len(list.Items))}
+	for _, v := range list.Items {
+		req.CartMenu = append(req.CartMenu, dto.CreateMenuItem{MenuID: v.MenuID, Qty: v.Qty})
+	}
+
+	return f.CartService.CreateCart(ctx, &req)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"online-food/dto"
+	"online-food/repository"
+	"online-food/utils/broker"
+	"online-food/utils/constanta"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// kitchenHistory is how many messages a display can miss and still resume without a new snapshot.
+const kitchenHistory = 256
+
+type KitchenDisplayService interface {
+	Run(ctx context.Context)
+	Attach(ctx context.Context, resume string) ([]dto.KitchenMessage, <-chan dto.KitchenMessage, func(), error)
+}
+
+type kitchenEntry struct {
+	seq     uint64
+	message dto.KitchenMessage
+}
+
+type kitchenDisplayServiceImpl struct {
+	OrderRepo repository.OrderRepository
+	Broker    broker.Broker
+
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	history []kitchenEntry
+	clients map[chan dto.KitchenMessage]struct{}
+}
+
+func NewKitchenDisplayServiceImpl(orderRepo repository.OrderRepository, broker broker.Broker) *kitchenDisplayServiceImpl {
+	return &kitchenDisplayServiceImpl{
+		OrderRepo: orderRepo,
+		Broker:    broker,
+		epoch:     strconv.FormatInt(time.Now().UnixNano(), 36),
+		clients:   map[chan dto.KitchenMessage]struct{}{},
+	}
+}
+
+// Run turns order events into display messages until ctx is done.
+func (k *kitchenDisplayServiceImpl) Run(ctx context.Context) {
+	events, cancel := k.Broker.Subscribe()
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			message, ok, err := k.translate(ctx, event)
+			if err != nil {
+				log.Printf("kitchen display service: %s for order %d: %v", event.Type, event.OrderID, err)
+				continue
+			}
+
+			if ok {
+				k.emit(message)
+			}
+		}
+	}
+}
+
+// translate keeps what the kitchen cares about: orders coming in once paid and released, bumps and recalls,
+// and orders leaving the queue.
+func (k *kitchenDisplayServiceImpl) translate(ctx context.Context, event broker.OrderEvent) (dto.KitchenMessage, bool, error) {
+	switch event.Type {
+	case broker.OrderItemBump:
+		return dto.KitchenMessage{Type: constanta.KitchenItemBumped, OrderID: event.OrderID, ItemIDs: event.ItemIDs}, true, nil
+	case broker.OrderRecalled:
+		order, err := k.OrderRepo.FindByID(ctx, event.OrderID)
+		if err != nil {
+			return dto.KitchenMessage{}, false, err
+		}
+
+		display := dto.ToKitchenOrder(order)
+		return dto.KitchenMessage{Type: constanta.KitchenOrderRecalled, OrderID: order.ID, ItemIDs: event.ItemIDs, Order: &display}, true, nil
+	case broker.OrderReleased:
+		if event.Status != constanta.Paid && event.Status != constanta.Preparing {
+			return dto.KitchenMessage{}, false, nil
+		}
+
+		order, err := k.OrderRepo.FindByID(ctx, event.OrderID)
+		if err != nil {
+			return dto.KitchenMessage{}, false, err
+		}
+
+		display := dto.ToKitchenOrder(order)
+		return dto.KitchenMessage{Type: constanta.KitchenOrderAdded, OrderID: order.ID, Order: &display}, true, nil
+	case broker.OrderCreated, broker.OrderStatus:
+		switch event.Status {
+		case constanta.Paid:
+			order, err := k.OrderRepo.FindByID(ctx, event.OrderID)
+			if err != nil {
+				return dto.KitchenMessage{}, false, err
+			}
+
+			//a pre-order paid ahead shows up once it is released
+			if order.ReleasedAt == nil {
+				return dto.KitchenMessage{}, false, nil
+			}
+
+			display := dto.ToKitchenOrder(order)
+			return dto.KitchenMessage{Type: constanta.KitchenOrderAdded, OrderID: order.ID, Order: &display}, true, nil
+		case constanta.Pending, constanta.Preparing:
+			return dto.KitchenMessage{}, false, nil
+		default:
+			if event.Type == broker.OrderCreated {
+				return dto.KitchenMessage{}, false, nil
+			}
+			return dto.KitchenMessage{Type: constanta.KitchenOrderRemoved, OrderID: event.OrderID, Status: event.Status}, true, nil
+		}
+	}
+
+	return dto.KitchenMessage{}, false, nil
+}
+
+func (k *kitchenDisplayServiceImpl) cursor(seq uint64) string {
+	return k.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+// emit numbers the message and sends it to every display. A display that can't keep up is
+// disconnected, it resumes from its cursor instead of silently missing a message.
+func (k *kitchenDisplayServiceImpl) emit(message dto.KitchenMessage) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.seq++
+	message.Cursor = k.cursor(k.seq)
+
+	k.history = append(k.history, kitchenEntry{seq: k.seq, message: message})
+	if len(k.history) > kitchenHistory {
+		k.history = k.history[len(k.history)-kitchenHistory:]
+	}
+
+	for ch := range k.clients {
+		select {
+		case ch <- message:
+		default:
+			delete(k.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// replay returns what came after the cursor, false when the cursor is from another run or too old.
+func (k *kitchenDisplayServiceImpl) replay(resume string) ([]dto.KitchenMessage, bool) {
+	epoch, value, found := strings.Cut(resume, "-")
+	if !found || epoch != k.epoch {
+		return nil, false
+	}
+
+	seq, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || seq > k.seq {
+		return nil, false
+	}
+
+	//the message right after the cursor has to still be in the history
+	if seq < k.seq && (len(k.history) == 0 || k.history[0].seq > seq+1) {
+		return nil, false
+	}
+
+	messages := []dto.KitchenMessage{}
+	for _, v := range k.history {
+		if v.seq > seq {
+			messages = append(messages, v.message)
+		}
+	}
+
+	return messages, true
+}
+
+// Attach registers a display. With a usable resume cursor it gets what it missed, otherwise it starts
+// from a snapshot of the paid and preparing orders.
+func (k *kitchenDisplayServiceImpl) Attach(ctx context.Context, resume string) ([]dto.KitchenMessage, <-chan dto.KitchenMessage, func(), error) {
+	ch := make(chan dto.KitchenMessage, 64)
+
+	k.mu.Lock()
+	k.clients[ch] = struct{}{}
+	messages, resumed := k.replay(resume)
+	cursor := k.cursor(k.seq)
+	k.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			k.mu.Lock()
+			defer k.mu.Unlock()
+			if _, ok := k.clients[ch]; ok {
+				delete(k.clients, ch)
+				close(ch)
+			}
+		})
+	}
+
+	if resumed {
+		return messages, ch, cancel, nil
+	}
+
+	//messages emitted while the snapshot loads are also queued, applying them again is harmless
+	orders, err := k.OrderRepo.FindKitchenQueue(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, nil, fmt.Errorf("kitchen display service: snapshot: %w", err)
+	}
+
+	snapshot := dto.KitchenMessage{Type: constanta.KitchenSnapshot, Cursor: cursor, Orders: make([]dto.KitchenOrder, 0, len(orders))}
+	for _, v := range orders {
+		snapshot.Orders = append(snapshot.Orders, dto.ToKitchenOrder(v))
+	}
+
+	return []dto.KitchenMessage{snapshot}, ch, cancel, nil
+}
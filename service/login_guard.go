@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"online-food/repository"
+	"online-food/utils/handling"
+	"os"
+	"strconv"
+	"time"
+)
+
+// loginGuard counts failed logins per account and per ip.
+// Every failure locks the account for an exponentially growing delay, reaching the max attempts locks it for the full lockout.
+// The ip is shared by everyone behind the same NAT, so it is only locked once it reaches its own max attempts.
+type loginGuard struct {
+	AttemptRepo   repository.LoginAttemptRepository
+	MaxAttempts   int64
+	IpMaxAttempts int64
+	Lockout       time.Duration
+	Window        time.Duration
+}
+
+func newLoginGuard(attemptRepo repository.LoginAttemptRepository) *loginGuard {
+	return &loginGuard{
+		AttemptRepo:   attemptRepo,
+		MaxAttempts:   int64(envInt("LOGIN_MAX_ATTEMPTS", 5)),
+		IpMaxAttempts: int64(envInt("LOGIN_IP_MAX_ATTEMPTS", 50)),
+		Lockout:       time.Duration(envInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		Window:        time.Duration(envInt("LOGIN_ATTEMPT_WINDOW_MINUTES", 60)) * time.Minute,
+	}
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+func accountKey(email string) string {
+	return "user:" + email
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func (l *loginGuard) Check(ctx context.Context, email, ip string) error {
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		wait, err := l.AttemptRepo.LockedFor(ctx, key)
+		if err != nil {
+			return fmt.Errorf("login guard: check: %w", err)
+		}
+
+		if wait > 0 {
+			return handling.ErrTooManyAttempts
+		}
+	}
+
+	return nil
+}
+
+func (l *loginGuard) Fail(ctx context.Context, email, ip string) error {
+	guards := []struct {
+		key   string
+		delay func(count int64) time.Duration
+	}{
+		{accountKey(email), func(count int64) time.Duration { return l.backoff(count, l.MaxAttempts) }},
+		{ipKey(ip), l.ipLock},
+	}
+
+	for _, v := range guards {
+		count, err := l.AttemptRepo.AddFailure(ctx, v.key, l.Window)
+		if err != nil {
+			return fmt.Errorf("login guard: add failure: %w", err)
+		}
+
+		//a zero duration would never expire in redis
+		delay := v.delay(count)
+		if delay <= 0 {
+			continue
+		}
+
+		if err := l.AttemptRepo.Lock(ctx, v.key, delay); err != nil {
+			return fmt.Errorf("login guard: lock: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (l *loginGuard) Success(ctx context.Context, email string) error {
+	if err := l.AttemptRepo.Clear(ctx, accountKey(email)); err != nil {
+		return fmt.Errorf("login guard: clear: %w", err)
+	}
+	return nil
+}
+
+func (l *loginGuard) Unlock(ctx context.Context, email string) error {
+	return l.Success(ctx, email)
+}
+
+func (l *loginGuard) backoff(count, max int64) time.Duration {
+	if count >= max || count > 20 {
+		return l.Lockout
+	}
+
+	//1s, 2s, 4s, ... capped by the lockout
+	delay := time.Second << (count - 1)
+	if delay > l.Lockout {
+		return l.Lockout
+	}
+
+	return delay
+}
+
+func (l *loginGuard) ipLock(count int64) time.Duration {
+	if count >= l.IpMaxAttempts {
+		return l.Lockout
+	}
+	return 0
+}
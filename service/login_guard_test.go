@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/utils/handling"
+	"testing"
+	"time"
+)
+
+func TestLoginGuardBackoff(t *testing.T) {
+	guard := &loginGuard{Lockout: 15 * time.Minute}
+
+	tests := []struct {
+		count, max int64
+		want       time.Duration
+	}{
+		{1, 5, time.Second},
+		{2, 5, 2 * time.Second},
+		{4, 5, 8 * time.Second},
+		{5, 5, 15 * time.Minute},
+		{9, 5, 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := guard.backoff(tt.count, tt.max); got != tt.want {
+			t.Errorf("backoff(%d, %d) = %v, want %v", tt.count, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuardIpLock(t *testing.T) {
+	guard := &loginGuard{IpMaxAttempts: 50, Lockout: 15 * time.Minute}
+
+	tests := []struct {
+		count int64
+		want  time.Duration
+	}{
+		{1, 0},
+		{11, 0},
+		{49, 0},
+		{50, 15 * time.Minute},
+		{51, 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := guard.ipLock(tt.count); got != tt.want {
+			t.Errorf("ipLock(%d) = %v, want %v", tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuardSharedIp(t *testing.T) {
+	attempts := newFakeAttemptRepo()
+	guard := &loginGuard{AttemptRepo: attempts, MaxAttempts: 5, IpMaxAttempts: 50, Lockout: 15 * time.Minute, Window: time.Hour}
+	ctx := context.Background()
+
+	//49 typos from different people behind one NAT
+	for i := 0; i < 49; i++ {
+		email := fmt.Sprintf("user%d@mail.com", i)
+		if err := guard.Fail(ctx, email, "10.0.0.1"); err != nil {
+			t.Fatalf("attempt %d: fail: %v", i+1, err)
+		}
+	}
+
+	if err := guard.Check(ctx, "siti@mail.com", "10.0.0.1"); err != nil {
+		t.Fatalf("ip should still be open at 49 failures, got %v", err)
+	}
+
+	if err := guard.Fail(ctx, "siti@mail.com", "10.0.0.1"); err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+
+	if err := guard.Check(ctx, "ani@mail.com", "10.0.0.1"); !errors.Is(err, handling.ErrTooManyAttempts) {
+		t.Fatalf("ip should be locked at 50 failures, got %v", err)
+	}
+}
+
+func TestLoginGuardLockout(t *testing.T) {
+	attempts := newFakeAttemptRepo()
+	guard := &loginGuard{AttemptRepo: attempts, MaxAttempts: 3, IpMaxAttempts: 10, Lockout: 15 * time.Minute, Window: time.Hour}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		attempts.unlock()
+		if err := guard.Check(ctx, "budi@mail.com", "10.0.0.1"); err != nil {
+			t.Fatalf("attempt %d: check: %v", i+1, err)
+		}
+		if err := guard.Fail(ctx, "budi@mail.com", "10.0.0.1"); err != nil {
+			t.Fatalf("attempt %d: fail: %v", i+1, err)
+		}
+		if err := guard.Check(ctx, "budi@mail.com", "10.0.0.1"); !errors.Is(err, handling.ErrTooManyAttempts) {
+			t.Fatalf("attempt %d: expected a lock, got %v", i+1, err)
+		}
+	}
+
+	if got := attempts.locks[accountKey("budi@mail.com")]; got != 15*time.Minute {
+		t.Fatalf("account lock = %v, want the full lockout", got)
+	}
+
+	if got, locked := attempts.locks[ipKey("10.0.0.1")]; locked {
+		t.Fatalf("ip lock = %v, want none below the ip max attempts", got)
+	}
+
+	//another account from another ip is not held up
+	if err := guard.Check(ctx, "siti@mail.com", "10.0.0.2"); err != nil {
+		t.Fatalf("other account: %v", err)
+	}
+
+	if err := guard.Unlock(ctx, "budi@mail.com"); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+
+	if _, locked := attempts.locks[accountKey("budi@mail.com")]; locked || attempts.failures[accountKey("budi@mail.com")] != 0 {
+		t.Fatal("unlock should clear the account")
+	}
+}
@@ -16,22 +16,43 @@ type MenuService interface {
 	Create(ctx context.Context, req *dto.MenuCreateReq) (*dto.MenuResponse, error)
 	Update(ctx context.Context, req *dto.MenuUpdateReq) (*dto.MenuResponse, error)
 	Delete(ctx context.Context, id uint) error
-	FindByID(ctx context.Context, id uint) (*dto.MenuResponse, error)
-	FindAll(ctx context.Context) ([]*dto.MenuResponse, error)
+	FindByID(ctx context.Context, id, userID uint) (*dto.MenuResponse, error)
+	FindAll(ctx context.Context, userID uint) ([]*dto.MenuResponse, error)
 }
 
 type menuServiceImpl struct {
-	MenuRepo repository.MenuRepository
-	Validate *validator.Validate
+	MenuRepo     repository.MenuRepository
+	FavoriteRepo repository.FavoriteRepository
+	Validate     *validator.Validate
 }
 
-func NewMenuServiceImpl(menuRepo repository.MenuRepository, validate *validator.Validate) *menuServiceImpl {
+func NewMenuServiceImpl(menuRepo repository.MenuRepository, favoriteRepo repository.FavoriteRepository, validate *validator.Validate) *menuServiceImpl {
 	return &menuServiceImpl{
-		MenuRepo: menuRepo,
-		Validate: validate,
+		MenuRepo:     menuRepo,
+		FavoriteRepo: favoriteRepo,
+		Validate:     validate,
 	}
 }
 
+// markFavorites sets is_favorite on the menus the user starred.
+func (m *menuServiceImpl) markFavorites(ctx context.Context, userID uint, menus ...*dto.MenuResponse) error {
+	ids := make([]uint, 0, len(menus))
+	for _, v := range menus {
+		ids = append(ids, v.ID)
+	}
+
+	starred, err := m.FavoriteRepo.FavoriteMenuIDs(ctx, userID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range menus {
+		v.IsFavorite = starred[v.ID]
+	}
+
+	return nil
+}
+
 func (m *menuServiceImpl) Create(ctx context.Context, req *dto.MenuCreateReq) (*dto.MenuResponse, error) {
 	if err := m.Validate.Struct(req); err != nil {
 		return nil, handling.ErrorValidation
@@ -107,7 +128,7 @@ func (m *menuServiceImpl) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
-func (m *menuServiceImpl) FindByID(ctx context.Context, id uint) (*dto.MenuResponse, error) {
+func (m *menuServiceImpl) FindByID(ctx context.Context, id, userID uint) (*dto.MenuResponse, error) {
 	result, err := m.MenuRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, handling.ErrorIdNotFound) {
@@ -117,10 +138,14 @@ func (m *menuServiceImpl) FindByID(ctx context.Context, id uint) (*dto.MenuRespo
 	}
 
 	response := dto.ToMenuResponse(result)
+	if err := m.markFavorites(ctx, userID, response); err != nil {
+		return nil, fmt.Errorf("menu service: find id: favorites: %w", err)
+	}
+
 	return response, nil
 }
 
-func (m *menuServiceImpl) FindAll(ctx context.Context) ([]*dto.MenuResponse, error) {
+func (m *menuServiceImpl) FindAll(ctx context.Context, userID uint) ([]*dto.MenuResponse, error) {
 	result, err := m.MenuRepo.FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("menu service: find all: %w", err)
@@ -130,5 +155,9 @@ func (m *menuServiceImpl) FindAll(ctx context.Context) ([]*dto.MenuResponse, err
 	for _, v := range result {
 		responses = append(responses, dto.ToMenuResponse(v))
 	}
+
+	if err := m.markFavorites(ctx, userID, responses...); err != nil {
+		return nil, fmt.Errorf("menu service: find all: favorites: %w", err)
+	}
 	return responses, nil
 }
@@ -0,0 +1,507 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/broker"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/invoice"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type OrderService interface {
+	FindByID(ctx context.Context, id uint) (*dto.OrderResponse, error)
+	FindAll(ctx context.Context) ([]*dto.OrderResponse, error)
+	FindByUserID(ctx context.Context, userID uint) ([]*dto.OrderResponse, error)
+	UpdateStatus(ctx context.Context, req *dto.OrderStatusReq) (*dto.OrderResponse, error)
+	AssignCourier(ctx context.Context, req *dto.OrderAssignCourierReq) (*dto.OrderResponse, error)
+	KitchenOrders(ctx context.Context) ([]*dto.OrderResponse, error)
+	KitchenUpdateStatus(ctx context.Context, req *dto.OrderStatusReq) (*dto.OrderResponse, error)
+	CashierOrders(ctx context.Context) ([]*dto.OrderResponse, error)
+	CashierMarkPaid(ctx context.Context, id uint) (*dto.OrderResponse, error)
+	CourierOrders(ctx context.Context, courierID uint) ([]*dto.OrderResponse, error)
+	CourierUpdateStatus(ctx context.Context, courierID uint, req *dto.OrderStatusReq) (*dto.OrderResponse, error)
+	Invoice(ctx context.Context, req *dto.InvoiceReq) (*dto.InvoiceFile, error)
+	Subscribe(req *dto.OrderStreamReq) (<-chan broker.OrderEvent, func())
+	KitchenBump(ctx context.Context, req *dto.KitchenItemReq) (*dto.OrderResponse, error)
+	KitchenRecall(ctx context.Context, req *dto.KitchenItemReq) (*dto.OrderResponse, error)
+}
+
+type orderServiceImpl struct {
+	OrderRepo repository.OrderRepository
+	UserRepo  repository.UserRepository
+	Broker    broker.Broker
+	Validate  *validator.Validate
+}
+
+func NewOrderServiceImpl(orderRepo repository.OrderRepository, userRepo repository.UserRepository, broker broker.Broker, validate *validator.Validate) *orderServiceImpl {
+	return &orderServiceImpl{
+		OrderRepo: orderRepo,
+		UserRepo:  userRepo,
+		Broker:    broker,
+		Validate:  validate,
+	}
+}
+
+// publishOrder tells the streams about a change that is already saved, so a failure is only logged.
+func publishOrder(ctx context.Context, b broker.Broker, eventType string, order *entity.Order, from string, itemIDs ...uint) {
+	event := broker.OrderEvent{
+		ItemIDs:   itemIDs,
+		Type:      eventType,
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		CourierID: order.CourierID,
+		From:      from,
+		Status:    order.Status,
+		At:        time.Now().UTC(),
+	}
+
+	if err := b.Publish(ctx, event); err != nil {
+		log.Printf("publish %s for order %d: %v", eventType, order.ID, err)
+	}
+}
+
+func toOrderResponses(orders []*entity.Order) []*dto.OrderResponse {
+	responses := make([]*dto.OrderResponse, 0, len(orders))
+	for _, v := range orders {
+		responses = append(responses, dto.ToOrderResponse(v))
+	}
+	return responses
+}
+
+func (o *orderServiceImpl) findOrder(ctx context.Context, id uint) (*entity.Order, error) {
+	order, err := o.OrderRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("find order: %w", err)
+	}
+
+	return order, nil
+}
+
+// transition moves the order through the state machine, allowed limits which target statuses the caller may set.
+func (o *orderServiceImpl) transition(ctx context.Context, order *entity.Order, to string, allowed ...string) (*dto.OrderResponse, error) {
+	if len(allowed) > 0 {
+		permitted := false
+		for _, v := range allowed {
+			if v == to {
+				permitted = true
+				break
+			}
+		}
+
+		if !permitted {
+			return nil, handling.ErrInvalidOrderStatus
+		}
+	}
+
+	result, err := o.move(ctx, order, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToOrderResponse(result)
+	return response, nil
+}
+
+// move saves a status change allowed by the state machine and tells the streams about it.
+func (o *orderServiceImpl) move(ctx context.Context, order *entity.Order, to string) (*entity.Order, error) {
+	if !canTransition(order.Status, to) {
+		return nil, handling.ErrInvalidOrderStatus
+	}
+
+	result, err := o.OrderRepo.UpdateStatus(ctx, order.ID, order.Status, to)
+	if err != nil {
+		if errors.Is(err, handling.ErrInvalidOrderStatus) {
+			return nil, handling.ErrInvalidOrderStatus
+		}
+		return nil, fmt.Errorf("update order status: %w", err)
+	}
+
+	publishOrder(ctx, o.Broker, broker.OrderStatus, result, order.Status)
+	return result, nil
+}
+
+func (o *orderServiceImpl) FindByID(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	order, err := o.findOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.ToOrderResponse(order)
+	return response, nil
+}
+
+func (o *orderServiceImpl) FindAll(ctx context.Context) ([]*dto.OrderResponse, error) {
+	orders, err := o.OrderRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("order service: find all: %w", err)
+	}
+
+	return toOrderResponses(orders), nil
+}
+
+func (o *orderServiceImpl) FindByUserID(ctx context.Context, userID uint) ([]*dto.OrderResponse, error) {
+	orders, err := o.OrderRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("order service: find by user id: %w", err)
+	}
+
+	return toOrderResponses(orders), nil
+}
+
+func (o *orderServiceImpl) UpdateStatus(ctx context.Context, req *dto.OrderStatusReq) (*dto.OrderResponse, error) {
+	if err := o.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	order, err := o.findOrder(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.transition(ctx, order, req.Status)
+}
+
+func (o *orderServiceImpl) AssignCourier(ctx context.Context, req *dto.OrderAssignCourierReq) (*dto.OrderResponse, error) {
+	if err := o.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	courier, err := o.UserRepo.FindByID(ctx, req.CourierID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrNotCourier
+		}
+		return nil, fmt.Errorf("order service: assign courier: find courier: %w", err)
+	}
+
+	if courier.Role != constanta.Courier {
+		return nil, handling.ErrNotCourier
+	}
+
+	order, err := o.findOrder(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status == constanta.Delivered || order.Status == constanta.Cancelled {
+		return nil, handling.ErrInvalidOrderStatus
+	}
+
+	result, err := o.OrderRepo.AssignCourier(ctx, order.ID, courier.ID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("order service: assign courier: %w", err)
+	}
+
+	publishOrder(ctx, o.Broker, broker.OrderCourier, result, "")
+
+	response := dto.ToOrderResponse(result)
+	return response, nil
+}
+
+func (o *orderServiceImpl) KitchenOrders(ctx context.Context) ([]*dto.OrderResponse, error) {
+	orders, err := o.OrderRepo.FindKitchenQueue(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("order service: kitchen orders: %w", err)
+	}
+
+	return toOrderResponses(orders), nil
+}
+
+func (o *orderServiceImpl) KitchenUpdateStatus(ctx context.Context, req *dto.OrderStatusReq) (*dto.OrderResponse, error) {
+	if err := o.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	order, err := o.findOrder(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.transition(ctx, order, req.Status, constanta.Preparing, constanta.Ready)
+}
+
+func (o *orderServiceImpl) CashierOrders(ctx context.Context) ([]*dto.OrderResponse, error) {
+	orders, err := o.OrderRepo.FindByStatus(ctx, constanta.Pending)
+	if err != nil {
+		return nil, fmt.Errorf("order service: cashier orders: %w", err)
+	}
+
+	cash := make([]*entity.Order, 0, len(orders))
+	for _, v := range orders {
+		if v.PaymentMethod == constanta.Cash {
+			cash = append(cash, v)
+		}
+	}
+
+	return toOrderResponses(cash), nil
+}
+
+func (o *orderServiceImpl) CashierMarkPaid(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	order, err := o.findOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.PaymentMethod != constanta.Cash {
+		return nil, handling.ErrNotCashOrder
+	}
+
+	return o.transition(ctx, order, constanta.Paid, constanta.Paid)
+}
+
+func (o *orderServiceImpl) CourierOrders(ctx context.Context, courierID uint) ([]*dto.OrderResponse, error) {
+	orders, err := o.OrderRepo.FindByCourierID(ctx, courierID, constanta.Paid, constanta.Preparing, constanta.Ready, constanta.Delivering)
+	if err != nil {
+		return nil, fmt.Errorf("order service: courier orders: %w", err)
+	}
+
+	return toOrderResponses(orders), nil
+}
+
+func (o *orderServiceImpl) CourierUpdateStatus(ctx context.Context, courierID uint, req *dto.OrderStatusReq) (*dto.OrderResponse, error) {
+	if err := o.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	order, err := o.findOrder(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.CourierID == nil || *order.CourierID != courierID {
+		return nil, handling.ErrOrderNotAssigned
+	}
+
+	return o.transition(ctx, order, req.Status, constanta.Delivering, constanta.Delivered)
+}
+
+// outletProfile is the header printed on invoices and receipts.
+func outletProfile() invoice.Outlet {
+	name := os.Getenv("OUTLET_NAME")
+	if name == "" {
+		name = "Online Food"
+	}
+
+	return invoice.Outlet{
+		Name:    name,
+		Address: os.Getenv("OUTLET_ADDRESS"),
+		Phone:   os.Getenv("OUTLET_PHONE"),
+	}
+}
+
+// Invoice numbers the order on its first invoice and prints it, customers only get their own orders.
+func (o *orderServiceImpl) Invoice(ctx context.Context, req *dto.InvoiceReq) (*dto.InvoiceFile, error) {
+	if err := o.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	order, err := o.findOrder(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.All && order.UserID != req.UserID {
+		return nil, handling.ErrorIdNotFound
+	}
+
+	//unpaid orders print as a proforma, the number is only taken once the money is
+	if order.InvoiceNo == nil && invoice.Numbered(order.Status) {
+		order, err = o.OrderRepo.AssignInvoiceNo(ctx, order.ID, time.Now())
+		if err != nil {
+			if errors.Is(err, handling.ErrorIdNotFound) || errors.Is(err, handling.ErrInvoiceUnavailable) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("order service: invoice: %w", err)
+		}
+	}
+
+	if order.InvoiceNo == nil && order.Status == constanta.Cancelled {
+		return nil, handling.ErrInvoiceUnavailable
+	}
+
+	name := fmt.Sprintf("proforma-%d", order.ID)
+	if order.InvoiceNo != nil {
+		name = strings.ReplaceAll(*order.InvoiceNo, "/", "-")
+	}
+	switch req.Format {
+	case "text":
+		return &dto.InvoiceFile{
+			Name:        name + ".txt",
+			ContentType: "text/plain; charset=utf-8",
+			Body:        []byte(invoice.Text(outletProfile(), order)),
+		}, nil
+	case "escpos":
+		return &dto.InvoiceFile{
+			Name:        name + ".bin",
+			ContentType: "application/octet-stream",
+			Body:        invoice.ESCPOS(outletProfile(), order),
+		}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := invoice.PDF(&buf, outletProfile(), order); err != nil {
+		return nil, fmt.Errorf("order service: invoice: render pdf: %w", err)
+	}
+
+	return &dto.InvoiceFile{
+		Name:        name + ".pdf",
+		ContentType: "application/pdf",
+		Body:        buf.Bytes(),
+	}, nil
+}
+
+// Subscribe streams the order events the caller may see: staff see every order, couriers the orders
+// assigned to them and customers their own.
+func (o *orderServiceImpl) Subscribe(req *dto.OrderStreamReq) (<-chan broker.OrderEvent, func()) {
+	events, cancel := o.Broker.Subscribe()
+	out := make(chan broker.OrderEvent)
+
+	go func() {
+		defer close(out)
+
+		for event := range events {
+			visible := req.All || event.UserID == req.UserID ||
+				(req.Courier && event.CourierID != nil && *event.CourierID == req.UserID)
+			if !visible {
+				continue
+			}
+
+			select {
+			case out <- event:
+			case <-req.Done:
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return out, cancel
+}
+
+// KitchenBump marks items ready. The first bump starts preparing a paid order and the last one makes
+// it ready, both through the state machine.
+func (o *orderServiceImpl) KitchenBump(ctx context.Context, req *dto.KitchenItemReq) (*dto.OrderResponse, error) {
+	if err := o.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	order, err := o.findOrder(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != constanta.Paid && order.Status != constanta.Preparing {
+		return nil, handling.ErrInvalidOrderStatus
+	}
+
+	now := time.Now().UTC()
+	bumped, err := o.OrderRepo.SetItemsReady(ctx, order.ID, req.ItemID, &now)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) || errors.Is(err, handling.ErrOrderItemNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("order service: kitchen bump: %w", err)
+	}
+
+	if len(bumped) > 0 {
+		publishOrder(ctx, o.Broker, broker.OrderItemBump, order, "", bumped...)
+	}
+
+	if order.Status == constanta.Paid {
+		if order, err = o.advance(ctx, order, constanta.Preparing); err != nil {
+			return nil, fmt.Errorf("order service: kitchen bump: %w", err)
+		}
+	}
+
+	order, err = o.findOrder(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ready := true
+	for _, v := range order.Items {
+		if v.ReadyAt == nil {
+			ready = false
+			break
+		}
+	}
+
+	if ready && order.Status == constanta.Preparing {
+		if order, err = o.advance(ctx, order, constanta.Ready); err != nil {
+			return nil, fmt.Errorf("order service: kitchen bump: %w", err)
+		}
+	}
+
+	response := dto.ToOrderResponse(order)
+	return response, nil
+}
+
+// KitchenRecall puts items back in the queue, a ready order that hasn't left goes back to preparing.
+func (o *orderServiceImpl) KitchenRecall(ctx context.Context, req *dto.KitchenItemReq) (*dto.OrderResponse, error) {
+	if err := o.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	order, err := o.findOrder(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.Status != constanta.Preparing && order.Status != constanta.Ready {
+		return nil, handling.ErrInvalidOrderStatus
+	}
+
+	recalled, err := o.OrderRepo.SetItemsReady(ctx, order.ID, req.ItemID, nil)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) || errors.Is(err, handling.ErrOrderItemNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("order service: kitchen recall: %w", err)
+	}
+
+	if order.Status == constanta.Ready && len(recalled) > 0 {
+		if order, err = o.advance(ctx, order, constanta.Preparing); err != nil {
+			return nil, fmt.Errorf("order service: kitchen recall: %w", err)
+		}
+	}
+
+	order, err = o.findOrder(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recalled) > 0 {
+		publishOrder(ctx, o.Broker, broker.OrderRecalled, order, "", recalled...)
+	}
+
+	response := dto.ToOrderResponse(order)
+	return response, nil
+}
+
+// advance is move for changes the kitchen makes as a side effect, when another screen got there
+// first the order is already where it should be.
+func (o *orderServiceImpl) advance(ctx context.Context, order *entity.Order, to string) (*entity.Order, error) {
+	result, err := o.move(ctx, order, to)
+	if errors.Is(err, handling.ErrInvalidOrderStatus) {
+		return o.findOrder(ctx, order.ID)
+	}
+	return result, err
+}
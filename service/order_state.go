@@ -0,0 +1,21 @@
+package service
+
+import "online-food/utils/constanta"
+
+// orderTransitions is the order state machine, every status change goes through canTransition.
+var orderTransitions = map[string][]string{
+	constanta.Pending:    {constanta.Paid, constanta.Cancelled},
+	constanta.Paid:       {constanta.Preparing, constanta.Cancelled},
+	constanta.Preparing:  {constanta.Ready},
+	constanta.Ready:      {constanta.Delivering, constanta.Preparing, constanta.Cancelled},
+	constanta.Delivering: {constanta.Delivered, constanta.Ready},
+}
+
+func canTransition(from, to string) bool {
+	for _, v := range orderTransitions[from] {
+		if v == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type PromotionService interface {
+	Create(ctx context.Context, req *dto.PromotionCreateReq) (*dto.PromotionResponse, error)
+	Update(ctx context.Context, req *dto.PromotionUpdateReq) (*dto.PromotionResponse, error)
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*dto.PromotionResponse, error)
+	FindAll(ctx context.Context) ([]*dto.PromotionResponse, error)
+}
+
+type promotionServiceImpl struct {
+	PromotionRepo repository.PromotionRepository
+	Validate      *validator.Validate
+}
+
+func NewPromotionServiceImpl(promotionRepo repository.PromotionRepository, validate *validator.Validate) *promotionServiceImpl {
+	return &promotionServiceImpl{
+		PromotionRepo: promotionRepo,
+		Validate:      validate,
+	}
+}
+
+// checkPromotion makes sure the fields needed by the rule type are set and clears the ones it ignores.
+func checkPromotion(promotion *entity.Promotion) error {
+	if (promotion.HourStart == "") != (promotion.HourEnd == "") || (promotion.HourStart != "" && promotion.HourStart == promotion.HourEnd) {
+		return handling.ErrorValidation
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.StartsAt.Before(*promotion.EndsAt) {
+		return handling.ErrorValidation
+	}
+
+	switch promotion.Type {
+	case constanta.PromoBogo:
+		if promotion.BuyQty <= 0 || promotion.FreeQty <= 0 {
+			return handling.ErrorValidation
+		}
+		promotion.Percent = 0
+		promotion.SpendAmount, promotion.SaveAmount = 0, 0
+	case constanta.PromoHappyHour:
+		if promotion.Percent <= 0 || promotion.Percent > 100 || promotion.HourStart == "" {
+			return handling.ErrorValidation
+		}
+		promotion.BuyQty, promotion.FreeQty = 0, 0
+		promotion.SpendAmount, promotion.SaveAmount = 0, 0
+	case constanta.PromoSpendSave:
+		//tiers run on the whole cart, a menu or category would never be looked at
+		if promotion.SaveAmount <= 0 || promotion.SpendAmount < promotion.SaveAmount ||
+			promotion.MenuID != nil || promotion.Category != "" {
+			return handling.ErrorValidation
+		}
+		promotion.BuyQty, promotion.FreeQty = 0, 0
+		promotion.Percent = 0
+	default:
+		return handling.ErrorValidation
+	}
+
+	return nil
+}
+
+func (p *promotionServiceImpl) Create(ctx context.Context, req *dto.PromotionCreateReq) (*dto.PromotionResponse, error) {
+	if err := p.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	promotion := entity.Promotion{
+		Name:        strings.TrimSpace(req.Name),
+		Type:        req.Type,
+		MenuID:      req.MenuID,
+		Category:    req.Category,
+		BuyQty:      req.BuyQty,
+		FreeQty:     req.FreeQty,
+		Percent:     req.Percent,
+		HourStart:   req.HourStart,
+		HourEnd:     req.HourEnd,
+		SpendAmount: req.SpendAmount,
+		SaveAmount:  req.SaveAmount,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		Active:      req.Active == nil || *req.Active,
+	}
+
+	if err := checkPromotion(&promotion); err != nil {
+		return nil, err
+	}
+
+	result, err := p.PromotionRepo.Create(ctx, &promotion)
+	if err != nil {
+		if errors.Is(err, handling.ErrMenuNotFound) {
+			return nil, handling.ErrMenuNotFound
+		}
+		return nil, fmt.Errorf("promotion service: create: %w", err)
+	}
+
+	response := dto.ToPromotionResponse(result)
+	return response, nil
+}
+
+func (p *promotionServiceImpl) Update(ctx context.Context, req *dto.PromotionUpdateReq) (*dto.PromotionResponse, error) {
+	if err := p.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	promotion, err := p.PromotionRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, handling.ErrPromotionNotFound) {
+			return nil, handling.ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("promotion service: update: find promotion: %w", err)
+	}
+
+	if req.Name != nil {
+		promotion.Name = strings.TrimSpace(*req.Name)
+	}
+
+	if req.Type != nil {
+		promotion.Type = *req.Type
+	}
+
+	//menu id 0 and category "all" remove the restriction
+	if req.MenuID != nil {
+		promotion.MenuID = req.MenuID
+		if *req.MenuID == 0 {
+			promotion.MenuID = nil
+		}
+	}
+
+	if req.Category != nil {
+		promotion.Category = *req.Category
+		if *req.Category == "all" {
+			promotion.Category = ""
+		}
+	}
+
+	if req.BuyQty != nil {
+		promotion.BuyQty = *req.BuyQty
+	}
+
+	if req.FreeQty != nil {
+		promotion.FreeQty = *req.FreeQty
+	}
+
+	if req.Percent != nil {
+		promotion.Percent = *req.Percent
+	}
+
+	if req.HourStart != nil {
+		promotion.HourStart = *req.HourStart
+	}
+
+	if req.HourEnd != nil {
+		promotion.HourEnd = *req.HourEnd
+	}
+
+	if req.SpendAmount != nil {
+		promotion.SpendAmount = *req.SpendAmount
+	}
+
+	if req.SaveAmount != nil {
+		promotion.SaveAmount = *req.SaveAmount
+	}
+
+	if req.StartsAt != nil {
+		promotion.StartsAt = req.StartsAt
+	}
+
+	if req.EndsAt != nil {
+		promotion.EndsAt = req.EndsAt
+	}
+
+	if req.Active != nil {
+		promotion.Active = *req.Active
+	}
+
+	if err := checkPromotion(promotion); err != nil {
+		return nil, err
+	}
+
+	result, err := p.PromotionRepo.Update(ctx, promotion)
+	if err != nil {
+		if errors.Is(err, handling.ErrPromotionNotFound) || errors.Is(err, handling.ErrMenuNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("promotion service: update: %w", err)
+	}
+
+	response := dto.ToPromotionResponse(result)
+	return response, nil
+}
+
+func (p *promotionServiceImpl) Delete(ctx context.Context, id uint) error {
+	if err := p.PromotionRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, handling.ErrPromotionNotFound) {
+			return handling.ErrPromotionNotFound
+		}
+		return fmt.Errorf("promotion service: delete: %w", err)
+	}
+
+	return nil
+}
+
+func (p *promotionServiceImpl) FindByID(ctx context.Context, id uint) (*dto.PromotionResponse, error) {
+	promotion, err := p.PromotionRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrPromotionNotFound) {
+			return nil, handling.ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("promotion service: find by id: %w", err)
+	}
+
+	response := dto.ToPromotionResponse(promotion)
+	return response, nil
+}
+
+func (p *promotionServiceImpl) FindAll(ctx context.Context) ([]*dto.PromotionResponse, error) {
+	promotions, err := p.PromotionRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("promotion service: find all: %w", err)
+	}
+
+	responses := make([]*dto.PromotionResponse, 0, len(promotions))
+	for _, v := range promotions {
+		responses = append(responses, dto.ToPromotionResponse(v))
+	}
+
+	return responses, nil
+}
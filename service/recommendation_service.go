@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"online-food/dto"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/recommend"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type RecommendationService interface {
+	Run(ctx context.Context)
+	Refresh(ctx context.Context) (*dto.RecommendationRefreshResponse, error)
+	Recommend(ctx context.Context, req *dto.RecommendationReq) (*dto.RecommendationResponse, error)
+}
+
+// recommendationServiceImpl rebuilds the precomputed recommendations and serves them. Clock is a field so
+// a test can pin the history window.
+type recommendationServiceImpl struct {
+	RecommendRepo repository.RecommendationRepository
+	Validate      *validator.Validate
+	Clock         func() time.Time
+	Options       recommend.Options
+	Window        time.Duration
+	Interval      time.Duration
+}
+
+func NewRecommendationServiceImpl(recommendRepo repository.RecommendationRepository, validate *validator.Validate) *recommendationServiceImpl {
+	return &recommendationServiceImpl{
+		RecommendRepo: recommendRepo,
+		Validate:      validate,
+		Clock:         time.Now,
+		Options:       recommend.DefaultOptions,
+		Window:        time.Duration(envInt("RECOMMEND_DAYS", 90)) * 24 * time.Hour,
+		Interval:      time.Duration(envInt("RECOMMEND_REFRESH_MINUTES", 60)) * time.Minute,
+	}
+}
+
+// Run refreshes on start and then every Interval.
+func (r *recommendationServiceImpl) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Refresh(ctx); err != nil {
+			log.Printf("recommendation service: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh recomputes everything from the orders of the last Window.
+func (r *recommendationServiceImpl) Refresh(ctx context.Context) (*dto.RecommendationRefreshResponse, error) {
+	lines, err := r.RecommendRepo.FindBaskets(ctx, r.Clock().Add(-r.Window))
+	if err != nil {
+		return nil, fmt.Errorf("recommendation service: refresh: find baskets: %w", err)
+	}
+
+	result := recommend.Compute(lines, r.Options)
+	if err := r.RecommendRepo.Replace(ctx, result); err != nil {
+		return nil, fmt.Errorf("recommendation service: refresh: replace: %w", err)
+	}
+
+	baskets := map[uint]bool{}
+	for _, v := range lines {
+		baskets[v.CartID] = true
+	}
+
+	return &dto.RecommendationRefreshResponse{
+		Baskets: len(baskets),
+		Pairs:   len(result.Pairs),
+		Users:   len(result.Users),
+		Menus:   len(result.Popular),
+	}, nil
+}
+
+// Recommend serves the user's picks topped up with best sellers, a user without a history only gets best
+// sellers. With a menu id the menus bought together with it are added.
+func (r *recommendationServiceImpl) Recommend(ctx context.Context, req *dto.RecommendationReq) (*dto.RecommendationResponse, error) {
+	if err := r.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 10
+	}
+
+	picks, err := r.RecommendRepo.FindForUser(ctx, req.UserID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("recommendation service: recommend: for user: %w", err)
+	}
+
+	response := &dto.RecommendationResponse{ForYou: []dto.RecommendedMenu{}, Personalised: len(picks) > 0}
+	seen := make([]uint, 0, len(picks))
+	for _, v := range picks {
+		response.ForYou = append(response.ForYou, dto.ToRecommendedMenu(&v.Menu, constanta.RecommendForYou, v.Score))
+		seen = append(seen, v.MenuID)
+	}
+
+	if len(response.ForYou) < limit {
+		popular, err := r.RecommendRepo.FindBestSellers(ctx, limit-len(response.ForYou), seen)
+		if err != nil {
+			return nil, fmt.Errorf("recommendation service: recommend: best sellers: %w", err)
+		}
+
+		for _, v := range popular {
+			response.ForYou = append(response.ForYou, dto.ToRecommendedMenu(&v.Menu, constanta.RecommendBestSeller, float64(v.Orders)))
+		}
+	}
+
+	if req.MenuID == 0 {
+		return response, nil
+	}
+
+	pairs, err := r.RecommendRepo.FindRelated(ctx, req.MenuID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("recommendation service: recommend: related: %w", err)
+	}
+
+	response.BoughtTogether = make([]dto.RecommendedMenu, 0, len(pairs))
+	for _, v := range pairs {
+		response.BoughtTogether = append(response.BoughtTogether, dto.ToRecommendedMenu(&v.Related, constanta.RecommendBoughtTogether, v.Score))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"online-food/repository"
+	"online-food/utils/recommend"
+	"testing"
+	"time"
+)
+
+// fakeRecommendRepo keeps baskets with their order date and returns the ones inside the window.
+type fakeRecommendRepo struct {
+	repository.RecommendationRepository
+	ordered  map[uint]time.Time
+	lines    []recommend.Line
+	replaced recommend.Result
+}
+
+func (f *fakeRecommendRepo) FindBaskets(ctx context.Context, since time.Time) ([]recommend.Line, error) {
+	var lines []recommend.Line
+	for _, v := range f.lines {
+		if !f.ordered[v.CartID].Before(since) {
+			lines = append(lines, v)
+		}
+	}
+	return lines, nil
+}
+
+func (f *fakeRecommendRepo) Replace(ctx context.Context, result recommend.Result) error {
+	f.replaced = result
+	return nil
+}
+
+func TestRecommendationRefreshWindow(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRecommendRepo{
+		ordered: map[uint]time.Time{
+			1: now.AddDate(0, 0, -10),
+			2: now.AddDate(0, 0, -30),
+			3: now.AddDate(0, 0, -31),
+		},
+		lines: []recommend.Line{
+			{UserID: 1, CartID: 1, MenuID: 1, Qty: 1}, {UserID: 1, CartID: 1, MenuID: 2, Qty: 1},
+			{UserID: 2, CartID: 2, MenuID: 1, Qty: 1}, {UserID: 2, CartID: 2, MenuID: 2, Qty: 1},
+			{UserID: 3, CartID: 3, MenuID: 1, Qty: 1}, {UserID: 3, CartID: 3, MenuID: 3, Qty: 1},
+		},
+	}
+
+	service := NewRecommendationServiceImpl(repo, nil)
+	service.Clock = func() time.Time { return now }
+	service.Options = recommend.Options{MinSupport: 1, PerMenu: 10, PerUser: 20}
+
+	tests := []struct {
+		name        string
+		window      time.Duration
+		wantBaskets int
+		wantMenus   int
+	}{
+		{"the whole history", 90 * 24 * time.Hour, 3, 3},
+		{"a basket on the cutoff counts", 30 * 24 * time.Hour, 2, 2},
+		{"older baskets drop out", 20 * 24 * time.Hour, 1, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.Window = tt.window
+
+			result, err := service.Refresh(context.Background())
+			if err != nil {
+				t.Fatalf("refresh: %v", err)
+			}
+
+			if result.Baskets != tt.wantBaskets || result.Menus != tt.wantMenus {
+				t.Fatalf("baskets %d menus %d, want %d and %d", result.Baskets, result.Menus, tt.wantBaskets, tt.wantMenus)
+			}
+
+			for _, v := range repo.replaced.Pairs {
+				if tt.wantMenus < 3 && (v.MenuID == 3 || v.RelatedID == 3) {
+					t.Fatalf("pair %+v comes from a basket outside the window", v)
+				}
+			}
+		})
+	}
+}
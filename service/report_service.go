@@ -0,0 +1,327 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"online-food/dto"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// maxReportDays keeps a single report from scanning years of orders.
+const maxReportDays = 731
+
+var reportStatuses = []string{constanta.Pending, constanta.Paid, constanta.Preparing, constanta.Ready, constanta.Delivering, constanta.Delivered, constanta.Cancelled}
+
+type ReportService interface {
+	Revenue(ctx context.Context, req *dto.ReportReq) (*dto.RevenueReport, error)
+	OrderCounts(ctx context.Context, req *dto.ReportReq) (*dto.OrderCountReport, error)
+	TopMenus(ctx context.Context, req *dto.ReportReq) (*dto.TopMenuReport, error)
+	Summary(ctx context.Context, req *dto.ReportReq) (*dto.SalesSummary, error)
+	Export(ctx context.Context, report string, req *dto.ReportReq) (*dto.ReportFile, error)
+}
+
+// reportServiceImpl reads sales figures, Clock is a field so a test can pin the default range.
+type reportServiceImpl struct {
+	ReportRepo repository.ReportRepository
+	Validate   *validator.Validate
+	Clock      func() time.Time
+}
+
+func NewReportServiceImpl(reportRepo repository.ReportRepository, validate *validator.Validate) *reportServiceImpl {
+	return &reportServiceImpl{
+		ReportRepo: reportRepo,
+		Validate:   validate,
+		Clock:      time.Now,
+	}
+}
+
+// period is a report range resolved on the report's clock, to is the midnight after the last day.
+type period struct {
+	From  time.Time
+	To    time.Time
+	Loc   *time.Location
+	Range dto.ReportRange
+}
+
+// resolve reads the range of the request, the last 30 days up to today when it is left out.
+func (r *reportServiceImpl) resolve(req *dto.ReportReq) (*period, error) {
+	if err := r.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	loc := outletTimezone()
+	if req.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return nil, handling.ErrorValidation
+		}
+	}
+
+	now := r.Clock().In(loc)
+	last := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if req.To != "" {
+		last, _ = time.ParseInLocation("2006-01-02", req.To, loc)
+	}
+
+	first := last.AddDate(0, 0, -29)
+	if req.From != "" {
+		first, _ = time.ParseInLocation("2006-01-02", req.From, loc)
+	}
+
+	//both days are included, so the last allowed day is maxReportDays-1 after the first
+	if first.After(last) || first.AddDate(0, 0, maxReportDays-1).Before(last) {
+		return nil, handling.ErrorValidation
+	}
+
+	return &period{
+		From: first,
+		To:   last.AddDate(0, 0, 1),
+		Loc:  loc,
+		Range: dto.ReportRange{
+			From:     first.Format("2006-01-02"),
+			To:       last.Format("2006-01-02"),
+			Timezone: loc.String(),
+		},
+	}, nil
+}
+
+// sqlOffset is the "+HH:MM" of t in its zone. Buckets use the offset at the start of the range, a daylight
+// saving change inside it shifts orders near midnight by an hour.
+func sqlOffset(t time.Time) string {
+	_, seconds := t.Zone()
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// periods lists the first day of every bucket in the range, so a day without orders still shows up.
+func periods(p *period, group string) []string {
+	start := p.From
+	switch group {
+	case constanta.ReportWeek:
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	case constanta.ReportMonth:
+		start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, p.Loc)
+	}
+
+	keys := []string{}
+	for at := start; at.Before(p.To); {
+		keys = append(keys, at.Format("2006-01-02"))
+		switch group {
+		case constanta.ReportWeek:
+			at = at.AddDate(0, 0, 7)
+		case constanta.ReportMonth:
+			at = at.AddDate(0, 1, 0)
+		default:
+			at = at.AddDate(0, 0, 1)
+		}
+	}
+
+	return keys
+}
+
+func money(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func (r *reportServiceImpl) Revenue(ctx context.Context, req *dto.ReportReq) (*dto.RevenueReport, error) {
+	p, err := r.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+
+	group := req.Group
+	if group == "" {
+		group = constanta.ReportDay
+	}
+
+	rows, err := r.ReportRepo.Revenue(ctx, p.From, p.To, group, sqlOffset(p.From.In(time.Local)), sqlOffset(p.From))
+	if err != nil {
+		return nil, fmt.Errorf("report service: revenue: %w", err)
+	}
+
+	byBucket := make(map[string]repository.RevenueRow, len(rows))
+	for _, v := range rows {
+		byBucket[v.Bucket] = v
+	}
+
+	report := &dto.RevenueReport{ReportRange: p.Range, Group: group, Periods: []dto.RevenueBucket{}}
+	for _, key := range periods(p, group) {
+		row := byBucket[key]
+		report.Periods = append(report.Periods, dto.RevenueBucket{
+			Period:      key,
+			Orders:      row.Orders,
+			Revenue:     money(row.Revenue),
+			Discount:    money(row.Discount),
+			Tax:         money(row.Tax),
+			DeliveryFee: money(row.DeliveryFee),
+		})
+		report.Orders += row.Orders
+		report.Revenue += row.Revenue
+	}
+
+	report.Revenue = money(report.Revenue)
+	return report, nil
+}
+
+func (r *reportServiceImpl) OrderCounts(ctx context.Context, req *dto.ReportReq) (*dto.OrderCountReport, error) {
+	p, err := r.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.ReportRepo.OrderCounts(ctx, p.From, p.To)
+	if err != nil {
+		return nil, fmt.Errorf("report service: order counts: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, v := range rows {
+		counts[v.Status] = v.Orders
+	}
+
+	report := &dto.OrderCountReport{ReportRange: p.Range, Statuses: make([]dto.StatusCount, 0, len(reportStatuses))}
+	for _, v := range reportStatuses {
+		report.Statuses = append(report.Statuses, dto.StatusCount{Status: v, Orders: counts[v]})
+		report.Total += counts[v]
+	}
+
+	return report, nil
+}
+
+func (r *reportServiceImpl) TopMenus(ctx context.Context, req *dto.ReportReq) (*dto.TopMenuReport, error) {
+	p, err := r.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, limit := req.Sort, req.Limit
+	if sort == "" {
+		sort = "qty"
+	}
+	if limit == 0 {
+		limit = 10
+	}
+
+	rows, err := r.ReportRepo.TopMenus(ctx, p.From, p.To, sort, limit)
+	if err != nil {
+		return nil, fmt.Errorf("report service: top menus: %w", err)
+	}
+
+	report := &dto.TopMenuReport{ReportRange: p.Range, Sort: sort, Menus: make([]dto.MenuSales, 0, len(rows))}
+	for _, v := range rows {
+		report.Menus = append(report.Menus, dto.MenuSales{MenuID: v.MenuID, Name: v.Name, Qty: v.Qty, Revenue: money(v.Revenue)})
+	}
+
+	return report, nil
+}
+
+func (r *reportServiceImpl) Summary(ctx context.Context, req *dto.ReportReq) (*dto.SalesSummary, error) {
+	p, err := r.resolve(req)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.ReportRepo.Customers(ctx, p.From, p.To)
+	if err != nil {
+		return nil, fmt.Errorf("report service: summary: %w", err)
+	}
+
+	summary := &dto.SalesSummary{
+		ReportRange:        p.Range,
+		Orders:             row.Orders,
+		Revenue:            money(row.Revenue),
+		Customers:          row.Customers,
+		NewCustomers:       row.NewCustomers,
+		ReturningCustomers: row.Customers - row.NewCustomers,
+	}
+
+	if row.Orders > 0 {
+		summary.AverageOrderValue = money(row.Revenue / float64(row.Orders))
+	}
+
+	return summary, nil
+}
+
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// Export writes a report as csv, one line per period, status or menu.
+func (r *reportServiceImpl) Export(ctx context.Context, report string, req *dto.ReportReq) (*dto.ReportFile, error) {
+	var reportRange dto.ReportRange
+	var records [][]string
+
+	switch report {
+	case constanta.ReportRevenue:
+		result, err := r.Revenue(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		reportRange = result.ReportRange
+		records = append(records, []string{"period", "orders", "revenue", "discount", "tax", "delivery_fee"})
+		for _, v := range result.Periods {
+			records = append(records, []string{v.Period, strconv.Itoa(v.Orders), formatMoney(v.Revenue), formatMoney(v.Discount), formatMoney(v.Tax), formatMoney(v.DeliveryFee)})
+		}
+	case constanta.ReportOrders:
+		result, err := r.OrderCounts(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		reportRange = result.ReportRange
+		records = append(records, []string{"status", "orders"})
+		for _, v := range result.Statuses {
+			records = append(records, []string{v.Status, strconv.Itoa(v.Orders)})
+		}
+	case constanta.ReportMenus:
+		result, err := r.TopMenus(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		reportRange = result.ReportRange
+		records = append(records, []string{"menu_id", "name", "qty", "revenue"})
+		for _, v := range result.Menus {
+			records = append(records, []string{strconv.FormatUint(uint64(v.MenuID), 10), v.Name, strconv.Itoa(v.Qty), formatMoney(v.Revenue)})
+		}
+	case constanta.ReportSummary:
+		result, err := r.Summary(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		reportRange = result.ReportRange
+		records = append(records,
+			[]string{"orders", "revenue", "average_order_value", "customers", "new_customers", "returning_customers"},
+			[]string{strconv.Itoa(result.Orders), formatMoney(result.Revenue), formatMoney(result.AverageOrderValue),
+				strconv.Itoa(result.Customers), strconv.Itoa(result.NewCustomers), strconv.Itoa(result.ReturningCustomers)},
+		)
+	default:
+		return nil, handling.ErrorValidation
+	}
+
+	var body bytes.Buffer
+	writer := csv.NewWriter(&body)
+	if err := writer.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("report service: export: %w", err)
+	}
+
+	return &dto.ReportFile{
+		Name:        fmt.Sprintf("%s-%s-%s.csv", report, reportRange.From, reportRange.To),
+		ContentType: "text/csv; charset=utf-8",
+		Body:        body.Bytes(),
+	}, nil
+}
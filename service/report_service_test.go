@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"online-food/dto"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// fakeReportRepo returns rows and remembers the offsets the buckets were asked for.
+type fakeReportRepo struct {
+	repository.ReportRepository
+	rows             []repository.RevenueRow
+	from, to         time.Time
+	dbOffset, offset string
+}
+
+func (f *fakeReportRepo) Revenue(ctx context.Context, from, to time.Time, group, dbOffset, offset string) ([]repository.RevenueRow, error) {
+	f.from, f.to, f.dbOffset, f.offset = from, to, dbOffset, offset
+	return f.rows, nil
+}
+
+func TestReportResolve(t *testing.T) {
+	t.Setenv("OUTLET_TIMEZONE", "Asia/Jakarta")
+
+	//02:00 in Jakarta is still the evening before in New York
+	now := time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC)
+	service := NewReportServiceImpl(nil, validator.New())
+	service.Clock = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		req     dto.ReportReq
+		want    dto.ReportRange
+		wantErr error
+	}{
+		{"last 30 days on the outlet clock", dto.ReportReq{}, dto.ReportRange{From: "2026-09-20", To: "2026-10-19", Timezone: "Asia/Jakarta"}, nil},
+		{"last 30 days on another clock", dto.ReportReq{Timezone: "America/New_York"}, dto.ReportRange{From: "2026-09-19", To: "2026-10-18", Timezone: "America/New_York"}, nil},
+		{"only to", dto.ReportReq{To: "2026-03-31"}, dto.ReportRange{From: "2026-03-02", To: "2026-03-31", Timezone: "Asia/Jakarta"}, nil},
+		{"one day", dto.ReportReq{From: "2026-10-01", To: "2026-10-01"}, dto.ReportRange{From: "2026-10-01", To: "2026-10-01", Timezone: "Asia/Jakarta"}, nil},
+		{"longest range", dto.ReportReq{From: "2024-01-01", To: "2025-12-31"}, dto.ReportRange{From: "2024-01-01", To: "2025-12-31", Timezone: "Asia/Jakarta"}, nil},
+		{"one day too long", dto.ReportReq{From: "2024-01-01", To: "2026-01-01"}, dto.ReportRange{}, handling.ErrorValidation},
+		{"from after to", dto.ReportReq{From: "2026-10-02", To: "2026-10-01"}, dto.ReportRange{}, handling.ErrorValidation},
+		{"unknown zone", dto.ReportReq{Timezone: "Mars/Olympus"}, dto.ReportRange{}, handling.ErrorValidation},
+		{"bad date", dto.ReportReq{From: "01-10-2026"}, dto.ReportRange{}, handling.ErrorValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := service.resolve(&tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("resolve: %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && p.Range != tt.want {
+				t.Fatalf("range = %+v, want %+v", p.Range, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevenueBuckets(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.UTC
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("load zone: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		req         dto.ReportReq
+		rows        []repository.RevenueRow
+		wantOffset  string
+		wantFrom    time.Time
+		wantPeriods []string
+		wantOrders  int
+	}{
+		{
+			name:        "days without orders are kept",
+			req:         dto.ReportReq{From: "2026-10-01", To: "2026-10-03", Timezone: "Asia/Jakarta"},
+			rows:        []repository.RevenueRow{{Bucket: "2026-10-02", Orders: 2, Revenue: 50000}},
+			wantOffset:  "+07:00",
+			wantFrom:    time.Date(2026, 10, 1, 0, 0, 0, 0, jakarta),
+			wantPeriods: []string{"2026-10-01", "2026-10-02", "2026-10-03"},
+			wantOrders:  2,
+		},
+		{
+			name:        "weeks start on monday",
+			req:         dto.ReportReq{From: "2026-10-01", To: "2026-10-14", Timezone: "Asia/Jakarta", Group: constanta.ReportWeek},
+			wantOffset:  "+07:00",
+			wantFrom:    time.Date(2026, 10, 1, 0, 0, 0, 0, jakarta),
+			wantPeriods: []string{"2026-09-28", "2026-10-05", "2026-10-12"},
+		},
+		{
+			name:        "months",
+			req:         dto.ReportReq{From: "2026-01-15", To: "2026-03-01", Timezone: "UTC", Group: constanta.ReportMonth},
+			wantOffset:  "+00:00",
+			wantFrom:    time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+			wantPeriods: []string{"2026-01-01", "2026-02-01", "2026-03-01"},
+		},
+		{
+			name:        "offset west of utc",
+			req:         dto.ReportReq{From: "2026-01-10", To: "2026-01-10", Timezone: "America/St_Johns"},
+			wantOffset:  "-03:30",
+			wantFrom:    time.Date(2026, 1, 10, 3, 30, 0, 0, time.UTC),
+			wantPeriods: []string{"2026-01-10"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports := &fakeReportRepo{rows: tt.rows}
+			report, err := NewReportServiceImpl(reports, validator.New()).Revenue(context.Background(), &tt.req)
+			if err != nil {
+				t.Fatalf("revenue: %v", err)
+			}
+
+			if reports.dbOffset != "+00:00" || reports.offset != tt.wantOffset {
+				t.Fatalf("offsets %s -> %s, want +00:00 -> %s", reports.dbOffset, reports.offset, tt.wantOffset)
+			}
+
+			if !reports.from.Equal(tt.wantFrom) {
+				t.Fatalf("from = %v, want %v", reports.from, tt.wantFrom)
+			}
+
+			if len(report.Periods) != len(tt.wantPeriods) {
+				t.Fatalf("periods = %+v, want %v", report.Periods, tt.wantPeriods)
+			}
+			for i, v := range report.Periods {
+				if v.Period != tt.wantPeriods[i] {
+					t.Fatalf("periods = %+v, want %v", report.Periods, tt.wantPeriods)
+				}
+			}
+
+			if report.Orders != tt.wantOrders {
+				t.Fatalf("orders = %d, want %d", report.Orders, tt.wantOrders)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type RoleService interface {
+	Create(ctx context.Context, req *dto.RoleCreateReq) (*dto.RoleResponse, error)
+	Update(ctx context.Context, req *dto.RoleUpdateReq) (*dto.RoleResponse, error)
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*dto.RoleResponse, error)
+	FindAll(ctx context.Context) ([]*dto.RoleResponse, error)
+	FindAllPermissions(ctx context.Context) ([]*dto.PermissionResponse, error)
+}
+
+type roleServiceImpl struct {
+	RoleRepo repository.RoleRepository
+	Validate *validator.Validate
+}
+
+func NewRoleServiceImpl(roleRepo repository.RoleRepository, validate *validator.Validate) *roleServiceImpl {
+	return &roleServiceImpl{
+		RoleRepo: roleRepo,
+		Validate: validate,
+	}
+}
+
+func (r *roleServiceImpl) Create(ctx context.Context, req *dto.RoleCreateReq) (*dto.RoleResponse, error) {
+	if err := r.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	role := entity.Role{
+		Name:        strings.ToLower(strings.TrimSpace(req.Name)),
+		Description: req.Description,
+	}
+
+	result, err := r.RoleRepo.Create(ctx, &role, req.Permissions)
+	if err != nil {
+		if errors.Is(err, handling.ErrRoleExist) {
+			return nil, handling.ErrRoleExist
+		}
+
+		if errors.Is(err, handling.ErrUnknownPermission) {
+			return nil, handling.ErrUnknownPermission
+		}
+
+		return nil, fmt.Errorf("role service: create: %w", err)
+	}
+
+	response := dto.ToRoleResponse(result)
+	return response, nil
+}
+
+func (r *roleServiceImpl) Update(ctx context.Context, req *dto.RoleUpdateReq) (*dto.RoleResponse, error) {
+	if err := r.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	role, err := r.RoleRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, handling.ErrRoleNotFound) {
+			return nil, handling.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("role service: update: find id: %w", err)
+	}
+
+	//admin always holds the full catalog, otherwise admins could lock themselves out
+	if role.Name == constanta.Admin {
+		return nil, handling.ErrSystemRole
+	}
+
+	if req.Description != nil {
+		role.Description = *req.Description
+	}
+
+	result, err := r.RoleRepo.Update(ctx, role, req.Permissions)
+	if err != nil {
+		if errors.Is(err, handling.ErrUnknownPermission) {
+			return nil, handling.ErrUnknownPermission
+		}
+		return nil, fmt.Errorf("role service: update: %w", err)
+	}
+
+	response := dto.ToRoleResponse(result)
+	return response, nil
+}
+
+func (r *roleServiceImpl) Delete(ctx context.Context, id uint) error {
+	role, err := r.RoleRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrRoleNotFound) {
+			return handling.ErrRoleNotFound
+		}
+		return fmt.Errorf("role service: delete: find id: %w", err)
+	}
+
+	if role.System {
+		return handling.ErrSystemRole
+	}
+
+	if err := r.RoleRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, handling.ErrRoleNotFound) {
+			return handling.ErrRoleNotFound
+		}
+
+		if errors.Is(err, handling.ErrRoleInUse) {
+			return handling.ErrRoleInUse
+		}
+
+		return fmt.Errorf("role service: delete: %w", err)
+	}
+
+	return nil
+}
+
+func (r *roleServiceImpl) FindByID(ctx context.Context, id uint) (*dto.RoleResponse, error) {
+	result, err := r.RoleRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrRoleNotFound) {
+			return nil, handling.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("role service: find id: %w", err)
+	}
+
+	response := dto.ToRoleResponse(result)
+	return response, nil
+}
+
+func (r *roleServiceImpl) FindAll(ctx context.Context) ([]*dto.RoleResponse, error) {
+	result, err := r.RoleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("role service: find all: %w", err)
+	}
+
+	responses := make([]*dto.RoleResponse, 0, len(result))
+	for _, v := range result {
+		responses = append(responses, dto.ToRoleResponse(v))
+	}
+
+	return responses, nil
+}
+
+func (r *roleServiceImpl) FindAllPermissions(ctx context.Context) ([]*dto.PermissionResponse, error) {
+	result, err := r.RoleRepo.FindAllPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("role service: find all permissions: %w", err)
+	}
+
+	responses := make([]*dto.PermissionResponse, 0, len(result))
+	for _, v := range result {
+		responses = append(responses, dto.ToPermissionResponse(v))
+	}
+
+	return responses, nil
+}
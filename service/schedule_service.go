@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"online-food/dto"
+	"online-food/repository"
+	"online-food/utils/broker"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/schedule"
+	"os"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ScheduleService interface {
+	Run(ctx context.Context)
+	Release(ctx context.Context) ([]uint, error)
+	GetConfig(ctx context.Context) (*dto.ScheduleConfigResponse, error)
+	UpdateConfig(ctx context.Context, req *dto.ScheduleConfigReq) (*dto.ScheduleConfigResponse, error)
+	Slots(ctx context.Context, req *dto.SlotReq) ([]dto.SlotResponse, error)
+}
+
+// scheduleServiceImpl books pre-orders into slots and releases them to the kitchen. Clock is a field so a
+// test can move time.
+type scheduleServiceImpl struct {
+	ScheduleRepo repository.ScheduleRepository
+	OrderRepo    repository.OrderRepository
+	SettingRepo  repository.SettingRepository
+	Broker       broker.Broker
+	Validate     *validator.Validate
+	Clock        func() time.Time
+	Interval     time.Duration
+}
+
+func NewScheduleServiceImpl(scheduleRepo repository.ScheduleRepository, orderRepo repository.OrderRepository, settingRepo repository.SettingRepository, broker broker.Broker, validate *validator.Validate) *scheduleServiceImpl {
+	return &scheduleServiceImpl{
+		ScheduleRepo: scheduleRepo,
+		OrderRepo:    orderRepo,
+		SettingRepo:  settingRepo,
+		Broker:       broker,
+		Validate:     validate,
+		Clock:        time.Now,
+		Interval:     time.Minute,
+	}
+}
+
+// outletTimezone is where the opening hours are read, the server's own zone when OUTLET_TIMEZONE isn't set.
+func outletTimezone() *time.Location {
+	name := os.Getenv("OUTLET_TIMEZONE")
+	if name == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("OUTLET_TIMEZONE %q: %v, using %s", name, err, time.Local)
+		return time.Local
+	}
+
+	return loc
+}
+
+// loadScheduleConfig reads the opening hours and slots from the settings table, nothing configured is
+// schedule.Default.
+func loadScheduleConfig(ctx context.Context, settingRepo repository.SettingRepository) (schedule.Config, error) {
+	config := schedule.Default()
+
+	value, err := settingRepo.Get(ctx, constanta.SettingSchedule, "")
+	if err != nil || value == "" {
+		return config, err
+	}
+
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return config, fmt.Errorf("parse schedule: %w", err)
+	}
+
+	if config.Hours == nil {
+		config.Hours = map[string][]schedule.Window{}
+	}
+
+	return config, nil
+}
+
+// Run releases due pre-orders every Interval.
+func (s *scheduleServiceImpl) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Release(ctx); err != nil {
+			log.Printf("schedule service: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Release hands every pre-order whose slot starts within the lead time to the kitchen and returns their ids.
+func (s *scheduleServiceImpl) Release(ctx context.Context) ([]uint, error) {
+	config, err := loadScheduleConfig(ctx, s.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("schedule service: release: %w", err)
+	}
+
+	now := s.Clock()
+	released, err := s.OrderRepo.ReleaseDue(ctx, now.Add(time.Duration(config.LeadMinutes)*time.Minute), now)
+	if err != nil {
+		return nil, fmt.Errorf("schedule service: release: %w", err)
+	}
+
+	for _, id := range released {
+		order, err := s.OrderRepo.FindByID(ctx, id)
+		if err != nil {
+			log.Printf("schedule service: reload order %d: %v", id, err)
+			continue
+		}
+
+		publishOrder(ctx, s.Broker, broker.OrderReleased, order, "")
+	}
+
+	return released, nil
+}
+
+func (s *scheduleServiceImpl) GetConfig(ctx context.Context) (*dto.ScheduleConfigResponse, error) {
+	config, err := loadScheduleConfig(ctx, s.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("schedule service: get config: %w", err)
+	}
+
+	return dto.ToScheduleConfigResponse(config, outletTimezone()), nil
+}
+
+// UpdateConfig replaces the whole schedule, orders already booked keep their slot.
+func (s *scheduleServiceImpl) UpdateConfig(ctx context.Context, req *dto.ScheduleConfigReq) (*dto.ScheduleConfigResponse, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	config := schedule.Config{
+		Hours:       req.Hours,
+		SlotMinutes: *req.SlotMinutes,
+		Capacity:    *req.Capacity,
+		LeadMinutes: *req.LeadMinutes,
+		MaxDays:     *req.MaxDays,
+	}
+
+	if config.Hours == nil {
+		config.Hours = map[string][]schedule.Window{}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	value, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("schedule service: update config: %w", err)
+	}
+
+	if err := s.SettingRepo.Set(ctx, constanta.SettingSchedule, string(value)); err != nil {
+		return nil, fmt.Errorf("schedule service: update config: %w", err)
+	}
+
+	return dto.ToScheduleConfigResponse(config, outletTimezone()), nil
+}
+
+// Slots lists the slots of a day with what is left of them, a slot is available while it can be booked now.
+func (s *scheduleServiceImpl) Slots(ctx context.Context, req *dto.SlotReq) ([]dto.SlotResponse, error) {
+	if err := s.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	loc := outletTimezone()
+	day, err := schedule.Day(req.Date, loc)
+	if err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	config, err := loadScheduleConfig(ctx, s.SettingRepo)
+	if err != nil {
+		return nil, fmt.Errorf("schedule service: slots: %w", err)
+	}
+
+	slots := config.Slots(day, loc)
+	responses := make([]dto.SlotResponse, 0, len(slots))
+	if len(slots) == 0 {
+		return responses, nil
+	}
+
+	booked, err := s.ScheduleRepo.FindSlots(ctx, slots[0].Start, slots[len(slots)-1].End)
+	if err != nil {
+		return nil, fmt.Errorf("schedule service: slots: %w", err)
+	}
+
+	counts := make(map[int64]int, len(booked))
+	for _, v := range booked {
+		counts[v.Start.Unix()] = v.Booked
+	}
+
+	now := s.Clock()
+	for _, v := range slots {
+		response := dto.SlotResponse{
+			Start:     v.Start,
+			End:       v.End,
+			Booked:    counts[v.Start.Unix()],
+			Available: config.Bookable(v, now),
+		}
+
+		if config.Capacity > 0 {
+			remaining := max(config.Capacity-response.Booked, 0)
+			response.Remaining = &remaining
+			response.Available = response.Available && remaining > 0
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/hashing"
+	"online-food/utils/token"
+	"online-food/utils/totp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	challengeTTL      = 5 * time.Minute
+	recoveryCodeCount = 10
+)
+
+type TwoFactorService interface {
+	Setup(ctx context.Context, userID uint) (*dto.TwoFactorSetupResponse, error)
+	Confirm(ctx context.Context, userID uint, req *dto.TwoFactorConfirmReq) (*dto.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID uint, req *dto.TwoFactorDisableReq) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, req *dto.TwoFactorCodeReq) (*dto.RecoveryCodesResponse, error)
+	ChallengeSetup(ctx context.Context, req *dto.TwoFactorChallengeReq) (*dto.TwoFactorSetupResponse, error)
+	ChallengeConfirm(ctx context.Context, req *dto.TwoFactorConfirmReq) (*dto.RecoveryCodesResponse, error)
+	Login(ctx context.Context, req *dto.TwoFactorLoginReq) (*dto.TokenResponse, error)
+	GetPolicy(ctx context.Context) (*dto.TwoFactorPolicyResponse, error)
+	UpdatePolicy(ctx context.Context, req *dto.TwoFactorPolicyReq) (*dto.TwoFactorPolicyResponse, error)
+}
+
+type twoFactorServiceImpl struct {
+	UserRepo      repository.UserRepository
+	TwoFactorRepo repository.TwoFactorRepository
+	SettingRepo   repository.SettingRepository
+	LoginGuard    *loginGuard
+	Validate      *validator.Validate
+}
+
+func NewTwoFactorServiceImpl(userRepo repository.UserRepository, twoFactorRepo repository.TwoFactorRepository, settingRepo repository.SettingRepository, attemptRepo repository.LoginAttemptRepository, validate *validator.Validate) *twoFactorServiceImpl {
+	return &twoFactorServiceImpl{
+		UserRepo:      userRepo,
+		TwoFactorRepo: twoFactorRepo,
+		SettingRepo:   settingRepo,
+		LoginGuard:    newLoginGuard(attemptRepo),
+		Validate:      validate,
+	}
+}
+
+func twoFactorRequired(ctx context.Context, settingRepo repository.SettingRepository, user *entity.User) (bool, error) {
+	if user.Role != constanta.Admin {
+		return false, nil
+	}
+
+	value, err := settingRepo.Get(ctx, constanta.SettingAdminTwoFactor, "false")
+	if err != nil {
+		return false, fmt.Errorf("get two factor setting: %w", err)
+	}
+
+	required, _ := strconv.ParseBool(value)
+	return required, nil
+}
+
+func (t *twoFactorServiceImpl) findUser(ctx context.Context, userID uint) (*entity.User, error) {
+	user, err := t.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (t *twoFactorServiceImpl) Setup(ctx context.Context, userID uint) (*dto.TwoFactorSetupResponse, error) {
+	user, err := t.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactor {
+		return nil, handling.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("two factor service: setup: generate secret: %w", err)
+	}
+
+	if err := t.TwoFactorRepo.SetSecret(ctx, user.ID, secret); err != nil {
+		if errors.Is(err, handling.ErrTwoFactorEnabled) {
+			return nil, handling.ErrTwoFactorEnabled
+		}
+		return nil, fmt.Errorf("two factor service: setup: %w", err)
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "Online Food"
+	}
+
+	response := &dto.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(issuer, user.Email, secret),
+	}
+
+	return response, nil
+}
+
+func (t *twoFactorServiceImpl) Confirm(ctx context.Context, userID uint, req *dto.TwoFactorConfirmReq) (*dto.RecoveryCodesResponse, error) {
+	if err := t.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	user, err := t.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactor {
+		return nil, handling.ErrTwoFactorEnabled
+	}
+
+	if user.TotpSecret == "" {
+		return nil, handling.ErrTwoFactorNotSetup
+	}
+
+	step, ok := totp.Validate(user.TotpSecret, req.Code, time.Now(), user.TotpLastStep)
+	if !ok {
+		return nil, handling.ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("two factor service: confirm: %w", err)
+	}
+
+	if err := t.TwoFactorRepo.Enable(ctx, user.ID, step, hashes); err != nil {
+		if errors.Is(err, handling.ErrInvalidTwoFactorCode) {
+			return nil, handling.ErrInvalidTwoFactorCode
+		}
+		return nil, fmt.Errorf("two factor service: confirm: %w", err)
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (t *twoFactorServiceImpl) Disable(ctx context.Context, userID uint, req *dto.TwoFactorDisableReq) error {
+	if err := t.Validate.Struct(req); err != nil {
+		return handling.ErrorValidation
+	}
+
+	user, err := t.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TwoFactor {
+		return handling.ErrTwoFactorNotSetup
+	}
+
+	required, err := twoFactorRequired(ctx, t.SettingRepo, user)
+	if err != nil {
+		return fmt.Errorf("two factor service: disable: %w", err)
+	}
+
+	if required {
+		return handling.ErrTwoFactorRequired
+	}
+
+	if !hashing.CompareHashPassword(user.Password, req.Password) {
+		return handling.ErrFailedLogin
+	}
+
+	if err := t.verifyCode(ctx, user, req.Code); err != nil {
+		return err
+	}
+
+	if err := t.TwoFactorRepo.Disable(ctx, user.ID); err != nil {
+		return fmt.Errorf("two factor service: disable: %w", err)
+	}
+
+	return nil
+}
+
+func (t *twoFactorServiceImpl) RegenerateRecoveryCodes(ctx context.Context, userID uint, req *dto.TwoFactorCodeReq) (*dto.RecoveryCodesResponse, error) {
+	if err := t.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	user, err := t.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TwoFactor {
+		return nil, handling.ErrTwoFactorNotSetup
+	}
+
+	if err := t.verifyCode(ctx, user, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("two factor service: regenerate recovery codes: %w", err)
+	}
+
+	if err := t.TwoFactorRepo.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+		return nil, fmt.Errorf("two factor service: regenerate recovery codes: %w", err)
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (t *twoFactorServiceImpl) ChallengeSetup(ctx context.Context, req *dto.TwoFactorChallengeReq) (*dto.TwoFactorSetupResponse, error) {
+	if err := t.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	claim, err := token.ClaimPurposeToken(req.ChallengeToken, constanta.TwoFactorSetupPurpose)
+	if err != nil {
+		return nil, handling.ErrInvalidChallenge
+	}
+
+	return t.Setup(ctx, claim.UserID)
+}
+
+func (t *twoFactorServiceImpl) ChallengeConfirm(ctx context.Context, req *dto.TwoFactorConfirmReq) (*dto.RecoveryCodesResponse, error) {
+	if err := t.Validate.Struct(req); err != nil || req.ChallengeToken == "" {
+		return nil, handling.ErrorValidation
+	}
+
+	claim, err := token.ClaimPurposeToken(req.ChallengeToken, constanta.TwoFactorSetupPurpose)
+	if err != nil {
+		return nil, handling.ErrInvalidChallenge
+	}
+
+	result, err := t.Confirm(ctx, claim.UserID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := t.findUser(ctx, claim.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.LoginGuard.Success(ctx, user.Email); err != nil {
+		return nil, fmt.Errorf("two factor service: challenge confirm: %w", err)
+	}
+
+	result.Token, err = generateTokens(user)
+	if err != nil {
+		return nil, fmt.Errorf("two factor service: challenge confirm: %w", err)
+	}
+
+	return result, nil
+}
+
+func (t *twoFactorServiceImpl) Login(ctx context.Context, req *dto.TwoFactorLoginReq) (*dto.TokenResponse, error) {
+	if err := t.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	claim, err := token.ClaimPurposeToken(req.ChallengeToken, constanta.LoginChallengePurpose)
+	if err != nil {
+		return nil, handling.ErrInvalidChallenge
+	}
+
+	if err := t.LoginGuard.Check(ctx, claim.Email, req.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := t.findUser(ctx, claim.UserID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrInvalidChallenge
+		}
+		return nil, fmt.Errorf("two factor service: login: %w", err)
+	}
+
+	if !user.TwoFactor || user.Email != claim.Email {
+		return nil, handling.ErrInvalidChallenge
+	}
+
+	if err := t.verifyCode(ctx, user, req.Code); err != nil {
+		if errors.Is(err, handling.ErrInvalidTwoFactorCode) {
+			if err := t.LoginGuard.Fail(ctx, user.Email, req.IP); err != nil {
+				return nil, fmt.Errorf("two factor service: login: %w", err)
+			}
+		}
+		return nil, err
+	}
+
+	if err := t.LoginGuard.Success(ctx, user.Email); err != nil {
+		return nil, fmt.Errorf("two factor service: login: %w", err)
+	}
+
+	return generateTokens(user)
+}
+
+func (t *twoFactorServiceImpl) GetPolicy(ctx context.Context) (*dto.TwoFactorPolicyResponse, error) {
+	value, err := t.SettingRepo.Get(ctx, constanta.SettingAdminTwoFactor, "false")
+	if err != nil {
+		return nil, fmt.Errorf("two factor service: get policy: %w", err)
+	}
+
+	required, _ := strconv.ParseBool(value)
+	return &dto.TwoFactorPolicyResponse{AdminRequired: required}, nil
+}
+
+func (t *twoFactorServiceImpl) UpdatePolicy(ctx context.Context, req *dto.TwoFactorPolicyReq) (*dto.TwoFactorPolicyResponse, error) {
+	if err := t.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	if err := t.SettingRepo.Set(ctx, constanta.SettingAdminTwoFactor, strconv.FormatBool(*req.AdminRequired)); err != nil {
+		return nil, fmt.Errorf("two factor service: update policy: %w", err)
+	}
+
+	//admins without two factor have to log in again and go through the setup
+	if *req.AdminRequired {
+		if err := t.TwoFactorRepo.RevokeUnenrolled(ctx, constanta.Admin); err != nil {
+			return nil, fmt.Errorf("two factor service: update policy: %w", err)
+		}
+	}
+
+	return &dto.TwoFactorPolicyResponse{AdminRequired: *req.AdminRequired}, nil
+}
+
+// verifyCode accepts a totp code or one of the recovery codes, both are single use.
+func (t *twoFactorServiceImpl) verifyCode(ctx context.Context, user *entity.User, code string) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.TotpSecret, code, time.Now(), user.TotpLastStep)
+		if !ok {
+			return handling.ErrInvalidTwoFactorCode
+		}
+
+		if err := t.TwoFactorRepo.UseStep(ctx, user.ID, step); err != nil {
+			if errors.Is(err, handling.ErrInvalidTwoFactorCode) {
+				return handling.ErrInvalidTwoFactorCode
+			}
+			return fmt.Errorf("use totp step: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := t.TwoFactorRepo.UseRecoveryCode(ctx, user.ID, hashing.HashToken(normalizeRecoveryCode(code)), time.Now().UTC()); err != nil {
+		if errors.Is(err, handling.ErrInvalidTwoFactorCode) {
+			return handling.ErrInvalidTwoFactorCode
+		}
+		return fmt.Errorf("use recovery code: %w", err)
+	}
+
+	return nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := hashing.RandomToken(5)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashing.HashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
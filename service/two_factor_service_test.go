@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"online-food/utils/hashing"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func newTwoFactorFixture(t *testing.T, twoFactor bool) (*userServiceImpl, *twoFactorServiceImpl, *fakeAttemptRepo) {
+	t.Helper()
+	loadTestKeys(t)
+
+	pass, err := hashing.HashPassword("password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verified := time.Now()
+	userRepo := &fakeUserRepo{users: []*entity.User{{
+		ID:              1,
+		Name:            "budi",
+		Email:           "budi@mail.com",
+		Password:        pass,
+		Role:            constanta.Customer,
+		EmailVerifiedAt: &verified,
+		TwoFactor:       twoFactor,
+	}}}
+	settingRepo := &fakeSettingRepo{values: map[string]string{}}
+	attemptRepo := newFakeAttemptRepo()
+	validate := validator.New()
+
+	users := NewUserServiceImpl(userRepo, nil, settingRepo, nil, attemptRepo, nil, validate)
+	twoFactors := NewTwoFactorServiceImpl(userRepo, &fakeTwoFactorRepo{}, settingRepo, attemptRepo, validate)
+
+	return users, twoFactors, attemptRepo
+}
+
+func TestLoginKeepsFailuresUntilSecondFactor(t *testing.T) {
+	users, twoFactors, attempts := newTwoFactorFixture(t, true)
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		attempts.unlock()
+
+		challenge, err := users.Login(ctx, &dto.UserLoginReq{Email: "budi@mail.com", Password: "password123", IP: "10.0.0.1"})
+		if err != nil {
+			t.Fatalf("round %d: password step: %v", i, err)
+		}
+
+		if challenge.ChallengeToken == "" || challenge.Token != "" {
+			t.Fatalf("round %d: expected a challenge only, got %+v", i, challenge)
+		}
+
+		_, err = twoFactors.Login(ctx, &dto.TwoFactorLoginReq{ChallengeToken: challenge.ChallengeToken, Code: "aaaaa-bbbbb", IP: "10.0.0.1"})
+		if !errors.Is(err, handling.ErrInvalidTwoFactorCode) {
+			t.Fatalf("round %d: expected invalid code, got %v", i, err)
+		}
+
+		if got := attempts.failures[accountKey("budi@mail.com")]; got != int64(i) {
+			t.Fatalf("round %d: account failures = %d, want %d", i, got, i)
+		}
+	}
+}
+
+func TestLoginWithoutSecondFactorClearsFailures(t *testing.T) {
+	users, _, attempts := newTwoFactorFixture(t, false)
+	ctx := context.Background()
+
+	_, err := users.Login(ctx, &dto.UserLoginReq{Email: "budi@mail.com", Password: "wrong-password", IP: "10.0.0.1"})
+	if !errors.Is(err, handling.ErrFailedLogin) {
+		t.Fatalf("expected failed login, got %v", err)
+	}
+
+	attempts.unlock()
+
+	result, err := users.Login(ctx, &dto.UserLoginReq{Email: "budi@mail.com", Password: "password123", IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	if result.Token == "" {
+		t.Fatal("expected tokens")
+	}
+
+	if got := attempts.failures[accountKey("budi@mail.com")]; got != 0 {
+		t.Fatalf("account failures = %d, want 0", got)
+	}
+}
+
+func TestUpdatePolicyRevokesUnenrolledAdmins(t *testing.T) {
+	tests := []struct {
+		name     string
+		required bool
+		revoked  int
+	}{
+		{"required", true, 1},
+		{"optional", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactorRepo := &fakeTwoFactorRepo{}
+			service := NewTwoFactorServiceImpl(&fakeUserRepo{}, twoFactorRepo, &fakeSettingRepo{values: map[string]string{}}, newFakeAttemptRepo(), validator.New())
+
+			if _, err := service.UpdatePolicy(context.Background(), &dto.TwoFactorPolicyReq{AdminRequired: &tt.required}); err != nil {
+				t.Fatalf("update policy: %v", err)
+			}
+
+			if len(twoFactorRepo.revoked) != tt.revoked {
+				t.Fatalf("revoked = %v, want %d call(s)", twoFactorRepo.revoked, tt.revoked)
+			}
+		})
+	}
+}
@@ -10,8 +10,11 @@ import (
 	"online-food/utils/constanta"
 	"online-food/utils/handling"
 	"online-food/utils/hashing"
+	"online-food/utils/mailer"
 	"online-food/utils/token"
 
+	"log"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -22,6 +25,7 @@ import (
 
 type UserService interface {
 	Create(ctx context.Context, req *dto.UserCreateReq) (*dto.UserResponse, error)
+	CreateAdmin(ctx context.Context, req *dto.UserCreateReq) (*dto.UserResponse, error)
 	Update(ctx context.Context, id uint, req *dto.UserUpdateReq) (*dto.UserResponse, error)
 	Delete(ctx context.Context, id uint) error
 	FindByID(ctx context.Context, id uint) (*dto.UserResponse, error)
@@ -29,17 +33,38 @@ type UserService interface {
 	FindByEmail(ctx context.Context, email string) (*dto.UserResponse, error)
 	Login(ctx context.Context, req *dto.UserLoginReq) (*dto.TokenResponse, error)
 	RefreshToken(ctx context.Context, req *dto.UserRefreshTokenReq) (*dto.TokenResponse, error)
+	VerifyEmail(ctx context.Context, tokenStr string) error
+	ResendVerification(ctx context.Context, req *dto.UserResendVerificationReq) error
+	ForgotPassword(ctx context.Context, req *dto.UserForgotPasswordReq) error
+	ResetPassword(ctx context.Context, req *dto.UserResetPasswordReq) error
+	Unlock(ctx context.Context, id uint) error
+	ChangeRole(ctx context.Context, actorID, id uint, req *dto.UserRoleReq) (*dto.UserResponse, error)
+	Suspend(ctx context.Context, actorID, id uint, req *dto.UserSuspendReq) (*dto.UserResponse, error)
+	Unsuspend(ctx context.Context, id uint) (*dto.UserResponse, error)
+	FindDeleted(ctx context.Context) ([]*dto.UserResponse, error)
+	Restore(ctx context.Context, id uint) (*dto.UserResponse, error)
+	Purge(ctx context.Context, actorID, id uint) error
 }
 
 type userServiceImpl struct {
-	UserRepo repository.UserRepository
-	Validate *validator.Validate
+	UserRepo    repository.UserRepository
+	ResetRepo   repository.PasswordResetRepository
+	SettingRepo repository.SettingRepository
+	RoleRepo    repository.RoleRepository
+	LoginGuard  *loginGuard
+	Mailer      mailer.Mailer
+	Validate    *validator.Validate
 }
 
-func NewUserServiceImpl(userRepo repository.UserRepository, validate *validator.Validate) *userServiceImpl {
+func NewUserServiceImpl(userRepo repository.UserRepository, resetRepo repository.PasswordResetRepository, settingRepo repository.SettingRepository, roleRepo repository.RoleRepository, attemptRepo repository.LoginAttemptRepository, mail mailer.Mailer, validate *validator.Validate) *userServiceImpl {
 	return &userServiceImpl{
-		UserRepo: userRepo,
-		Validate: validate,
+		UserRepo:    userRepo,
+		ResetRepo:   resetRepo,
+		SettingRepo: settingRepo,
+		RoleRepo:    roleRepo,
+		LoginGuard:  newLoginGuard(attemptRepo),
+		Mailer:      mail,
+		Validate:    validate,
 	}
 }
 
@@ -72,11 +97,52 @@ func (u *userServiceImpl) Create(ctx context.Context, req *dto.UserCreateReq) (*
 		return nil, fmt.Errorf("user service: create: %w", err)
 	}
 
+	//account is already created, a failed mail can be retried through resend verification
+	if err := u.sendVerification(ctx, result); err != nil {
+		log.Printf("user service: create: send verification: %v", err)
+	}
+
 	response := dto.ToUserResponse(result)
 
 	return response, nil
 }
 
+// CreateAdmin is used to bootstrap an installation, the account is trusted so no verification mail is sent.
+func (u *userServiceImpl) CreateAdmin(ctx context.Context, req *dto.UserCreateReq) (*dto.UserResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	if err := u.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	pass, err := hashing.HashPassword(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("hashing: %w", err)
+	}
+
+	now := time.Now()
+	user := entity.User{
+		Name:            req.Name,
+		Email:           email,
+		Password:        pass,
+		Role:            constanta.Admin,
+		Hp:              req.Hp,
+		Address:         req.Address,
+		EmailVerifiedAt: &now,
+	}
+
+	result, err := u.UserRepo.Create(ctx, &user)
+	if err != nil {
+		if errors.Is(err, handling.ErrorEmailExist) {
+			return nil, handling.ErrorEmailExist
+		}
+		return nil, fmt.Errorf("user service: create admin: %w", err)
+	}
+
+	response := dto.ToUserResponse(result)
+	return response, nil
+}
+
 func (u *userServiceImpl) Update(ctx context.Context, id uint, req *dto.UserUpdateReq) (*dto.UserResponse, error) {
 	if err := u.Validate.Struct(req); err != nil {
 		return nil, handling.ErrorValidation
@@ -177,40 +243,70 @@ func (u *userServiceImpl) Login(ctx context.Context, req *dto.UserLoginReq) (*dt
 		return nil, handling.ErrorValidation
 	}
 
-	user, err := u.UserRepo.FindByEmail(ctx, req.Email)
-	if err != nil {
-		if errors.Is(err, handling.ErrorEmailNotFound) {
-			return nil, handling.ErrorEmailNotFound
-		}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	if err := u.LoginGuard.Check(ctx, email, req.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := u.UserRepo.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, handling.ErrorEmailNotFound) {
 		return nil, fmt.Errorf("user service: login: find by email: %w", err)
 	}
 
-	if !hashing.CompareHashPassword(user.Password, req.Password) {
+	//unknown email and wrong password answer the same, hashing a dummy keeps the timing equal too
+	passwordHash := hashing.DummyHash()
+	if user != nil {
+		passwordHash = user.Password
+	}
+
+	if !hashing.CompareHashPassword(passwordHash, req.Password) || user == nil {
+		if err := u.LoginGuard.Fail(ctx, email, req.IP); err != nil {
+			return nil, fmt.Errorf("user service: login: %w", err)
+		}
 		return nil, handling.ErrFailedLogin
 	}
 
-	tokenExp, _ := strconv.Atoi(os.Getenv("JWT_EXP"))
+	if user.EmailVerifiedAt == nil {
+		return nil, handling.ErrEmailNotVerified
+	}
 
-	accessToken, err := token.GenerateToken(user.ID, user.Name, user.Email, user.Role, time.Duration(tokenExp))
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	if user.SuspendedAt != nil {
+		return nil, handling.ErrUserSuspended
 	}
 
-	refreshToken, err := token.GenerateToken(user.ID, user.Name, user.Email, user.Role, time.Duration(tokenExp*2))
+	required, err := twoFactorRequired(ctx, u.SettingRepo, user)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, fmt.Errorf("user service: login: %w", err)
 	}
 
-	createdToken := &dto.TokenResponse{
-		Username:     user.Name,
-		Token:        accessToken,
-		TokenRefresh: refreshToken,
-		TokenType:    "Bearer",
-		ExipresIn:    tokenExp * 3600,
+	//with two factor the password only buys a short lived challenge, tokens come from the second step.
+	//the failure counter is kept until then so bad codes can't be reset by logging in with the password again
+	if user.TwoFactor || required {
+		purpose := constanta.LoginChallengePurpose
+		if !user.TwoFactor {
+			purpose = constanta.TwoFactorSetupPurpose
+		}
+
+		challenge, err := token.GeneratePurposeToken(user.ID, user.Email, purpose, challengeTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate challenge token: %w", err)
+		}
+
+		return &dto.TokenResponse{
+			Username:               user.Name,
+			ExipresIn:              int(challengeTTL.Seconds()),
+			ChallengeToken:         challenge,
+			TwoFactorRequired:      user.TwoFactor,
+			TwoFactorSetupRequired: !user.TwoFactor,
+		}, nil
 	}
 
-	return createdToken, nil
+	if err := u.LoginGuard.Success(ctx, email); err != nil {
+		return nil, fmt.Errorf("user service: login: %w", err)
+	}
 
+	return generateTokens(user)
 }
 
 func (u *userServiceImpl) RefreshToken(ctx context.Context, req *dto.UserRefreshTokenReq) (*dto.TokenResponse, error) {
@@ -231,9 +327,17 @@ func (u *userServiceImpl) RefreshToken(ctx context.Context, req *dto.UserRefresh
 		return nil, fmt.Errorf("user service: refresh token, find user: %w", err)
 	}
 
+	if user.SessionVersion != tokenClaims.Session {
+		return nil, handling.ErrInvalidToken
+	}
+
+	if user.SuspendedAt != nil {
+		return nil, handling.ErrUserSuspended
+	}
+
 	tokenExp, _ := strconv.Atoi(os.Getenv("JWT_EXP"))
 
-	accessToken, err := token.GenerateToken(user.ID, user.Name, user.Email, user.Role, time.Duration(tokenExp)) //expired in 24 hour
+	accessToken, err := token.GenerateToken(user.ID, user.Name, user.Email, user.Role, user.SessionVersion, constanta.AccessToken, time.Duration(tokenExp)) //expired in 24 hour
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -247,3 +351,373 @@ func (u *userServiceImpl) RefreshToken(ctx context.Context, req *dto.UserRefresh
 
 	return createdToken, nil
 }
+
+func (u *userServiceImpl) VerifyEmail(ctx context.Context, tokenStr string) error {
+	claim, err := token.ClaimPurposeToken(tokenStr, constanta.VerifyEmailPurpose)
+	if err != nil {
+		return handling.ErrInvalidVerifyToken
+	}
+
+	user, err := u.UserRepo.FindByID(ctx, claim.UserID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return handling.ErrInvalidVerifyToken
+		}
+		return fmt.Errorf("user service: verify email: find user: %w", err)
+	}
+
+	if user.Email != claim.Email {
+		return handling.ErrInvalidVerifyToken
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if err := u.UserRepo.VerifyEmail(ctx, user.ID, user.Email, time.Now().UTC()); err != nil {
+		if errors.Is(err, handling.ErrInvalidVerifyToken) {
+			return handling.ErrInvalidVerifyToken
+		}
+		return fmt.Errorf("user service: verify email: %w", err)
+	}
+
+	return nil
+}
+
+func (u *userServiceImpl) ResendVerification(ctx context.Context, req *dto.UserResendVerificationReq) error {
+	if err := u.Validate.Struct(req); err != nil {
+		return handling.ErrorValidation
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	//unknown or already verified emails get the same response so accounts can't be enumerated
+	user, err := u.UserRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, handling.ErrorEmailNotFound) {
+			return nil
+		}
+		return fmt.Errorf("user service: resend verification: find by email: %w", err)
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if err := u.sendVerification(ctx, user); err != nil {
+		return fmt.Errorf("user service: resend verification: %w", err)
+	}
+
+	return nil
+}
+
+func (u *userServiceImpl) sendVerification(ctx context.Context, user *entity.User) error {
+	verifyExp, err := strconv.Atoi(os.Getenv("EMAIL_VERIFY_EXP"))
+	if err != nil || verifyExp <= 0 {
+		verifyExp = 24
+	}
+
+	verifyToken, err := token.GeneratePurposeToken(user.ID, user.Email, constanta.VerifyEmailPurpose, time.Duration(verifyExp)*time.Hour)
+	if err != nil {
+		return fmt.Errorf("generate verification token: %w", err)
+	}
+
+	link := fmt.Sprintf("%s/api/v1/auth/verify-email?token=%s", strings.TrimRight(os.Getenv("APP_URL"), "/"), url.QueryEscape(verifyToken))
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email address by opening the link below:\n%s\n\nThe link expires in %d hours.\n",
+			user.Name, link, verifyExp),
+	}
+
+	if err := u.Mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
+
+func (u *userServiceImpl) ForgotPassword(ctx context.Context, req *dto.UserForgotPasswordReq) error {
+	if err := u.Validate.Struct(req); err != nil {
+		return handling.ErrorValidation
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	//same response for unknown emails so accounts can't be enumerated
+	user, err := u.UserRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, handling.ErrorEmailNotFound) {
+			return nil
+		}
+		return fmt.Errorf("user service: forgot password: find by email: %w", err)
+	}
+
+	resetExp, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_EXP"))
+	if err != nil || resetExp <= 0 {
+		resetExp = 30
+	}
+
+	resetToken, err := hashing.RandomToken(32)
+	if err != nil {
+		return fmt.Errorf("user service: forgot password: generate token: %w", err)
+	}
+
+	now := time.Now().UTC()
+	reset := entity.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hashing.HashToken(resetToken),
+		ExpiresAt: now.Add(time.Duration(resetExp) * time.Minute),
+		CreatedAt: now,
+	}
+
+	if _, err := u.ResetRepo.Create(ctx, &reset); err != nil {
+		return fmt.Errorf("user service: forgot password: %w", err)
+	}
+
+	//PASSWORD_RESET_URL points at the frontend form, without it the link goes to the api route
+	resetURL := strings.TrimSpace(os.Getenv("PASSWORD_RESET_URL"))
+	if resetURL == "" {
+		resetURL = strings.TrimRight(os.Getenv("APP_URL"), "/") + "/api/v1/auth/reset-password"
+	}
+
+	link := fmt.Sprintf("%s?token=%s", resetURL, url.QueryEscape(resetToken))
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n%s\n\nThe link expires in %d minutes and can only be used once. If you did not request this, you can ignore this email.\n",
+			user.Name, link, resetExp),
+	}
+
+	//sent in the background and only logged, a failure or a slow mail server must not tell registered emails apart
+	go func() {
+		if err := u.Mailer.Send(context.WithoutCancel(ctx), msg); err != nil {
+			log.Printf("user service: forgot password: send mail to user %d: %v", user.ID, err)
+		}
+	}()
+
+	return nil
+}
+
+func (u *userServiceImpl) ResetPassword(ctx context.Context, req *dto.UserResetPasswordReq) error {
+	if err := u.Validate.Struct(req); err != nil {
+		return handling.ErrorValidation
+	}
+
+	pass, err := hashing.HashPassword(req.Password)
+	if err != nil {
+		return fmt.Errorf("hashing: %w", err)
+	}
+
+	if err := u.ResetRepo.ResetPassword(ctx, hashing.HashToken(req.Token), pass, time.Now().UTC()); err != nil {
+		if errors.Is(err, handling.ErrInvalidResetToken) {
+			return handling.ErrInvalidResetToken
+		}
+		return fmt.Errorf("user service: reset password: %w", err)
+	}
+
+	return nil
+}
+
+func (u *userServiceImpl) Unlock(ctx context.Context, id uint) error {
+	user, err := u.UserRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return handling.ErrorIdNotFound
+		}
+		return fmt.Errorf("user service: unlock: find user: %w", err)
+	}
+
+	if err := u.LoginGuard.Unlock(ctx, user.Email); err != nil {
+		return fmt.Errorf("user service: unlock: %w", err)
+	}
+
+	return nil
+}
+
+func (u *userServiceImpl) ChangeRole(ctx context.Context, actorID, id uint, req *dto.UserRoleReq) (*dto.UserResponse, error) {
+	if err := u.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	//an admin demoting themselves could leave nobody able to manage users
+	if actorID == id {
+		return nil, handling.ErrOwnAccount
+	}
+
+	if _, err := u.RoleRepo.FindByName(ctx, req.Role); err != nil {
+		if errors.Is(err, handling.ErrRoleNotFound) {
+			return nil, handling.ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("user service: change role: find role: %w", err)
+	}
+
+	actor, err := u.UserRepo.FindByID(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("user service: change role: find actor: %w", err)
+	}
+
+	target, err := u.UserRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("user service: change role: %w", err)
+	}
+
+	//nobody hands out or takes away more than they hold themselves
+	for _, role := range []string{req.Role, target.Role} {
+		granted, err := u.holdsRole(ctx, actor.Role, role)
+		if err != nil {
+			return nil, fmt.Errorf("user service: change role: %w", err)
+		}
+
+		if !granted {
+			return nil, handling.ErrRoleNotGrantable
+		}
+	}
+
+	user, err := u.UserRepo.UpdateRole(ctx, id, req.Role)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("user service: change role: %w", err)
+	}
+
+	response := dto.ToUserResponse(user)
+	return response, nil
+}
+
+// holdsRole reports whether the actor role has every permission of the role.
+func (u *userServiceImpl) holdsRole(ctx context.Context, actorRole, role string) (bool, error) {
+	owned, err := u.RoleRepo.PermissionsByRole(ctx, actorRole)
+	if err != nil {
+		return false, err
+	}
+
+	wanted, err := u.RoleRepo.PermissionsByRole(ctx, role)
+	if err != nil {
+		return false, err
+	}
+
+	granted := map[string]bool{}
+	for _, v := range owned {
+		granted[v] = true
+	}
+
+	for _, v := range wanted {
+		if !granted[v] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (u *userServiceImpl) Suspend(ctx context.Context, actorID, id uint, req *dto.UserSuspendReq) (*dto.UserResponse, error) {
+	if err := u.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	if actorID == id {
+		return nil, handling.ErrOwnAccount
+	}
+
+	user, err := u.UserRepo.Suspend(ctx, id, strings.TrimSpace(req.Reason), time.Now())
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("user service: suspend: %w", err)
+	}
+
+	response := dto.ToUserResponse(user)
+	return response, nil
+}
+
+func (u *userServiceImpl) Unsuspend(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	user, err := u.UserRepo.Unsuspend(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) || errors.Is(err, handling.ErrUserNotSuspended) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("user service: unsuspend: %w", err)
+	}
+
+	response := dto.ToUserResponse(user)
+	return response, nil
+}
+
+func (u *userServiceImpl) FindDeleted(ctx context.Context) ([]*dto.UserResponse, error) {
+	users, err := u.UserRepo.FindDeleted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("user service: find deleted: %w", err)
+	}
+
+	responses := make([]*dto.UserResponse, 0, len(users))
+	for _, v := range users {
+		responses = append(responses, dto.ToUserResponse(v))
+	}
+
+	return responses, nil
+}
+
+func (u *userServiceImpl) Restore(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	user, err := u.UserRepo.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) || errors.Is(err, handling.ErrUserNotDeleted) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("user service: restore: %w", err)
+	}
+
+	response := dto.ToUserResponse(user)
+	return response, nil
+}
+
+func (u *userServiceImpl) Purge(ctx context.Context, actorID, id uint) error {
+	if actorID == id {
+		return handling.ErrOwnAccount
+	}
+
+	if err := u.UserRepo.Purge(ctx, id); err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) || errors.Is(err, handling.ErrUserHasOrders) {
+			return err
+		}
+		return fmt.Errorf("user service: purge: %w", err)
+	}
+
+	return nil
+}
+
+func generateTokens(user *entity.User) (*dto.TokenResponse, error) {
+	//every path that hands out tokens ends here, so a suspension made mid login still holds
+	if user.SuspendedAt != nil {
+		return nil, handling.ErrUserSuspended
+	}
+
+	tokenExp, _ := strconv.Atoi(os.Getenv("JWT_EXP"))
+
+	accessToken, err := token.GenerateToken(user.ID, user.Name, user.Email, user.Role, user.SessionVersion, constanta.AccessToken, time.Duration(tokenExp))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := token.GenerateToken(user.ID, user.Name, user.Email, user.Role, user.SessionVersion, constanta.RefreshToken, time.Duration(tokenExp*2))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	createdToken := &dto.TokenResponse{
+		Username:     user.Name,
+		Token:        accessToken,
+		TokenRefresh: refreshToken,
+		TokenType:    "Bearer",
+		ExipresIn:    tokenExp * 3600,
+	}
+
+	return createdToken, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestChangeRole(t *testing.T) {
+	all := make([]string, 0, len(constanta.Permissions))
+	for v := range constanta.Permissions {
+		all = append(all, v)
+	}
+
+	roles := &fakeRoleRepo{permissions: map[string][]string{
+		constanta.Admin:    all,
+		constanta.Customer: constanta.DefaultRoles[constanta.Customer],
+		constanta.Kitchen:  constanta.DefaultRoles[constanta.Kitchen],
+		//a custom staff role that may manage users and roles but not much else
+		"supervisor": append([]string{constanta.PermOrderPrepare, constanta.PermUserRead, constanta.PermUserWrite, constanta.PermRoleManage},
+			constanta.DefaultRoles[constanta.Customer]...),
+	}}
+
+	tests := []struct {
+		name    string
+		actor   string
+		target  string
+		role    string
+		wantErr error
+	}{
+		{"admin promotes to admin", constanta.Admin, constanta.Customer, constanta.Admin, nil},
+		{"supervisor promotes to admin", "supervisor", constanta.Customer, constanta.Admin, handling.ErrRoleNotGrantable},
+		{"supervisor demotes an admin", "supervisor", constanta.Admin, constanta.Customer, handling.ErrRoleNotGrantable},
+		{"supervisor hands out a role they cover", "supervisor", constanta.Customer, constanta.Kitchen, nil},
+		{"unknown role", constanta.Admin, constanta.Customer, "owner", handling.ErrRoleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &fakeUserRepo{users: []*entity.User{{ID: 1, Role: tt.actor}, {ID: 2, Role: tt.target}}}
+			service := NewUserServiceImpl(users, nil, nil, roles, nil, nil, validator.New())
+
+			_, err := service.ChangeRole(context.Background(), 1, 2, &dto.UserRoleReq{Role: tt.role})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("change role: %v, want %v", err, tt.wantErr)
+			}
+
+			want := tt.target
+			if tt.wantErr == nil {
+				want = tt.role
+			}
+
+			if users.users[1].Role != want {
+				t.Fatalf("role = %q, want %q", users.users[1].Role, want)
+			}
+		})
+	}
+}
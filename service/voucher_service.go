@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"online-food/dto"
+	"online-food/entity"
+	"online-food/repository"
+	"online-food/utils/constanta"
+	"online-food/utils/handling"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type VoucherService interface {
+	Create(ctx context.Context, req *dto.VoucherCreateReq) (*dto.VoucherResponse, error)
+	Update(ctx context.Context, req *dto.VoucherUpdateReq) (*dto.VoucherResponse, error)
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*dto.VoucherResponse, error)
+	FindAll(ctx context.Context) ([]*dto.VoucherResponse, error)
+	Preview(ctx context.Context, userID uint, req *dto.VoucherPreviewReq) (*dto.VoucherPreviewResponse, error)
+}
+
+type voucherServiceImpl struct {
+	VoucherRepo repository.VoucherRepository
+	CartRepo    repository.CartRepository
+	Validate    *validator.Validate
+}
+
+func NewVoucherServiceImpl(voucherRepo repository.VoucherRepository, cartRepo repository.CartRepository, validate *validator.Validate) *voucherServiceImpl {
+	return &voucherServiceImpl{
+		VoucherRepo: voucherRepo,
+		CartRepo:    cartRepo,
+		Validate:    validate,
+	}
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func checkVoucher(voucher *entity.Voucher) error {
+	if voucher.Type == constanta.VoucherPercentage && voucher.Value > 100 {
+		return handling.ErrorValidation
+	}
+
+	if voucher.StartsAt != nil && voucher.EndsAt != nil && !voucher.EndsAt.After(*voucher.StartsAt) {
+		return handling.ErrorValidation
+	}
+
+	return nil
+}
+
+func (v *voucherServiceImpl) Create(ctx context.Context, req *dto.VoucherCreateReq) (*dto.VoucherResponse, error) {
+	if err := v.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	voucher := entity.Voucher{
+		Code:         normalizeCode(req.Code),
+		Description:  req.Description,
+		Type:         req.Type,
+		Value:        req.Value,
+		MinSpend:     req.MinSpend,
+		MaxDiscount:  req.MaxDiscount,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		Categories:   strings.Join(req.Categories, ","),
+		Active:       req.Active == nil || *req.Active,
+	}
+
+	if err := checkVoucher(&voucher); err != nil {
+		return nil, err
+	}
+
+	result, err := v.VoucherRepo.Create(ctx, &voucher, req.MenuIDs)
+	if err != nil {
+		if errors.Is(err, handling.ErrVoucherExist) || errors.Is(err, handling.ErrMenuNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("voucher service: create: %w", err)
+	}
+
+	response := dto.ToVoucherResponse(result)
+	return response, nil
+}
+
+func (v *voucherServiceImpl) Update(ctx context.Context, req *dto.VoucherUpdateReq) (*dto.VoucherResponse, error) {
+	if err := v.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	voucher, err := v.VoucherRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, handling.ErrVoucherNotFound) {
+			return nil, handling.ErrVoucherNotFound
+		}
+		return nil, fmt.Errorf("voucher service: update: find voucher: %w", err)
+	}
+
+	if req.Code != nil {
+		voucher.Code = normalizeCode(*req.Code)
+	}
+
+	if req.Description != nil {
+		voucher.Description = *req.Description
+	}
+
+	if req.Type != nil {
+		voucher.Type = *req.Type
+	}
+
+	if req.Value != nil {
+		voucher.Value = *req.Value
+	}
+
+	if req.MinSpend != nil {
+		voucher.MinSpend = *req.MinSpend
+	}
+
+	if req.MaxDiscount != nil {
+		voucher.MaxDiscount = *req.MaxDiscount
+	}
+
+	if req.StartsAt != nil {
+		voucher.StartsAt = req.StartsAt
+	}
+
+	if req.EndsAt != nil {
+		voucher.EndsAt = req.EndsAt
+	}
+
+	if req.UsageLimit != nil {
+		voucher.UsageLimit = *req.UsageLimit
+	}
+
+	if req.PerUserLimit != nil {
+		voucher.PerUserLimit = *req.PerUserLimit
+	}
+
+	if req.Categories != nil {
+		voucher.Categories = strings.Join(req.Categories, ",")
+	}
+
+	if req.Active != nil {
+		voucher.Active = *req.Active
+	}
+
+	if err := checkVoucher(voucher); err != nil {
+		return nil, err
+	}
+
+	result, err := v.VoucherRepo.Update(ctx, voucher, req.MenuIDs)
+	if err != nil {
+		if errors.Is(err, handling.ErrVoucherExist) || errors.Is(err, handling.ErrMenuNotFound) || errors.Is(err, handling.ErrVoucherNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("voucher service: update: %w", err)
+	}
+
+	response := dto.ToVoucherResponse(result)
+	return response, nil
+}
+
+func (v *voucherServiceImpl) Delete(ctx context.Context, id uint) error {
+	if err := v.VoucherRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, handling.ErrVoucherNotFound) {
+			return handling.ErrVoucherNotFound
+		}
+		return fmt.Errorf("voucher service: delete: %w", err)
+	}
+
+	return nil
+}
+
+func (v *voucherServiceImpl) FindByID(ctx context.Context, id uint) (*dto.VoucherResponse, error) {
+	voucher, err := v.VoucherRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, handling.ErrVoucherNotFound) {
+			return nil, handling.ErrVoucherNotFound
+		}
+		return nil, fmt.Errorf("voucher service: find by id: %w", err)
+	}
+
+	response := dto.ToVoucherResponse(voucher)
+	return response, nil
+}
+
+func (v *voucherServiceImpl) FindAll(ctx context.Context) ([]*dto.VoucherResponse, error) {
+	vouchers, err := v.VoucherRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("voucher service: find all: %w", err)
+	}
+
+	responses := make([]*dto.VoucherResponse, 0, len(vouchers))
+	for _, voucher := range vouchers {
+		responses = append(responses, dto.ToVoucherResponse(voucher))
+	}
+
+	return responses, nil
+}
+
+func (v *voucherServiceImpl) Preview(ctx context.Context, userID uint, req *dto.VoucherPreviewReq) (*dto.VoucherPreviewResponse, error) {
+	if err := v.Validate.Struct(req); err != nil {
+		return nil, handling.ErrorValidation
+	}
+
+	cart, err := v.CartRepo.GetCartByID(ctx, req.CartID)
+	if err != nil {
+		if errors.Is(err, handling.ErrorIdNotFound) {
+			return nil, handling.ErrorIdNotFound
+		}
+		return nil, fmt.Errorf("voucher service: preview: find cart: %w", err)
+	}
+
+	if cart.UserID != userID {
+		return nil, handling.ErrorIdNotFound
+	}
+
+	if cart.Status == constanta.Checkout {
+		return nil, handling.ErrCheckoutCart
+	}
+
+	voucher, quote, err := quoteVoucher(ctx, v.VoucherRepo, req.Code, userID, cart, time.Now())
+	if err != nil {
+		if isVoucherError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("voucher service: preview: %w", err)
+	}
+
+	return &dto.VoucherPreviewResponse{
+		Code:             voucher.Code,
+		Subtotal:         cart.Amount,
+		EligibleSubtotal: quote.Eligible,
+		Discount:         quote.Discount,
+		Total:            cart.Amount - quote.Discount,
+	}, nil
+}
+
+type voucherQuote struct {
+	Eligible float64
+	Discount float64
+}
+
+func isVoucherError(err error) bool {
+	for _, v := range []error{
+		handling.ErrVoucherInvalid,
+		handling.ErrVoucherMinSpend,
+		handling.ErrVoucherNotEligible,
+		handling.ErrVoucherExhausted,
+		handling.ErrVoucherUserLimit,
+	} {
+		if errors.Is(err, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteVoucher checks a code against a cart and prices the discount. Limits are checked again
+// under a row lock when the order is created, this only gives the customer an early answer.
+func quoteVoucher(ctx context.Context, voucherRepo repository.VoucherRepository, code string, userID uint, cart *entity.Cart, now time.Time) (*entity.Voucher, *voucherQuote, error) {
+	voucher, err := voucherRepo.FindByCode(ctx, normalizeCode(code))
+	if err != nil {
+		if errors.Is(err, handling.ErrVoucherNotFound) {
+			return nil, nil, handling.ErrVoucherInvalid
+		}
+		return nil, nil, fmt.Errorf("find voucher: %w", err)
+	}
+
+	if !voucher.Running(now) {
+		return nil, nil, handling.ErrVoucherInvalid
+	}
+
+	if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+		return nil, nil, handling.ErrVoucherExhausted
+	}
+
+	if voucher.PerUserLimit > 0 {
+		used, err := voucherRepo.CountRedemptions(ctx, voucher.ID, userID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("count redemptions: %w", err)
+		}
+
+		if used >= int64(voucher.PerUserLimit) {
+			return nil, nil, handling.ErrVoucherUserLimit
+		}
+	}
+
+	//a voucher without menus or categories applies to the whole cart
+	eligible := cart.Amount
+	if len(voucher.Menus) > 0 || voucher.Categories != "" {
+		menus := map[uint]bool{}
+		for _, v := range voucher.Menus {
+			menus[v.ID] = true
+		}
+
+		categories := map[string]bool{}
+		for _, v := range strings.Split(voucher.Categories, ",") {
+			categories[v] = true
+		}
+
+		eligible = 0
+		for _, v := range cart.CartMenu {
+			if menus[v.MenuID] || categories[v.Menu.Category] {
+				eligible += v.UnitPrice * float64(v.Qty)
+			}
+		}
+	}
+
+	if eligible <= 0 {
+		return nil, nil, handling.ErrVoucherNotEligible
+	}
+
+	if eligible < voucher.MinSpend {
+		return nil, nil, handling.ErrVoucherMinSpend
+	}
+
+	discount := voucher.Value
+	if voucher.Type == constanta.VoucherPercentage {
+		discount = eligible * voucher.Value / 100
+		if voucher.MaxDiscount > 0 {
+			discount = math.Min(discount, voucher.MaxDiscount)
+		}
+	}
+
+	//promotions already took their share, the voucher can't push the cart below zero
+	discount = math.Round(math.Min(discount, math.Min(eligible, cart.Amount))*100) / 100
+
+	return voucher, &voucherQuote{Eligible: eligible, Discount: discount}, nil
+}
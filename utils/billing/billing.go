@@ -0,0 +1,129 @@
+// Package billing turns a priced cart into the bill printed for the customer.
+//
+// Rounding rules:
+//   - service charge and tax are summed over the whole bill and rounded once, to 2 decimals,
+//     half away from zero, never per line
+//   - discounts are shared between categories in proportion to their subtotal before tax is worked out
+//   - an inclusive rate is already part of the menu price, the tax is taken out of the price
+//     (price * rate / (100 + rate)) and only shown on the bill
+//   - service charge is worked out on the price without tax and is itself taxed at the category rate
+//   - delivery fee is neither charged service nor taxed
+package billing
+
+import (
+	"math"
+	"online-food/entity"
+	"sort"
+)
+
+type Tax struct {
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+}
+
+type Config struct {
+	ServiceCharge float64        `json:"service_charge"`
+	Taxes         map[string]Tax `json:"taxes"`
+}
+
+type Line struct {
+	Category string
+	Amount   float64
+}
+
+type TaxLine struct {
+	Category  string
+	Rate      float64
+	Inclusive bool
+	Base      float64
+	Amount    float64
+}
+
+type Bill struct {
+	Subtotal      float64
+	Discount      float64
+	ServiceCharge float64
+	Tax           float64
+	TaxIncluded   float64
+	DeliveryFee   float64
+	Total         float64
+	Taxes         []TaxLine
+}
+
+func Round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Compute prices the lines with the given discount (promotions and voucher together) and delivery fee.
+func Compute(lines []Line, discount, deliveryFee float64, config Config) Bill {
+	gross := map[string]float64{}
+	subtotal := 0.0
+	for _, v := range lines {
+		gross[v.Category] += v.Amount
+		subtotal += v.Amount
+	}
+
+	categories := make([]string, 0, len(gross))
+	for k := range gross {
+		categories = append(categories, k)
+	}
+	sort.Strings(categories)
+
+	discount = math.Min(math.Max(discount, 0), subtotal)
+	net := subtotal - discount
+
+	service, tax, included := 0.0, 0.0, 0.0
+	taxes := []TaxLine{}
+	for _, category := range categories {
+		amount := gross[category]
+		if subtotal > 0 {
+			amount -= discount * gross[category] / subtotal
+		}
+
+		rate := config.Taxes[category]
+		base := amount
+		if rate.Inclusive && rate.Rate > 0 {
+			base = amount * 100 / (100 + rate.Rate)
+			included += amount - base
+		}
+
+		charge := base * config.ServiceCharge / 100
+		service += charge
+
+		if rate.Rate <= 0 {
+			continue
+		}
+
+		amountTax := (base + charge) * rate.Rate / 100
+		tax += amountTax
+		taxes = append(taxes, TaxLine{
+			Category:  category,
+			Rate:      rate.Rate,
+			Inclusive: rate.Inclusive,
+			Base:      Round(base + charge),
+			Amount:    Round(amountTax),
+		})
+	}
+
+	bill := Bill{
+		Subtotal:      Round(subtotal),
+		Discount:      Round(discount),
+		ServiceCharge: Round(service),
+		Tax:           Round(tax),
+		TaxIncluded:   Round(included),
+		DeliveryFee:   Round(deliveryFee),
+		Taxes:         taxes,
+	}
+
+	bill.Total = Round(net + bill.ServiceCharge + bill.Tax - bill.TaxIncluded + bill.DeliveryFee)
+	return bill
+}
+
+// CartLines groups the cart items for Compute, the menus have to be preloaded for their category.
+func CartLines(cart *entity.Cart) []Line {
+	lines := make([]Line, 0, len(cart.CartMenu))
+	for _, v := range cart.CartMenu {
+		lines = append(lines, Line{Category: v.Menu.Category, Amount: v.UnitPrice * float64(v.Qty)})
+	}
+	return lines
+}
@@ -0,0 +1,92 @@
+package billing
+
+import "testing"
+
+func TestCompute(t *testing.T) {
+	exclusive := Tax{Rate: 10}
+	inclusive := Tax{Rate: 10, Inclusive: true}
+
+	tests := []struct {
+		name                               string
+		lines                              []Line
+		discount, deliveryFee              float64
+		config                             Config
+		wantService, wantTax, wantIncluded float64
+		wantTotal                          float64
+	}{
+		{
+			name:        "exclusive with service",
+			lines:       []Line{{"food", 100000}},
+			config:      Config{ServiceCharge: 5, Taxes: map[string]Tax{"food": exclusive}},
+			wantService: 5000, wantTax: 10500, wantTotal: 115500,
+		},
+		{
+			name:    "inclusive is only shown",
+			lines:   []Line{{"drink", 11000}},
+			config:  Config{Taxes: map[string]Tax{"drink": inclusive}},
+			wantTax: 1000, wantIncluded: 1000, wantTotal: 11000,
+		},
+		{
+			name:        "inclusive service on the price without tax",
+			lines:       []Line{{"drink", 11000}},
+			config:      Config{ServiceCharge: 10, Taxes: map[string]Tax{"drink": inclusive}},
+			wantService: 1000, wantTax: 1100, wantIncluded: 1000, wantTotal: 12100,
+		},
+		{
+			name:    "rounded once for the whole bill",
+			lines:   []Line{{"drink", 1000.05}, {"food", 1000.05}},
+			config:  Config{Taxes: map[string]Tax{"drink": exclusive, "food": exclusive}},
+			wantTax: 200.01, wantTotal: 2200.11,
+		},
+		{
+			name:     "discount shared by subtotal",
+			lines:    []Line{{"food", 60000}, {"drink", 40000}},
+			discount: 10000,
+			config:   Config{Taxes: map[string]Tax{"food": exclusive, "drink": inclusive}},
+			wantTax:  8672.73, wantIncluded: 3272.73, wantTotal: 95400,
+		},
+		{
+			name:        "delivery fee is not charged",
+			lines:       []Line{{"food", 10000}},
+			deliveryFee: 5000,
+			config:      Config{ServiceCharge: 10, Taxes: map[string]Tax{"food": exclusive}},
+			wantService: 1000, wantTax: 1100, wantTotal: 17100,
+		},
+		{
+			name:      "discount capped at the subtotal",
+			lines:     []Line{{"food", 10000}},
+			discount:  15000,
+			config:    Config{Taxes: map[string]Tax{"food": exclusive}},
+			wantTotal: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bill := Compute(tt.lines, tt.discount, tt.deliveryFee, tt.config)
+
+			if bill.ServiceCharge != tt.wantService || bill.Tax != tt.wantTax || bill.TaxIncluded != tt.wantIncluded || bill.Total != tt.wantTotal {
+				t.Fatalf("service %v tax %v included %v total %v, want %v %v %v %v",
+					bill.ServiceCharge, bill.Tax, bill.TaxIncluded, bill.Total,
+					tt.wantService, tt.wantTax, tt.wantIncluded, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount, want float64
+	}{
+		{1.234, 1.23},
+		{1.235, 1.24},
+		{-1.235, -1.24},
+		{2.5, 2.5},
+	}
+
+	for _, tt := range tests {
+		if got := Round(tt.amount); got != tt.want {
+			t.Errorf("Round(%v) = %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package broker fans order events out to everyone streaming them. The memory broker only reaches
+// subscribers of this process, the redis broker relays through pub/sub so every instance gets them.
+package broker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	OrderCreated  string = "order.created"
+	OrderStatus   string = "order.status"
+	OrderCourier  string = "order.courier"
+	OrderItemBump string = "order.item_bumped"
+	OrderRecalled string = "order.recalled"
+	OrderOffered  string = "order.offered"
+	OrderReleased string = "order.released"
+	subscriberBuf int    = 64
+)
+
+type OrderEvent struct {
+	Type      string    `json:"type"`
+	OrderID   uint      `json:"order_id"`
+	UserID    uint      `json:"user_id"`
+	CourierID *uint     `json:"courier_id,omitempty"`
+	ItemIDs   []uint    `json:"item_ids,omitempty"`
+	OfferID   uint      `json:"offer_id,omitempty"`
+	From      string    `json:"from,omitempty"`
+	Status    string    `json:"status"`
+	At        time.Time `json:"at"`
+}
+
+type Broker interface {
+	Publish(ctx context.Context, event OrderEvent) error
+	// Subscribe returns the events published from now on, cancel has to be called to let go of them.
+	Subscribe() (<-chan OrderEvent, func())
+}
+
+// hub hands each event to every local subscriber, one that doesn't keep up misses events
+// rather than holding up the publisher.
+type hub struct {
+	mu          sync.Mutex
+	subscribers map[chan OrderEvent]struct{}
+}
+
+func newHub() *hub {
+	return &hub{subscribers: map[chan OrderEvent]struct{}{}}
+}
+
+func (h *hub) deliver(event OrderEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (h *hub) Subscribe() (<-chan OrderEvent, func()) {
+	ch := make(chan OrderEvent, subscriberBuf)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			close(ch)
+			h.mu.Unlock()
+		})
+	}
+
+	return ch, cancel
+}
+
+type memoryBroker struct {
+	*hub
+}
+
+func NewMemoryBroker() *memoryBroker {
+	return &memoryBroker{hub: newHub()}
+}
+
+func (m *memoryBroker) Publish(ctx context.Context, event OrderEvent) error {
+	m.deliver(event)
+	return nil
+}
@@ -0,0 +1,51 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type redisBroker struct {
+	*hub
+	Rdb     *redis.Client
+	Channel string
+}
+
+// NewRedisBroker publishes on the redis channel and relays what arrives there to local subscribers,
+// events published here come back through redis too so every instance sees them the same way.
+func NewRedisBroker(ctx context.Context, rdb *redis.Client, channel string) *redisBroker {
+	r := &redisBroker{
+		hub:     newHub(),
+		Rdb:     rdb,
+		Channel: channel,
+	}
+
+	pubsub := rdb.Subscribe(ctx, channel)
+	go func() {
+		defer pubsub.Close()
+
+		for msg := range pubsub.Channel() {
+			var event OrderEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Printf("redis broker: decode event: %v", err)
+				continue
+			}
+
+			r.deliver(event)
+		}
+	}()
+
+	return r
+}
+
+func (r *redisBroker) Publish(ctx context.Context, event OrderEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return r.Rdb.Publish(ctx, r.Channel, data).Err()
+}
@@ -3,6 +3,9 @@ package constanta
 const (
 	Customer string = "customer"
 	Admin    string = "admin"
+	Kitchen  string = "kitchen"
+	Cashier  string = "cashier"
+	Courier  string = "courier"
 )
 
 const (
@@ -11,7 +14,105 @@ const (
 )
 
 const (
-	Pending   string = "pending"
-	Paid      string = "paid"
-	Cancelled string = "cancelled"
+	Pending    string = "pending"
+	Paid       string = "paid"
+	Preparing  string = "preparing"
+	Ready      string = "ready"
+	Delivering string = "delivering"
+	Delivered  string = "delivered"
+	Cancelled  string = "cancelled"
+)
+
+const (
+	DeliveryAssigned  string = "assigned"
+	DeliveryPickedUp  string = "picked_up"
+	DeliveryDelivered string = "delivered"
+	DeliveryFailed    string = "failed"
+)
+
+const (
+	OfferPending   string = "pending"
+	OfferAccepted  string = "accepted"
+	OfferDeclined  string = "declined"
+	OfferExpired   string = "expired"
+	OfferWithdrawn string = "withdrawn"
+)
+
+const (
+	Cash     string = "cash"
+	Transfer string = "transfer"
+)
+
+const (
+	ZoneRadius  string = "radius"
+	ZonePolygon string = "polygon"
+)
+
+const (
+	VoucherPercentage string = "percentage"
+	VoucherFixed      string = "fixed"
+)
+
+const (
+	PromoBogo      string = "bogo"
+	PromoHappyHour string = "happy_hour"
+	PromoSpendSave string = "spend_save"
+)
+
+const (
+	KitchenSnapshot      string = "snapshot"
+	KitchenOrderAdded    string = "order.added"
+	KitchenItemBumped    string = "item.bumped"
+	KitchenOrderRecalled string = "order.recalled"
+	KitchenOrderRemoved  string = "order.removed"
+	KitchenBump          string = "bump"
+	KitchenRecall        string = "recall"
+	KitchenPing          string = "ping"
+	KitchenPong          string = "pong"
+	KitchenError         string = "error"
+	KitchenProtocol      string = "bearer"
+)
+
+const (
+	ReorderRemoved      string = "removed"
+	ReorderOutOfStock   string = "out_of_stock"
+	ReorderQtyReduced   string = "qty_reduced"
+	ReorderPriceChanged string = "price_changed"
+)
+
+const (
+	RecommendForYou         string = "for_you"
+	RecommendBestSeller     string = "best_seller"
+	RecommendBoughtTogether string = "bought_together"
+)
+
+const (
+	ReportDay   string = "day"
+	ReportWeek  string = "week"
+	ReportMonth string = "month"
+)
+
+const (
+	ReportRevenue string = "revenue"
+	ReportOrders  string = "orders"
+	ReportMenus   string = "menus"
+	ReportSummary string = "summary"
+)
+
+const (
+	SettingAdminTwoFactor string = "two_factor_required_admin"
+	SettingServiceCharge  string = "service_charge_percent"
+	SettingTaxRates       string = "tax_rates"
+	SettingSchedule       string = "schedule"
+)
+
+const (
+	AccessToken  string = "access"
+	RefreshToken string = "refresh"
+)
+
+const (
+	VerifyEmailPurpose    string = "verify_email"
+	LoginChallengePurpose string = "login_challenge"
+	TwoFactorSetupPurpose string = "two_factor_setup"
 )
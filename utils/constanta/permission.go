@@ -0,0 +1,85 @@
+package constanta
+
+const (
+	PermProfileRead     string = "profile:read"
+	PermProfileWrite    string = "profile:write"
+	PermUserRead        string = "user:read"
+	PermUserWrite       string = "user:write"
+	PermMenuRead        string = "menu:read"
+	PermMenuWrite       string = "menu:write"
+	PermCartRead        string = "cart:read"
+	PermCartWrite       string = "cart:write"
+	PermCartReadAll     string = "cart:read_all"
+	PermSettingRead     string = "setting:read"
+	PermSettingWrite    string = "setting:write"
+	PermRoleManage      string = "role:manage"
+	PermOrderRead       string = "order:read"
+	PermOrderReadAll    string = "order:read_all"
+	PermOrderManage     string = "order:manage"
+	PermOrderPrepare    string = "order:prepare"
+	PermOrderPay        string = "order:pay"
+	PermOrderDeliver    string = "order:deliver"
+	PermZoneRead        string = "delivery_zone:read"
+	PermZoneWrite       string = "delivery_zone:write"
+	PermVoucherManage   string = "voucher:manage"
+	PermPromotionManage string = "promotion:manage"
+	PermReportRead      string = "report:read"
+)
+
+// Permissions is the catalog seeded into the permissions table, endpoints can only check these.
+var Permissions = map[string]string{
+	PermProfileRead:     "read own profile",
+	PermProfileWrite:    "update own profile and security settings",
+	PermUserRead:        "read any user",
+	PermUserWrite:       "delete, unlock and manage users",
+	PermMenuRead:        "read menus",
+	PermMenuWrite:       "create, update and delete menus",
+	PermCartRead:        "read own carts",
+	PermCartWrite:       "create, update and checkout own carts",
+	PermCartReadAll:     "read every cart",
+	PermSettingRead:     "read application settings",
+	PermSettingWrite:    "update application settings",
+	PermRoleManage:      "manage roles and their permissions",
+	PermOrderRead:       "read own orders",
+	PermOrderReadAll:    "read every order",
+	PermOrderManage:     "change any order status and assign couriers",
+	PermOrderPrepare:    "see and advance orders in the kitchen",
+	PermOrderPay:        "mark cash orders as paid",
+	PermOrderDeliver:    "see and deliver assigned orders",
+	PermZoneRead:        "read delivery zones",
+	PermZoneWrite:       "create, update and delete delivery zones",
+	PermVoucherManage:   "create, update and delete vouchers",
+	PermPromotionManage: "create, update and schedule automatic promotions",
+	PermReportRead:      "read sales reports",
+}
+
+// DefaultRoles are created on startup when missing, the admin role always receives the full catalog.
+var DefaultRoles = map[string][]string{
+	Customer: {
+		PermProfileRead,
+		PermProfileWrite,
+		PermMenuRead,
+		PermCartRead,
+		PermCartWrite,
+		PermOrderRead,
+		PermZoneRead,
+	},
+	Kitchen: {
+		PermProfileRead,
+		PermProfileWrite,
+		PermMenuRead,
+		PermOrderPrepare,
+	},
+	Cashier: {
+		PermProfileRead,
+		PermProfileWrite,
+		PermMenuRead,
+		PermOrderPay,
+	},
+	Courier: {
+		PermProfileRead,
+		PermProfileWrite,
+		PermOrderDeliver,
+	},
+	Admin: {},
+}
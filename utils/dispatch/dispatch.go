@@ -0,0 +1,81 @@
+// Package dispatch ranks couriers for a ready order. It has no clock or database of its own, the caller passes
+// everything in, so the same input always gives the same order.
+//
+// A courier's score is the sum of
+//   - PerKm for every km between the courier and the outlet
+//   - PerOrder for every delivery the courier is already carrying
+//   - minus PerIdleMinute for every minute since the courier's last assignment, up to MaxIdle
+//
+// the lowest score goes first, equal scores go to the lower courier id.
+package dispatch
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type Weights struct {
+	PerKm         float64
+	PerOrder      float64
+	PerIdleMinute float64
+	MaxIdle       time.Duration
+}
+
+// DefaultWeights make a courier 2 km further away worth one more order in the bag, and half an hour
+// of waiting worth 1.5 km.
+var DefaultWeights = Weights{
+	PerKm:         1,
+	PerOrder:      2,
+	PerIdleMinute: 0.05,
+	MaxIdle:       time.Hour,
+}
+
+type Candidate struct {
+	CourierID    uint
+	DistanceKm   float64
+	Load         int
+	LastAssigned *time.Time
+}
+
+type Ranked struct {
+	Candidate
+	Score float64
+}
+
+// Score is how costly it is to send this courier, lower is better. A courier who was never assigned
+// counts as idle for the whole MaxIdle.
+func Score(candidate Candidate, weights Weights, now time.Time) float64 {
+	idle := weights.MaxIdle
+	if candidate.LastAssigned != nil {
+		idle = now.Sub(*candidate.LastAssigned)
+	}
+
+	if idle < 0 {
+		idle = 0
+	}
+
+	if idle > weights.MaxIdle {
+		idle = weights.MaxIdle
+	}
+
+	score := candidate.DistanceKm*weights.PerKm + float64(candidate.Load)*weights.PerOrder - idle.Minutes()*weights.PerIdleMinute
+	return math.Round(score*1000) / 1000
+}
+
+// Rank orders the candidates best first.
+func Rank(candidates []Candidate, weights Weights, now time.Time) []Ranked {
+	ranked := make([]Ranked, 0, len(candidates))
+	for _, v := range candidates {
+		ranked = append(ranked, Ranked{Candidate: v, Score: Score(v, weights, now)})
+	}
+
+	sort.Slice(ranked, func(a, b int) bool {
+		if ranked[a].Score != ranked[b].Score {
+			return ranked[a].Score < ranked[b].Score
+		}
+		return ranked[a].CourierID < ranked[b].CourierID
+	})
+
+	return ranked
+}
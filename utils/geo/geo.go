@@ -0,0 +1,38 @@
+package geo
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// Point is a latitude, longitude pair in degrees.
+type Point [2]float64
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// Haversine returns the great circle distance between two coordinates in kilometres.
+func Haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// InPolygon uses ray casting, which is accurate enough for delivery areas of a few kilometres.
+func InPolygon(lat, lng float64, polygon []Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		latI, lngI := polygon[i][0], polygon[i][1]
+		latJ, lngJ := polygon[j][0], polygon[j][1]
+
+		if (lngI > lng) != (lngJ > lng) && lat < (latJ-latI)*(lng-lngI)/(lngJ-lngI)+latI {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
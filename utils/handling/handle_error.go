@@ -9,16 +9,72 @@ import (
 )
 
 var (
-	ErrorIdNotFound    = errors.New("id not found")
-	ErrorEmailNotFound = errors.New("email not found")
-	ErrorEmailExist    = errors.New("email already exist")
-	ErrNotEnoughStock  = errors.New("not enough stock")
-	ErrorValidation    = errors.New("validation failed")
-	ErrFailedLogin     = errors.New("email or password wrong")
-	ErrInvalidToken    = errors.New("invalid token refresh")
-	ErrEmptyItems      = errors.New("cart has no items")
-	ErrMenuNotFound    = errors.New("menu not found")
-	ErrCheckoutCart    = errors.New("cart already checkout")
+	ErrorIdNotFound          = errors.New("id not found")
+	ErrorEmailNotFound       = errors.New("email not found")
+	ErrorEmailExist          = errors.New("email already exist")
+	ErrNotEnoughStock        = errors.New("not enough stock")
+	ErrorValidation          = errors.New("validation failed")
+	ErrFailedLogin           = errors.New("email or password wrong")
+	ErrInvalidToken          = errors.New("invalid token refresh")
+	ErrEmptyItems            = errors.New("cart has no items")
+	ErrMenuNotFound          = errors.New("menu not found")
+	ErrCheckoutCart          = errors.New("cart already checkout")
+	ErrEmailNotVerified      = errors.New("email not verified")
+	ErrInvalidVerifyToken    = errors.New("invalid verification token")
+	ErrInvalidResetToken     = errors.New("invalid reset token")
+	ErrTooManyAttempts       = errors.New("too many login attempts")
+	ErrTwoFactorEnabled      = errors.New("two factor already enabled")
+	ErrTwoFactorNotSetup     = errors.New("two factor not set up")
+	ErrTwoFactorRequired     = errors.New("two factor required")
+	ErrInvalidTwoFactorCode  = errors.New("invalid two factor code")
+	ErrInvalidChallenge      = errors.New("invalid challenge token")
+	ErrRoleNotFound          = errors.New("role not found")
+	ErrRoleExist             = errors.New("role already exist")
+	ErrRoleInUse             = errors.New("role in use")
+	ErrSystemRole            = errors.New("system role")
+	ErrUnknownPermission     = errors.New("unknown permission")
+	ErrRoleNotGrantable      = errors.New("role not grantable")
+	ErrInvalidOrderStatus    = errors.New("invalid order status")
+	ErrNotCashOrder          = errors.New("order is not paid by cash")
+	ErrNotCourier            = errors.New("user is not a courier")
+	ErrOrderNotAssigned      = errors.New("order not assigned")
+	ErrOwnAccount            = errors.New("action not allowed on own account")
+	ErrUserSuspended         = errors.New("user suspended")
+	ErrUserNotSuspended      = errors.New("user not suspended")
+	ErrUserNotDeleted        = errors.New("user not deleted")
+	ErrUserHasOrders         = errors.New("user has orders")
+	ErrAddressNotFound       = errors.New("address not found")
+	ErrZoneNotFound          = errors.New("delivery zone not found")
+	ErrOutsideDeliveryZone   = errors.New("outside delivery zone")
+	ErrBelowMinimumOrder     = errors.New("below minimum order")
+	ErrLocationRequired      = errors.New("delivery location required")
+	ErrOutletLocationUnset   = errors.New("outlet location unset")
+	ErrCartChanged           = errors.New("cart changed")
+	ErrVoucherNotFound       = errors.New("voucher not found")
+	ErrVoucherExist          = errors.New("voucher already exist")
+	ErrVoucherInvalid        = errors.New("voucher invalid")
+	ErrVoucherMinSpend       = errors.New("voucher minimum spend not met")
+	ErrVoucherNotEligible    = errors.New("voucher not eligible")
+	ErrVoucherExhausted      = errors.New("voucher usage limit reached")
+	ErrVoucherUserLimit      = errors.New("voucher user limit reached")
+	ErrPromotionNotFound     = errors.New("promotion not found")
+	ErrInvoiceUnavailable    = errors.New("invoice unavailable")
+	ErrOrderItemNotFound     = errors.New("order item not found")
+	ErrDeliveryNotFound      = errors.New("delivery not found")
+	ErrDeliveryInProgress    = errors.New("delivery in progress")
+	ErrInvalidDeliveryStatus = errors.New("invalid delivery status")
+	ErrFailReasonRequired    = errors.New("fail reason required")
+	ErrPhotoNotFound         = errors.New("photo not found")
+	ErrOfferNotFound         = errors.New("offer not found")
+	ErrOfferClosed           = errors.New("offer closed")
+	ErrSlotUnavailable       = errors.New("slot unavailable")
+	ErrSlotFull              = errors.New("slot full")
+	ErrOutletClosed          = errors.New("outlet closed")
+	ErrNothingToReorder      = errors.New("nothing to reorder")
+	ErrFavoriteNotFound      = errors.New("favorite not found")
+	ErrListNotFound          = errors.New("saved list not found")
+	ErrListExist             = errors.New("saved list exist")
+	ErrInvalidPhoto          = errors.New("invalid photo")
 )
 
 var errorMapping = map[error]struct {
@@ -27,16 +83,83 @@ var errorMapping = map[error]struct {
 	Message string
 	Data    interface{}
 }{
-	ErrorEmailExist:    {http.StatusConflict, "Conflict", "email already exists", nil},
-	ErrorValidation:    {http.StatusBadRequest, "Bad Request", "invalid input", nil},
-	ErrNotEnoughStock:  {http.StatusBadRequest, "Bad Request", "not enough stock", nil},
-	ErrFailedLogin:     {http.StatusBadRequest, "Bad Request", "email or password wrong", nil},
-	ErrInvalidToken:    {http.StatusBadRequest, "Bad Request", "invalid token refresh", nil},
-	ErrorEmailNotFound: {http.StatusNotFound, "Not Found", "email not found", nil},
-	ErrorIdNotFound:    {http.StatusNotFound, "Not Found", "id not found", nil},
-	ErrMenuNotFound:    {http.StatusNotFound, "Not Found", "menu not found", nil},
-	ErrEmptyItems:      {http.StatusBadRequest, "Bad Request", "cart has no items", nil},
-	ErrCheckoutCart:    {http.StatusBadRequest, "Bad Request", "cart already checkout", nil},
+	ErrorEmailExist:          {http.StatusConflict, "Conflict", "email already exists", nil},
+	ErrorValidation:          {http.StatusBadRequest, "Bad Request", "invalid input", nil},
+	ErrNotEnoughStock:        {http.StatusBadRequest, "Bad Request", "not enough stock", nil},
+	ErrFailedLogin:           {http.StatusBadRequest, "Bad Request", "email or password wrong", nil},
+	ErrInvalidToken:          {http.StatusBadRequest, "Bad Request", "invalid token refresh", nil},
+	ErrorEmailNotFound:       {http.StatusNotFound, "Not Found", "email not found", nil},
+	ErrorIdNotFound:          {http.StatusNotFound, "Not Found", "id not found", nil},
+	ErrMenuNotFound:          {http.StatusNotFound, "Not Found", "menu not found", nil},
+	ErrEmptyItems:            {http.StatusBadRequest, "Bad Request", "cart has no items", nil},
+	ErrCheckoutCart:          {http.StatusBadRequest, "Bad Request", "cart already checkout", nil},
+	ErrEmailNotVerified:      {http.StatusForbidden, "Forbidden", "email not verified", nil},
+	ErrInvalidVerifyToken:    {http.StatusBadRequest, "Bad Request", "invalid or expired verification token", nil},
+	ErrInvalidResetToken:     {http.StatusBadRequest, "Bad Request", "invalid or expired reset token", nil},
+	ErrTooManyAttempts:       {http.StatusTooManyRequests, "Too Many Requests", "too many login attempts, try again later", nil},
+	ErrTwoFactorEnabled:      {http.StatusConflict, "Conflict", "two factor already enabled", nil},
+	ErrTwoFactorNotSetup:     {http.StatusBadRequest, "Bad Request", "two factor setup has not been started", nil},
+	ErrTwoFactorRequired:     {http.StatusForbidden, "Forbidden", "two factor is mandatory for this role", nil},
+	ErrInvalidTwoFactorCode:  {http.StatusUnauthorized, "Unauthorization", "invalid two factor code", nil},
+	ErrInvalidChallenge:      {http.StatusUnauthorized, "Unauthorization", "invalid or expired challenge token", nil},
+	ErrRoleNotFound:          {http.StatusNotFound, "Not Found", "role not found", nil},
+	ErrRoleExist:             {http.StatusConflict, "Conflict", "role already exists", nil},
+	ErrRoleInUse:             {http.StatusConflict, "Conflict", "role is still assigned to users", nil},
+	ErrSystemRole:            {http.StatusForbidden, "Forbidden", "system role can't be changed", nil},
+	ErrUnknownPermission:     {http.StatusBadRequest, "Bad Request", "unknown permission", nil},
+	ErrRoleNotGrantable:      {http.StatusForbidden, "Forbidden", "you can only change roles whose permissions you hold", nil},
+	ErrInvalidOrderStatus:    {http.StatusConflict, "Conflict", "order can't move to that status", nil},
+	ErrNotCashOrder:          {http.StatusBadRequest, "Bad Request", "order is not paid by cash", nil},
+	ErrNotCourier:            {http.StatusBadRequest, "Bad Request", "user is not a courier", nil},
+	ErrOrderNotAssigned:      {http.StatusForbidden, "Forbidden", "order is not assigned to you", nil},
+	ErrOwnAccount:            {http.StatusBadRequest, "Bad Request", "this action is not allowed on your own account", nil},
+	ErrUserSuspended:         {http.StatusForbidden, "Forbidden", "account is suspended", nil},
+	ErrUserNotSuspended:      {http.StatusConflict, "Conflict", "user is not suspended", nil},
+	ErrUserNotDeleted:        {http.StatusConflict, "Conflict", "user is not deleted", nil},
+	ErrUserHasOrders:         {http.StatusConflict, "Conflict", "user has orders and can't be purged", nil},
+	ErrAddressNotFound:       {http.StatusNotFound, "Not Found", "address not found", nil},
+	ErrZoneNotFound:          {http.StatusNotFound, "Not Found", "delivery zone not found", nil},
+	ErrOutsideDeliveryZone:   {http.StatusUnprocessableEntity, "Unprocessable Entity", "address is outside every delivery zone", nil},
+	ErrBelowMinimumOrder:     {http.StatusUnprocessableEntity, "Unprocessable Entity", "order is below the minimum for this delivery zone", nil},
+	ErrLocationRequired:      {http.StatusBadRequest, "Bad Request", "checkout needs a saved address with coordinates", nil},
+	ErrOutletLocationUnset:   {http.StatusUnprocessableEntity, "Unprocessable Entity", "radius zones need the outlet location in OUTLET_LATITUDE and OUTLET_LONGITUDE", nil},
+	ErrCartChanged:           {http.StatusConflict, "Conflict", "cart changed during checkout, please try again", nil},
+	ErrVoucherNotFound:       {http.StatusNotFound, "Not Found", "voucher not found", nil},
+	ErrVoucherExist:          {http.StatusConflict, "Conflict", "voucher code already exist", nil},
+	ErrVoucherInvalid:        {http.StatusUnprocessableEntity, "Unprocessable Entity", "voucher code is not valid or has expired", nil},
+	ErrVoucherMinSpend:       {http.StatusUnprocessableEntity, "Unprocessable Entity", "cart does not reach the voucher minimum spend", nil},
+	ErrVoucherNotEligible:    {http.StatusUnprocessableEntity, "Unprocessable Entity", "no item in the cart is eligible for this voucher", nil},
+	ErrVoucherExhausted:      {http.StatusUnprocessableEntity, "Unprocessable Entity", "voucher has been fully redeemed", nil},
+	ErrVoucherUserLimit:      {http.StatusUnprocessableEntity, "Unprocessable Entity", "you have used this voucher the maximum number of times", nil},
+	ErrPromotionNotFound:     {http.StatusNotFound, "Not Found", "promotion not found", nil},
+	ErrInvoiceUnavailable:    {http.StatusConflict, "Conflict", "cancelled orders have no invoice", nil},
+	ErrOrderItemNotFound:     {http.StatusNotFound, "Not Found", "order item not found", nil},
+	ErrDeliveryNotFound:      {http.StatusNotFound, "Not Found", "order has no delivery yet", nil},
+	ErrDeliveryInProgress:    {http.StatusConflict, "Conflict", "order is already on the way with another courier", nil},
+	ErrInvalidDeliveryStatus: {http.StatusConflict, "Conflict", "delivery can't move to that status", nil},
+	ErrFailReasonRequired:    {http.StatusUnprocessableEntity, "Unprocessable Entity", "notes are required when a delivery fails", nil},
+	ErrPhotoNotFound:         {http.StatusNotFound, "Not Found", "delivery has no photo", nil},
+	ErrOfferNotFound:         {http.StatusNotFound, "Not Found", "offer not found", nil},
+	ErrOfferClosed:           {http.StatusConflict, "Conflict", "offer has expired or the order is no longer available", nil},
+	ErrSlotUnavailable:       {http.StatusUnprocessableEntity, "Unprocessable Entity", "no delivery slot starts at that time or it can no longer be booked", nil},
+	ErrSlotFull:              {http.StatusConflict, "Conflict", "delivery slot is fully booked", nil},
+	ErrOutletClosed:          {http.StatusUnprocessableEntity, "Unprocessable Entity", "outlet is closed, schedule the order for a later slot", nil},
+	ErrNothingToReorder:      {http.StatusUnprocessableEntity, "Unprocessable Entity", "none of the items of this order are available anymore", nil},
+	ErrFavoriteNotFound:      {http.StatusNotFound, "Not Found", "menu is not in your favorites", nil},
+	ErrListNotFound:          {http.StatusNotFound, "Not Found", "saved list not found", nil},
+	ErrListExist:             {http.StatusConflict, "Conflict", "a saved list with this name already exists", nil},
+	ErrInvalidPhoto:          {http.StatusBadRequest, "Bad Request", "photo must be a jpeg, png or webp image up to 5 MB", nil},
+}
+
+// Message is the text HandleError would answer with, for replies that don't go over http.
+func Message(err error) string {
+	for key, v := range errorMapping {
+		if errors.Is(err, key) {
+			return v.Message
+		}
+	}
+
+	return "internal server error"
 }
 
 func HandleError(ctx *gin.Context, err error) {
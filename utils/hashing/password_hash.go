@@ -1,13 +1,31 @@
 package hashing
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
 
 func HashPassword(pass string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(pass), 10)
 	return string(bytes), err
 }
 
-func CompareHashPassword(hash, pass  string) bool {
+func CompareHashPassword(hash, pass string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(pass))
 	return err == nil
-}
\ No newline at end of file
+}
+
+var (
+	dummyOnce sync.Once
+	dummyHash string
+)
+
+// DummyHash is compared against when the account does not exist, so the response time matches a real check.
+func DummyHash() string {
+	dummyOnce.Do(func() {
+		bytes, _ := bcrypt.GenerateFromPassword([]byte("online-food-dummy-password"), 10)
+		dummyHash = string(bytes)
+	})
+	return dummyHash
+}
@@ -0,0 +1,115 @@
+// Package invoice prints an order as a PDF invoice or a receipt for 58mm thermal printers.
+// Everything is read from the order snapshot, a menu renamed or repriced later doesn't change old bills.
+package invoice
+
+import (
+	"fmt"
+	"math"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"strings"
+	"time"
+)
+
+type Outlet struct {
+	Name    string
+	Address string
+	Phone   string
+}
+
+type row struct {
+	Label  string
+	Amount float64
+}
+
+// Rupiah formats an amount the Indonesian way, 12500.5 becomes Rp12.500,50.
+func Rupiah(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	cents := int64(math.Round(amount * 100))
+	whole := fmt.Sprintf("%d", cents/100)
+
+	var groups []string
+	for len(whole) > 3 {
+		groups = append([]string{whole[len(whole)-3:]}, groups...)
+		whole = whole[:len(whole)-3]
+	}
+	groups = append([]string{whole}, groups...)
+
+	result := sign + "Rp" + strings.Join(groups, ".")
+	if cents%100 != 0 {
+		result += fmt.Sprintf(",%02d", cents%100)
+	}
+	return result
+}
+
+// Numbered tells whether an order in this status gets an invoice number, only money already taken does.
+// Before that the order prints as a proforma without a number.
+func Numbered(status string) bool {
+	switch status {
+	case constanta.Paid, constanta.Preparing, constanta.Ready, constanta.Delivering, constanta.Delivered:
+		return true
+	}
+	return false
+}
+
+// Number is the invoice number of the last invoice of the year, INV/2026/000042.
+func Number(year, last int) string {
+	return fmt.Sprintf("INV/%d/%06d", year, last)
+}
+
+func number(order *entity.Order) string {
+	if order.InvoiceNo != nil {
+		return *order.InvoiceNo
+	}
+	return fmt.Sprintf("ORDER-%d", order.ID)
+}
+
+func title(order *entity.Order) string {
+	if order.InvoiceNo != nil {
+		return "INVOICE"
+	}
+	return "PROFORMA INVOICE"
+}
+
+func issued(order *entity.Order) time.Time {
+	if order.InvoicedAt != nil {
+		return *order.InvoicedAt
+	}
+	return order.OrderDate
+}
+
+// summary lists the bill below the items, in the order the amounts were worked out.
+func summary(order *entity.Order) []row {
+	rows := []row{{"Subtotal", order.Subtotal}}
+
+	for _, v := range order.DiscountLines() {
+		rows = append(rows, row{v.Name, -v.Amount})
+	}
+
+	if order.Discount > 0 {
+		rows = append(rows, row{"Voucher " + order.VoucherCode, -order.Discount})
+	}
+
+	if order.ServiceCharge > 0 {
+		rows = append(rows, row{fmt.Sprintf("Service %g%%", order.ServiceRate), order.ServiceCharge})
+	}
+
+	for _, v := range order.Taxes {
+		label := fmt.Sprintf("PPN %g%% %s", v.Rate, v.Category)
+		if v.Inclusive {
+			label += " (incl.)"
+		}
+		rows = append(rows, row{label, v.Amount})
+	}
+
+	if order.DeliveryFee > 0 {
+		rows = append(rows, row{"Delivery", order.DeliveryFee})
+	}
+
+	return rows
+}
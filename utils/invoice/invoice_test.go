@@ -0,0 +1,105 @@
+package invoice
+
+import (
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNumbered(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{constanta.Pending, false},
+		{constanta.Cancelled, false},
+		{constanta.Paid, true},
+		{constanta.Preparing, true},
+		{constanta.Ready, true},
+		{constanta.Delivering, true},
+		{constanta.Delivered, true},
+	}
+
+	for _, tt := range tests {
+		if got := Numbered(tt.status); got != tt.want {
+			t.Errorf("Numbered(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		year, last int
+		want       string
+	}{
+		{2026, 1, "INV/2026/000001"},
+		{2026, 42, "INV/2026/000042"},
+		{2027, 123456, "INV/2027/123456"},
+	}
+
+	for _, tt := range tests {
+		if got := Number(tt.year, tt.last); got != tt.want {
+			t.Errorf("Number(%d, %d) = %q, want %q", tt.year, tt.last, got, tt.want)
+		}
+	}
+}
+
+func TestTextProformaAndSnapshot(t *testing.T) {
+	number := "INV/2026/000007"
+	base := entity.Order{
+		ID:            7,
+		Subtotal:      50000,
+		PromoDiscount: 5000,
+		AmountPay:     45000,
+		PaymentMethod: constanta.Cash,
+		OrderDate:     time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		Discounts:     []entity.OrderDiscount{{Name: "Happy hour", Amount: 5000}},
+		//the cart was priced again after checkout, the invoice must not follow it
+		Cart: entity.Cart{Discounts: []entity.CartDiscount{{Name: "Weekend deal", Amount: 9000}}},
+	}
+
+	tests := []struct {
+		name      string
+		status    string
+		invoiceNo *string
+		want      []string
+		not       []string
+	}{
+		{"pending is a proforma", constanta.Pending, nil, []string{"PROFORMA INVOICE", "ORDER-7", "Happy hour"}, []string{"INV/", "Weekend deal"}},
+		{"paid has its number", constanta.Paid, &number, []string{"INVOICE", number, "Happy hour"}, []string{"PROFORMA", "Weekend deal"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := base
+			order.Status = tt.status
+			order.InvoiceNo = tt.invoiceNo
+
+			text := Text(Outlet{Name: "Online Food"}, &order)
+			for _, v := range tt.want {
+				if !strings.Contains(text, v) {
+					t.Errorf("missing %q in\n%s", v, text)
+				}
+			}
+			for _, v := range tt.not {
+				if strings.Contains(text, v) {
+					t.Errorf("unexpected %q in\n%s", v, text)
+				}
+			}
+		})
+	}
+}
+
+func TestDiscountLinesFallsBackToCart(t *testing.T) {
+	order := entity.Order{
+		PromoDiscount: 9000,
+		Cart:          entity.Cart{Discounts: []entity.CartDiscount{{PromotionID: 3, Name: "Weekend deal", Amount: 9000}}},
+	}
+
+	lines := order.DiscountLines()
+	if len(lines) != 1 || lines[0].Name != "Weekend deal" || lines[0].Amount != 9000 {
+		t.Fatalf("lines = %+v, want the cart line", lines)
+	}
+}
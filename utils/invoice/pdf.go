@@ -0,0 +1,71 @@
+package invoice
+
+import (
+	"fmt"
+	"io"
+	"online-food/entity"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// PDF writes an A4 invoice for the order.
+func PDF(w io.Writer, outlet Outlet, order *entity.Order) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(number(order), true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(110, 8, tr(outlet.Name), "", 0, "L", false, 0, "")
+	pdf.CellFormat(70, 8, title(order), "", 1, "R", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(110, 5, tr(outlet.Address), "", 0, "L", false, 0, "")
+	pdf.CellFormat(70, 5, number(order), "", 1, "R", false, 0, "")
+	pdf.CellFormat(110, 5, tr(outlet.Phone), "", 0, "L", false, 0, "")
+	pdf.CellFormat(70, 5, issued(order).Format("02 Jan 2006 15:04"), "", 1, "R", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 6, "Bill to", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 5, tr(order.User.Name), "", 1, "L", false, 0, "")
+	if order.Delivery.Address != "" {
+		pdf.MultiCell(0, 5, tr(order.Delivery.Address), "", "L", false)
+	}
+	pdf.CellFormat(0, 5, fmt.Sprintf("Order #%d, %s, payment %s", order.ID, order.Status, order.PaymentMethod), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(90, 7, "Item", "B", 0, "L", true, 0, "")
+	pdf.CellFormat(20, 7, "Qty", "B", 0, "R", true, 0, "")
+	pdf.CellFormat(35, 7, "Price", "B", 0, "R", true, 0, "")
+	pdf.CellFormat(35, 7, "Amount", "B", 1, "R", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, v := range order.Items {
+		pdf.CellFormat(90, 7, tr(v.Name), "", 0, "L", false, 0, "")
+		pdf.CellFormat(20, 7, fmt.Sprintf("%d", v.Qty), "", 0, "R", false, 0, "")
+		pdf.CellFormat(35, 7, Rupiah(v.UnitPrice), "", 0, "R", false, 0, "")
+		pdf.CellFormat(35, 7, Rupiah(v.UnitPrice*float64(v.Qty)), "", 1, "R", false, 0, "")
+	}
+
+	pdf.Ln(2)
+	for _, v := range summary(order) {
+		pdf.CellFormat(145, 6, tr(v.Label), "", 0, "R", false, 0, "")
+		pdf.CellFormat(35, 6, Rupiah(v.Amount), "", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(145, 8, "Total", "T", 0, "R", false, 0, "")
+	pdf.CellFormat(35, 8, Rupiah(order.AmountPay), "T", 1, "R", false, 0, "")
+
+	if order.TaxIncluded > 0 {
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 6, "Prices marked incl. already contain "+Rupiah(order.TaxIncluded)+" tax.", "", 1, "R", false, 0, "")
+	}
+
+	return pdf.Output(w)
+}
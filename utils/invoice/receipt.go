@@ -0,0 +1,150 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"online-food/entity"
+	"strings"
+	"unicode/utf8"
+)
+
+// Width is the number of characters a 58mm printer fits on a line with its default font.
+const Width = 32
+
+var (
+	escInit       = []byte{0x1b, 0x40}
+	escLeft       = []byte{0x1b, 0x61, 0x00}
+	escBoldOn     = []byte{0x1b, 0x45, 0x01}
+	escBoldOff    = []byte{0x1b, 0x45, 0x00}
+	escFeedAndCut = []byte{0x1b, 0x64, 0x04, 0x1d, 0x56, 0x01}
+)
+
+func center(text string) string {
+	text = cut(text, Width)
+	pad := (Width - utf8.RuneCountInString(text)) / 2
+	return strings.Repeat(" ", pad) + text
+}
+
+func cut(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	return string([]rune(text)[:width])
+}
+
+// spread puts the label on the left and the amount on the right, a long label is cut to make room.
+func spread(label, value string) string {
+	room := Width - utf8.RuneCountInString(value) - 1
+	label = cut(label, room)
+	return label + strings.Repeat(" ", Width-utf8.RuneCountInString(label)-utf8.RuneCountInString(value)) + value
+}
+
+// wrap breaks text on spaces so no line is wider than the paper.
+func wrap(text string) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		word = cut(word, Width)
+		if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > Width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+type receipt struct {
+	header []string
+	body   []string
+	total  string
+	footer []string
+}
+
+func build(outlet Outlet, order *entity.Order) receipt {
+	r := receipt{}
+	r.header = append(r.header, center(outlet.Name))
+	for _, v := range wrap(outlet.Address) {
+		r.header = append(r.header, center(v))
+	}
+	if outlet.Phone != "" {
+		r.header = append(r.header, center(outlet.Phone))
+	}
+
+	line := strings.Repeat("-", Width)
+	r.body = append(r.body,
+		line,
+		title(order),
+		number(order),
+		issued(order).Format("02/01/2006 15:04"),
+		cut("Customer: "+order.User.Name, Width),
+		line,
+	)
+
+	for _, v := range order.Items {
+		r.body = append(r.body, wrap(v.Name)...)
+		r.body = append(r.body, spread(fmt.Sprintf("  %d x %s", v.Qty, Rupiah(v.UnitPrice)), Rupiah(v.UnitPrice*float64(v.Qty))))
+	}
+
+	r.body = append(r.body, line)
+	for _, v := range summary(order) {
+		r.body = append(r.body, spread(v.Label, Rupiah(v.Amount)))
+	}
+	r.body = append(r.body, line)
+
+	r.total = spread("TOTAL", Rupiah(order.AmountPay))
+	r.footer = append(r.footer,
+		spread("Payment", strings.ToUpper(order.PaymentMethod)),
+		spread("Status", strings.ToUpper(order.Status)),
+		"",
+		center("Thank you"),
+	)
+
+	return r
+}
+
+// Text renders the receipt as plain text, one printer line per text line.
+func Text(outlet Outlet, order *entity.Order) string {
+	r := build(outlet, order)
+
+	lines := append([]string{}, r.header...)
+	lines = append(lines, r.body...)
+	lines = append(lines, r.total)
+	lines = append(lines, r.footer...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// ESCPOS renders the receipt with the printer commands for a bold header and total and a paper cut at the end.
+func ESCPOS(outlet Outlet, order *entity.Order) []byte {
+	r := build(outlet, order)
+
+	var buf bytes.Buffer
+	buf.Write(escInit)
+	buf.Write(escBoldOn)
+	for _, v := range r.header {
+		buf.WriteString(v + "\n")
+	}
+	buf.Write(escBoldOff)
+	buf.Write(escLeft)
+
+	for _, v := range r.body {
+		buf.WriteString(v + "\n")
+	}
+
+	buf.Write(escBoldOn)
+	buf.WriteString(r.total + "\n")
+	buf.Write(escBoldOff)
+
+	for _, v := range r.footer {
+		buf.WriteString(v + "\n")
+	}
+
+	buf.Write(escFeedAndCut)
+	return buf.Bytes()
+}
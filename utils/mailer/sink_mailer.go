@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// memoryMailer keeps sent messages in memory, used by tests and local runs.
+type memoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *memoryMailer {
+	return &memoryMailer{}
+}
+
+func (m *memoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *memoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]Message, len(m.messages))
+	copy(result, m.messages)
+	return result
+}
+
+// fileMailer appends every message as a json line to a file.
+type fileMailer struct {
+	mu   sync.Mutex
+	Path string
+}
+
+func NewFileMailer(path string) *fileMailer {
+	return &fileMailer{
+		Path: path,
+	}
+}
+
+func (f *fileMailer) Send(ctx context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open mail file: %w", err)
+	}
+	defer file.Close()
+
+	line := struct {
+		SentAt time.Time `json:"sent_at"`
+		Message
+	}{time.Now().UTC(), msg}
+
+	if err := json.NewEncoder(file).Encode(line); err != nil {
+		return fmt.Errorf("write mail file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package promotion
+
+import (
+	"math"
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"sort"
+	"time"
+)
+
+type Line struct {
+	MenuID    uint
+	Category  string
+	UnitPrice float64
+	Qty       int
+}
+
+type Discount struct {
+	PromotionID uint
+	Name        string
+	Amount      float64
+}
+
+// Running reports whether the rule applies at the given time, including the daily hours of a happy hour.
+// The hours are wall clock hours, now has to be in the outlet's timezone.
+func Running(rule *entity.Promotion, now time.Time) bool {
+	if !rule.Active {
+		return false
+	}
+
+	if rule.StartsAt != nil && now.Before(*rule.StartsAt) {
+		return false
+	}
+
+	if rule.EndsAt != nil && !now.Before(*rule.EndsAt) {
+		return false
+	}
+
+	if rule.HourStart == "" || rule.HourEnd == "" {
+		return true
+	}
+
+	start, errStart := time.Parse("15:04", rule.HourStart)
+	end, errEnd := time.Parse("15:04", rule.HourEnd)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+
+	//a window like 22:00-02:00 runs past midnight
+	if from <= to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+func matches(rule *entity.Promotion, line Line) bool {
+	if rule.MenuID != nil && *rule.MenuID != line.MenuID {
+		return false
+	}
+
+	return rule.Category == "" || rule.Category == line.Category
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Apply prices the lines against the rules. Happy hour cuts the unit price first, buy-x-get-y then
+// gives away the cheapest units at that price, and the best spend-and-save tier runs on what is left.
+// Rules are expected in a stable order, a unit is only ever given away by one buy-x-get-y rule.
+func Apply(lines []Line, rules []*entity.Promotion, now time.Time) []Discount {
+	amounts := map[uint]float64{}
+	order := []*entity.Promotion{}
+	add := func(rule *entity.Promotion, amount float64) {
+		if amount <= 0 {
+			return
+		}
+		if _, ok := amounts[rule.ID]; !ok {
+			order = append(order, rule)
+		}
+		amounts[rule.ID] += amount
+	}
+
+	running := make([]*entity.Promotion, 0, len(rules))
+	for _, v := range rules {
+		if Running(v, now) {
+			running = append(running, v)
+		}
+	}
+
+	prices := make([]float64, len(lines))
+	for i, line := range lines {
+		prices[i] = line.UnitPrice
+
+		var best *entity.Promotion
+		for _, rule := range running {
+			if rule.Type == constanta.PromoHappyHour && matches(rule, line) && (best == nil || rule.Percent > best.Percent) {
+				best = rule
+			}
+		}
+
+		if best != nil {
+			cut := line.UnitPrice * math.Min(best.Percent, 100) / 100
+			prices[i] -= cut
+			add(best, cut*float64(line.Qty))
+		}
+	}
+
+	type unit struct {
+		line  int
+		price float64
+	}
+
+	consumed := make([]int, len(lines))
+	for _, rule := range running {
+		if rule.Type != constanta.PromoBogo || rule.BuyQty <= 0 || rule.FreeQty <= 0 {
+			continue
+		}
+
+		units := []unit{}
+		for i, line := range lines {
+			if !matches(rule, line) {
+				continue
+			}
+			for n := consumed[i]; n < line.Qty; n++ {
+				units = append(units, unit{line: i, price: prices[i]})
+			}
+		}
+
+		sort.SliceStable(units, func(a, b int) bool {
+			return units[a].price > units[b].price
+		})
+
+		group := rule.BuyQty + rule.FreeQty
+		full := len(units) / group * group
+		free := 0.0
+		for i := 0; i < full; i++ {
+			consumed[units[i].line]++
+			if i%group >= rule.BuyQty {
+				free += units[i].price
+			}
+		}
+
+		add(rule, free)
+	}
+
+	net := 0.0
+	for _, line := range lines {
+		net += line.UnitPrice * float64(line.Qty)
+	}
+	for _, v := range amounts {
+		net -= v
+	}
+
+	var tier *entity.Promotion
+	for _, rule := range running {
+		if rule.Type == constanta.PromoSpendSave && net >= rule.SpendAmount && (tier == nil || rule.SaveAmount > tier.SaveAmount) {
+			tier = rule
+		}
+	}
+
+	if tier != nil {
+		add(tier, math.Min(tier.SaveAmount, net))
+	}
+
+	discounts := make([]Discount, 0, len(order))
+	for _, rule := range order {
+		amount := round(amounts[rule.ID])
+		if amount <= 0 {
+			continue
+		}
+		discounts = append(discounts, Discount{PromotionID: rule.ID, Name: rule.Name, Amount: amount})
+	}
+
+	return discounts
+}
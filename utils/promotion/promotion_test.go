@@ -0,0 +1,97 @@
+package promotion
+
+import (
+	"online-food/entity"
+	"online-food/utils/constanta"
+	"testing"
+	"time"
+)
+
+func TestRunning(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	starts := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	ends := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		rule entity.Promotion
+		now  time.Time
+		want bool
+	}{
+		{"inactive", entity.Promotion{}, time.Date(2026, 10, 19, 12, 0, 0, 0, jakarta), false},
+		{"no window", entity.Promotion{Active: true}, time.Date(2026, 10, 19, 12, 0, 0, 0, jakarta), true},
+		{"before start date", entity.Promotion{Active: true, StartsAt: &starts}, starts.Add(-time.Second), false},
+		{"on start date", entity.Promotion{Active: true, StartsAt: &starts}, starts, true},
+		{"end date is exclusive", entity.Promotion{Active: true, EndsAt: &ends}, ends, false},
+		{"inside hours", entity.Promotion{Active: true, HourStart: "14:00", HourEnd: "17:00"}, time.Date(2026, 10, 19, 14, 0, 0, 0, jakarta), true},
+		{"end hour is exclusive", entity.Promotion{Active: true, HourStart: "14:00", HourEnd: "17:00"}, time.Date(2026, 10, 19, 17, 0, 0, 0, jakarta), false},
+		{"past midnight, late", entity.Promotion{Active: true, HourStart: "22:00", HourEnd: "02:00"}, time.Date(2026, 10, 19, 23, 30, 0, 0, jakarta), true},
+		{"past midnight, early", entity.Promotion{Active: true, HourStart: "22:00", HourEnd: "02:00"}, time.Date(2026, 10, 20, 1, 59, 0, 0, jakarta), true},
+		{"past midnight, outside", entity.Promotion{Active: true, HourStart: "22:00", HourEnd: "02:00"}, time.Date(2026, 10, 20, 2, 0, 0, 0, jakarta), false},
+		{"bad hours", entity.Promotion{Active: true, HourStart: "2pm", HourEnd: "17:00"}, time.Date(2026, 10, 19, 15, 0, 0, 0, jakarta), false},
+		//15:00 in Jakarta is 08:00 UTC, the hours are read off the clock of the time given
+		{"outlet clock", entity.Promotion{Active: true, HourStart: "14:00", HourEnd: "17:00"}, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC).In(jakarta), true},
+		{"server clock", entity.Promotion{Active: true, HourStart: "14:00", HourEnd: "17:00"}, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Running(&tt.rule, tt.now); got != tt.want {
+				t.Fatalf("Running = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2026, 10, 19, 15, 0, 0, 0, time.UTC)
+	happy := &entity.Promotion{ID: 1, Name: "Happy hour", Type: constanta.PromoHappyHour, Category: "drink", Percent: 50, HourStart: "14:00", HourEnd: "17:00", Active: true}
+	bogo := &entity.Promotion{ID: 2, Name: "Buy 2 get 1", Type: constanta.PromoBogo, Category: "food", BuyQty: 2, FreeQty: 1, Active: true}
+	spend := &entity.Promotion{ID: 3, Name: "Spend 100k save 10k", Type: constanta.PromoSpendSave, SpendAmount: 100000, SaveAmount: 10000, Active: true}
+
+	tests := []struct {
+		name  string
+		lines []Line
+		rules []*entity.Promotion
+		want  []Discount
+	}{
+		{
+			name:  "happy hour on matching lines",
+			lines: []Line{{MenuID: 1, Category: "drink", UnitPrice: 10000, Qty: 2}, {MenuID: 2, Category: "food", UnitPrice: 20000, Qty: 1}},
+			rules: []*entity.Promotion{happy},
+			want:  []Discount{{PromotionID: 1, Name: "Happy hour", Amount: 10000}},
+		},
+		{
+			name:  "cheapest units go free",
+			lines: []Line{{MenuID: 1, Category: "food", UnitPrice: 30000, Qty: 2}, {MenuID: 2, Category: "food", UnitPrice: 20000, Qty: 1}},
+			rules: []*entity.Promotion{bogo},
+			want:  []Discount{{PromotionID: 2, Name: "Buy 2 get 1", Amount: 20000}},
+		},
+		{
+			name:  "spend tier runs on what is left",
+			lines: []Line{{MenuID: 1, Category: "food", UnitPrice: 40000, Qty: 3}},
+			rules: []*entity.Promotion{bogo, spend},
+			want:  []Discount{{PromotionID: 2, Name: "Buy 2 get 1", Amount: 40000}},
+		},
+		{
+			name:  "spend tier reached",
+			lines: []Line{{MenuID: 1, Category: "food", UnitPrice: 40000, Qty: 4}},
+			rules: []*entity.Promotion{bogo, spend},
+			want:  []Discount{{PromotionID: 2, Name: "Buy 2 get 1", Amount: 40000}, {PromotionID: 3, Name: "Spend 100k save 10k", Amount: 10000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Apply(tt.lines, tt.rules, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("discounts = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("discounts = %+v, want %+v", got, tt.want)
+				}
+			}
+		})
+	}
+}
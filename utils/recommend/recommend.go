@@ -0,0 +1,194 @@
+// Package recommend turns checked-out baskets into "bought together" pairs and per-user picks. It keeps no
+// state, the caller loads the baskets and stores the result, so the same baskets always give the same result.
+//
+//   - two menus are related by cosine similarity: baskets holding both / sqrt(baskets with one * baskets with the other)
+//   - a pair has to show up in MinSupport baskets, each menu keeps its PerMenu best pairs
+//   - a user's pick scores the similarity to every menu they bought, times how often they bought it,
+//     menus they already bought are left out
+//
+// higher scores go first, equal scores go to the lower id.
+package recommend
+
+import (
+	"math"
+	"sort"
+)
+
+// Line is one menu of a checked-out basket.
+type Line struct {
+	UserID uint
+	CartID uint
+	MenuID uint
+	Qty    int
+}
+
+type Pair struct {
+	MenuID    uint
+	RelatedID uint
+	Count     int
+	Score     float64
+}
+
+type Affinity struct {
+	UserID uint
+	MenuID uint
+	Score  float64
+}
+
+type Popularity struct {
+	MenuID uint
+	Orders int
+	Qty    int
+}
+
+type Options struct {
+	MinSupport int
+	PerMenu    int
+	PerUser    int
+}
+
+var DefaultOptions = Options{
+	MinSupport: 2,
+	PerMenu:    10,
+	PerUser:    20,
+}
+
+type Result struct {
+	Pairs   []Pair
+	Users   []Affinity
+	Popular []Popularity
+}
+
+func round(score float64) float64 {
+	return math.Round(score*10000) / 10000
+}
+
+// Compute builds everything from the baskets.
+func Compute(lines []Line, options Options) Result {
+	baskets := map[uint]map[uint]bool{}
+	owner := map[uint]uint{}
+	popular := map[uint]*Popularity{}
+	for _, v := range lines {
+		if baskets[v.CartID] == nil {
+			baskets[v.CartID] = map[uint]bool{}
+		}
+		owner[v.CartID] = v.UserID
+
+		if popular[v.MenuID] == nil {
+			popular[v.MenuID] = &Popularity{MenuID: v.MenuID}
+		}
+		if !baskets[v.CartID][v.MenuID] {
+			popular[v.MenuID].Orders++
+		}
+		popular[v.MenuID].Qty += v.Qty
+		baskets[v.CartID][v.MenuID] = true
+	}
+
+	together := map[[2]uint]int{}
+	bought := map[uint]map[uint]int{}
+	for cartID, menus := range baskets {
+		user := owner[cartID]
+		if bought[user] == nil {
+			bought[user] = map[uint]int{}
+		}
+
+		for a := range menus {
+			bought[user][a]++
+			for b := range menus {
+				if a != b {
+					together[[2]uint{a, b}]++
+				}
+			}
+		}
+	}
+
+	related := map[uint][]Pair{}
+	for key, count := range together {
+		if count < options.MinSupport {
+			continue
+		}
+
+		score := float64(count) / math.Sqrt(float64(popular[key[0]].Orders*popular[key[1]].Orders))
+		related[key[0]] = append(related[key[0]], Pair{MenuID: key[0], RelatedID: key[1], Count: count, Score: round(score)})
+	}
+
+	result := Result{Pairs: []Pair{}, Users: []Affinity{}, Popular: []Popularity{}}
+	for menuID, pairs := range related {
+		sort.Slice(pairs, func(a, b int) bool {
+			if pairs[a].Score != pairs[b].Score {
+				return pairs[a].Score > pairs[b].Score
+			}
+			return pairs[a].RelatedID < pairs[b].RelatedID
+		})
+
+		if len(pairs) > options.PerMenu {
+			pairs = pairs[:options.PerMenu]
+		}
+		related[menuID] = pairs
+		result.Pairs = append(result.Pairs, pairs...)
+	}
+
+	sort.Slice(result.Pairs, func(a, b int) bool {
+		if result.Pairs[a].MenuID != result.Pairs[b].MenuID {
+			return result.Pairs[a].MenuID < result.Pairs[b].MenuID
+		}
+		if result.Pairs[a].Score != result.Pairs[b].Score {
+			return result.Pairs[a].Score > result.Pairs[b].Score
+		}
+		return result.Pairs[a].RelatedID < result.Pairs[b].RelatedID
+	})
+
+	for userID, menus := range bought {
+		scores := map[uint]float64{}
+		for menuID, times := range menus {
+			for _, v := range related[menuID] {
+				if menus[v.RelatedID] == 0 {
+					scores[v.RelatedID] += float64(times) * v.Score
+				}
+			}
+		}
+
+		picks := make([]Affinity, 0, len(scores))
+		for menuID, score := range scores {
+			picks = append(picks, Affinity{UserID: userID, MenuID: menuID, Score: round(score)})
+		}
+
+		sort.Slice(picks, func(a, b int) bool {
+			if picks[a].Score != picks[b].Score {
+				return picks[a].Score > picks[b].Score
+			}
+			return picks[a].MenuID < picks[b].MenuID
+		})
+
+		if len(picks) > options.PerUser {
+			picks = picks[:options.PerUser]
+		}
+		result.Users = append(result.Users, picks...)
+	}
+
+	sort.Slice(result.Users, func(a, b int) bool {
+		if result.Users[a].UserID != result.Users[b].UserID {
+			return result.Users[a].UserID < result.Users[b].UserID
+		}
+		if result.Users[a].Score != result.Users[b].Score {
+			return result.Users[a].Score > result.Users[b].Score
+		}
+		return result.Users[a].MenuID < result.Users[b].MenuID
+	})
+
+	for _, v := range popular {
+		result.Popular = append(result.Popular, *v)
+	}
+
+	sort.Slice(result.Popular, func(a, b int) bool {
+		if result.Popular[a].Orders != result.Popular[b].Orders {
+			return result.Popular[a].Orders > result.Popular[b].Orders
+		}
+		if result.Popular[a].Qty != result.Popular[b].Qty {
+			return result.Popular[a].Qty > result.Popular[b].Qty
+		}
+		return result.Popular[a].MenuID < result.Popular[b].MenuID
+	})
+
+	return result
+}
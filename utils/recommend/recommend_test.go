@@ -0,0 +1,103 @@
+package recommend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	//menu 1 is in every basket, menu 2 goes with it twice, menu 3 once
+	lines := []Line{
+		{UserID: 1, CartID: 1, MenuID: 1, Qty: 1}, {UserID: 1, CartID: 1, MenuID: 2, Qty: 1},
+		{UserID: 2, CartID: 2, MenuID: 1, Qty: 2}, {UserID: 2, CartID: 2, MenuID: 2, Qty: 1},
+		{UserID: 2, CartID: 3, MenuID: 1, Qty: 1}, {UserID: 2, CartID: 3, MenuID: 3, Qty: 1},
+		{UserID: 3, CartID: 4, MenuID: 1, Qty: 1}, {UserID: 3, CartID: 4, MenuID: 1, Qty: 2},
+	}
+
+	tests := []struct {
+		name      string
+		options   Options
+		wantPairs []Pair
+		wantUsers []Affinity
+	}{
+		{
+			name:      "pairs below the support are dropped",
+			options:   Options{MinSupport: 2, PerMenu: 10, PerUser: 20},
+			wantPairs: []Pair{{1, 2, 2, 0.7071}, {2, 1, 2, 0.7071}},
+			wantUsers: []Affinity{{3, 2, 0.7071}},
+		},
+		{
+			name:      "cosine ranks the pairs, bought menus are left out",
+			options:   Options{MinSupport: 1, PerMenu: 10, PerUser: 20},
+			wantPairs: []Pair{{1, 2, 2, 0.7071}, {1, 3, 1, 0.5}, {2, 1, 2, 0.7071}, {3, 1, 1, 0.5}},
+			wantUsers: []Affinity{{1, 3, 0.5}, {3, 2, 0.7071}, {3, 3, 0.5}},
+		},
+		{
+			name:      "each menu keeps its best pairs",
+			options:   Options{MinSupport: 1, PerMenu: 1, PerUser: 20},
+			wantPairs: []Pair{{1, 2, 2, 0.7071}, {2, 1, 2, 0.7071}, {3, 1, 1, 0.5}},
+			wantUsers: []Affinity{{3, 2, 0.7071}},
+		},
+		{
+			name:      "each user keeps their best picks",
+			options:   Options{MinSupport: 1, PerMenu: 10, PerUser: 1},
+			wantPairs: []Pair{{1, 2, 2, 0.7071}, {1, 3, 1, 0.5}, {2, 1, 2, 0.7071}, {3, 1, 1, 0.5}},
+			wantUsers: []Affinity{{1, 3, 0.5}, {3, 2, 0.7071}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Compute(lines, tt.options)
+
+			if !reflect.DeepEqual(result.Pairs, tt.wantPairs) {
+				t.Fatalf("pairs = %+v, want %+v", result.Pairs, tt.wantPairs)
+			}
+
+			if !reflect.DeepEqual(result.Users, tt.wantUsers) {
+				t.Fatalf("users = %+v, want %+v", result.Users, tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestComputeScoresRepeatBuyers(t *testing.T) {
+	//user 1 bought menu 1 twice, so what goes with it weighs double against menu 2
+	lines := []Line{
+		{UserID: 1, CartID: 1, MenuID: 1, Qty: 1},
+		{UserID: 1, CartID: 2, MenuID: 1, Qty: 1},
+		{UserID: 1, CartID: 3, MenuID: 2, Qty: 1},
+		{UserID: 2, CartID: 4, MenuID: 1, Qty: 1}, {UserID: 2, CartID: 4, MenuID: 3, Qty: 1},
+		{UserID: 2, CartID: 5, MenuID: 2, Qty: 1}, {UserID: 2, CartID: 5, MenuID: 4, Qty: 1},
+	}
+
+	result := Compute(lines, Options{MinSupport: 1, PerMenu: 10, PerUser: 20})
+
+	var picks []Affinity
+	for _, v := range result.Users {
+		if v.UserID == 1 {
+			picks = append(picks, v)
+		}
+	}
+
+	//2 times the pair 1-3 at 1/sqrt(3*1) = 0.5774, once the pair 2-4 at 1/sqrt(2*1)
+	want := []Affinity{{1, 3, 1.1548}, {1, 4, 0.7071}}
+	if !reflect.DeepEqual(picks, want) {
+		t.Fatalf("picks = %+v, want %+v", picks, want)
+	}
+}
+
+func TestComputePopularity(t *testing.T) {
+	lines := []Line{
+		{UserID: 1, CartID: 1, MenuID: 2, Qty: 5},
+		{UserID: 1, CartID: 2, MenuID: 1, Qty: 1}, {UserID: 1, CartID: 2, MenuID: 1, Qty: 1},
+		{UserID: 2, CartID: 3, MenuID: 1, Qty: 1},
+		{UserID: 2, CartID: 3, MenuID: 3, Qty: 5},
+	}
+
+	//a menu twice in one basket is one order, ties on orders go to qty then the lower id
+	want := []Popularity{{1, 2, 3}, {2, 1, 5}, {3, 1, 5}}
+	if got := Compute(lines, DefaultOptions).Popular; !reflect.DeepEqual(got, want) {
+		t.Fatalf("popular = %+v, want %+v", got, want)
+	}
+}
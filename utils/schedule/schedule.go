@@ -0,0 +1,169 @@
+// Package schedule cuts the opening hours into delivery slots for pre-orders.
+//
+// Rules:
+//   - hours are per weekday in the outlet's time zone, a day without hours is closed, no hours at all is open all day
+//   - a window is "HH:MM" to "HH:MM" on the same day, "24:00" closes at midnight
+//   - slots start at the opening time and every SlotMinutes after, a slot has to end before the window closes
+//   - a slot can be booked from LeadMinutes before it starts, the kitchen gets the order at that moment
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	//the outlet time zone has to load on hosts without a zoneinfo database
+	_ "time/tzdata"
+)
+
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+type Window struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+type Config struct {
+	Hours       map[string][]Window `json:"hours"`
+	SlotMinutes int                 `json:"slot_minutes"`
+	Capacity    int                 `json:"capacity"`
+	LeadMinutes int                 `json:"lead_minutes"`
+	MaxDays     int                 `json:"max_days"`
+}
+
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Default is an outlet open all day, half hour slots without a limit, released 45 minutes ahead, a week ahead.
+func Default() Config {
+	return Config{
+		Hours:       map[string][]Window{},
+		SlotMinutes: 30,
+		Capacity:    0,
+		LeadMinutes: 45,
+		MaxDays:     7,
+	}
+}
+
+func minutes(clock string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err != nil || len(clock) != 5 {
+		return 0, fmt.Errorf("%q is not HH:MM", clock)
+	}
+
+	if hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("%q is not a time of day", clock)
+	}
+
+	return hour*60 + minute, nil
+}
+
+func (c Config) Validate() error {
+	if c.SlotMinutes < 5 || c.SlotMinutes > 24*60 {
+		return errors.New("slot_minutes must be between 5 and 1440")
+	}
+
+	if c.Capacity < 0 || c.LeadMinutes < 0 || c.MaxDays < 1 {
+		return errors.New("capacity and lead_minutes can't be negative, max_days must be at least 1")
+	}
+
+	for day, windows := range c.Hours {
+		known := false
+		for _, v := range weekdays {
+			known = known || v == day
+		}
+
+		if !known {
+			return fmt.Errorf("%q is not a weekday", day)
+		}
+
+		for _, w := range windows {
+			open, err := minutes(w.Open)
+			if err != nil {
+				return err
+			}
+
+			closing, err := minutes(w.Close)
+			if err != nil {
+				return err
+			}
+
+			if open >= closing {
+				return fmt.Errorf("%s %s-%s closes before it opens", day, w.Open, w.Close)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c Config) windows(day time.Weekday) []Window {
+	if len(c.Hours) == 0 {
+		return []Window{{Open: "00:00", Close: "24:00"}}
+	}
+	return c.Hours[weekdays[day]]
+}
+
+// Open reports whether the outlet is open at the moment, at is read in its own location.
+func (c Config) Open(at time.Time) bool {
+	minute := at.Hour()*60 + at.Minute()
+	for _, w := range c.windows(at.Weekday()) {
+		open, errOpen := minutes(w.Open)
+		closing, errClose := minutes(w.Close)
+		if errOpen == nil && errClose == nil && minute >= open && minute < closing {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Slots are the slots of the calendar day of date in loc, in order.
+func (c Config) Slots(date time.Time, loc *time.Location) []Slot {
+	date = date.In(loc)
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	length := time.Duration(c.SlotMinutes) * time.Minute
+
+	slots := []Slot{}
+	for _, w := range c.windows(date.Weekday()) {
+		open, errOpen := minutes(w.Open)
+		closing, errClose := minutes(w.Close)
+		if errOpen != nil || errClose != nil || c.SlotMinutes <= 0 {
+			continue
+		}
+
+		//built from the wall clock so a daylight saving day still starts slots on the hour
+		for start := open; start+c.SlotMinutes <= closing; start += c.SlotMinutes {
+			begin := time.Date(midnight.Year(), midnight.Month(), midnight.Day(), start/60, start%60, 0, 0, loc)
+			slots = append(slots, Slot{Start: begin, End: begin.Add(length)})
+		}
+	}
+
+	return slots
+}
+
+// Find is the slot starting at start, false when no slot starts then.
+func (c Config) Find(start time.Time, loc *time.Location) (Slot, bool) {
+	for _, v := range c.Slots(start, loc) {
+		if v.Start.Equal(start) {
+			return v, true
+		}
+	}
+
+	return Slot{}, false
+}
+
+// Bookable reports whether a slot may still be booked at now: far enough ahead for the kitchen and not
+// further than MaxDays.
+func (c Config) Bookable(slot Slot, now time.Time) bool {
+	lead := time.Duration(c.LeadMinutes) * time.Minute
+	return !slot.Start.Before(now.Add(lead)) && !slot.Start.After(now.AddDate(0, 0, c.MaxDays))
+}
+
+// Day parses a YYYY-MM-DD date in loc.
+func Day(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", strings.TrimSpace(value), loc)
+}
@@ -1,31 +1,73 @@
 package token
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"online-food/dto"
-	"os"
+	"online-food/utils/constanta"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateToken(userId uint, username, email, role string, exp time.Duration) (string, error) {
-	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
-	jwtExp := time.Now().Add(exp * time.Hour)
+var ErrTokenType = errors.New("invalid token type")
+
+func generateID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func GenerateToken(userId uint, username, email, role string, session uint, tokenType string, exp time.Duration) (string, error) {
+	set, err := currentKeys()
+	if err != nil {
+		return "", err
+	}
+
+	jti, err := generateID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+
+	now := time.Now()
+	jwtExp := now.Add(exp * time.Hour)
 
 	tokenCLaim := &dto.TokenClaim{
-		UserID:   userId,
-		Username: username,
-		Email:    email,
-		Role:     role,
+		UserID:    userId,
+		Username:  username,
+		Email:     email,
+		Role:      role,
+		TokenType: tokenType,
+		Session:   session,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    set.issuer,
+			Subject:   strconv.FormatUint(uint64(userId), 10),
+			Audience:  jwt.ClaimStrings{set.audience},
 			ExpiresAt: jwt.NewNumericDate(jwtExp),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        jti,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenCLaim)
-	tokenStr, err := token.SignedString(jwtSecret)
+	var tokenStr string
+	switch {
+	case tokenType == constanta.RefreshToken:
+		tokenStr, err = jwt.NewWithClaims(jwt.SigningMethodHS256, tokenCLaim).SignedString(set.refreshSecret)
+	case tokenType == constanta.AccessToken && set.active == nil:
+		tokenStr, err = jwt.NewWithClaims(jwt.SigningMethodHS256, tokenCLaim).SignedString(set.accessSecret)
+	case tokenType == constanta.AccessToken:
+		token := jwt.NewWithClaims(set.active.Method, tokenCLaim)
+		token.Header["kid"] = set.active.Kid
+		tokenStr, err = token.SignedString(set.active.Private)
+	default:
+		return "", ErrTokenType
+	}
+
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -33,13 +75,34 @@ func GenerateToken(userId uint, username, email, role string, exp time.Duration)
 	return tokenStr, nil
 }
 
-func ClaimTokenRefresh(tokenUser string) (*dto.TokenClaim, error) {
-	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
+func parseToken(tokenUser, tokenType string) (*dto.TokenClaim, error) {
+	set, err := currentKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	var keyFunc jwt.Keyfunc
+	var methods []string
+	switch tokenType {
+	case constanta.AccessToken:
+		keyFunc = set.accessKeyFunc
+		methods = set.accessMethods()
+	case constanta.RefreshToken:
+		keyFunc = set.refreshKeyFunc
+		methods = []string{jwt.SigningMethodHS256.Alg()}
+	default:
+		return nil, ErrTokenType
+	}
+
 	claim := &dto.TokenClaim{}
 
-	token, err := jwt.ParseWithClaims(tokenUser, claim, func(t *jwt.Token) (any, error) {
-		return jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenUser, claim, keyFunc,
+		jwt.WithValidMethods(methods),
+		jwt.WithIssuer(set.issuer),
+		jwt.WithAudience(set.audience),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, err
@@ -47,8 +110,19 @@ func ClaimTokenRefresh(tokenUser string) (*dto.TokenClaim, error) {
 
 	if !token.Valid {
 		return nil, errors.New("invalid token")
+	}
 
+	if claim.TokenType != tokenType {
+		return nil, ErrTokenType
 	}
 
 	return claim, nil
 }
+
+func ClaimTokenAccess(tokenUser string) (*dto.TokenClaim, error) {
+	return parseToken(tokenUser, constanta.AccessToken)
+}
+
+func ClaimTokenRefresh(tokenUser string) (*dto.TokenClaim, error) {
+	return parseToken(tokenUser, constanta.RefreshToken)
+}
@@ -0,0 +1,204 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"online-food/utils/constanta"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// loadTestKeys loads the keys from env, keyList is JWT_KEYS and stays empty for the HS256 fallback.
+func loadTestKeys(t *testing.T, secrets [3]string, keyList, activeKid string) {
+	t.Helper()
+
+	t.Setenv("JWT_SECRET", secrets[0])
+	t.Setenv("JWT_REFRESH_SECRET", secrets[1])
+	t.Setenv("EMAIL_VERIFY_SECRET", secrets[2])
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("JWT_AUDIENCE", "")
+	t.Setenv("JWT_KEYS", keyList)
+	t.Setenv("JWT_ACTIVE_KID", activeKid)
+
+	if err := LoadKeys(); err != nil {
+		t.Fatalf("load keys: %v", err)
+	}
+	t.Cleanup(func() { keys = nil })
+}
+
+// writeRSAKey writes a fresh RSA key as PEM and returns its path and the key.
+func writeRSAKey(t *testing.T, name string) (string, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), name+".pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	return path, key
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return signed
+}
+
+func accessClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id":    1,
+		"token_type": constanta.AccessToken,
+		"iss":        "online-food",
+		"aud":        "online-food-api",
+		"iat":        now.Unix(),
+		"exp":        now.Add(time.Hour).Unix(),
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestTokenTypesAreSeparate(t *testing.T) {
+	tests := []struct {
+		name    string
+		secrets [3]string
+	}{
+		{"different secrets", [3]string{"access-secret", "refresh-secret", "verify-secret"}},
+		//the token type still tells them apart when every secret is the same
+		{"shared secret", [3]string{"same-secret", "same-secret", "same-secret"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTestKeys(t, tt.secrets, "", "")
+
+			access, err := GenerateToken(1, "budi", "budi@mail.com", constanta.Customer, 0, constanta.AccessToken, 1)
+			if err != nil {
+				t.Fatalf("access: %v", err)
+			}
+
+			refresh, err := GenerateToken(1, "budi", "budi@mail.com", constanta.Customer, 0, constanta.RefreshToken, 1)
+			if err != nil {
+				t.Fatalf("refresh: %v", err)
+			}
+
+			purpose, err := GeneratePurposeToken(1, "budi@mail.com", constanta.LoginChallengePurpose, time.Minute)
+			if err != nil {
+				t.Fatalf("purpose: %v", err)
+			}
+
+			checks := []struct {
+				name   string
+				claim  func() error
+				wantOK bool
+			}{
+				{"access as access", func() error { _, err := ClaimTokenAccess(access); return err }, true},
+				{"refresh as refresh", func() error { _, err := ClaimTokenRefresh(refresh); return err }, true},
+				{"purpose as its purpose", func() error { _, err := ClaimPurposeToken(purpose, constanta.LoginChallengePurpose); return err }, true},
+				{"refresh as access", func() error { _, err := ClaimTokenAccess(refresh); return err }, false},
+				{"access as refresh", func() error { _, err := ClaimTokenRefresh(access); return err }, false},
+				{"purpose as access", func() error { _, err := ClaimTokenAccess(purpose); return err }, false},
+				{"purpose as refresh", func() error { _, err := ClaimTokenRefresh(purpose); return err }, false},
+				{"purpose as another purpose", func() error { _, err := ClaimPurposeToken(purpose, constanta.VerifyEmailPurpose); return err }, false},
+				{"access as purpose", func() error { _, err := ClaimPurposeToken(access, constanta.LoginChallengePurpose); return err }, false},
+			}
+
+			for _, c := range checks {
+				if err := c.claim(); (err == nil) != c.wantOK {
+					t.Errorf("%s: err = %v, want accepted %v", c.name, err, c.wantOK)
+				}
+			}
+		})
+	}
+}
+
+func TestClaimTokenAccessRejectsForgedTokens(t *testing.T) {
+	path, key := writeRSAKey(t, "a")
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})
+
+	secret := []byte("access-secret")
+	tests := []struct {
+		name   string
+		rsa    bool
+		token  func(t *testing.T) string
+		wantOK bool
+	}{
+		{"valid hs256", false, func(t *testing.T) string { return sign(t, jwt.SigningMethodHS256, secret, "", accessClaims(nil)) }, true},
+		{"wrong issuer", false, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodHS256, secret, "", accessClaims(jwt.MapClaims{"iss": "someone-else"}))
+		}, false},
+		{"wrong audience", false, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodHS256, secret, "", accessClaims(jwt.MapClaims{"aud": "another-api"}))
+		}, false},
+		{"missing exp", false, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodHS256, secret, "", accessClaims(jwt.MapClaims{"exp": nil}))
+		}, false},
+		{"expired", false, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodHS256, secret, "", accessClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}))
+		}, false},
+		{"issued in the future", false, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodHS256, secret, "", accessClaims(jwt.MapClaims{"iat": time.Now().Add(time.Hour).Unix()}))
+		}, false},
+		{"missing token type", false, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodHS256, secret, "", accessClaims(jwt.MapClaims{"token_type": nil}))
+		}, false},
+		{"alg none", false, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", accessClaims(nil))
+		}, false},
+		{"valid rs256", true, func(t *testing.T) string { return sign(t, jwt.SigningMethodRS256, key, "a", accessClaims(nil)) }, true},
+		{"hs256 signed with the public key", true, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodHS256, publicPEM, "a", accessClaims(nil))
+		}, false},
+		{"hs256 signed with the old secret", true, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodHS256, secret, "a", accessClaims(nil))
+		}, false},
+		{"alg none with a kid", true, func(t *testing.T) string {
+			return sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "a", accessClaims(nil))
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.rsa {
+				loadTestKeys(t, [3]string{string(secret), "refresh-secret", "verify-secret"}, "a="+path, "a")
+			} else {
+				loadTestKeys(t, [3]string{string(secret), "refresh-secret", "verify-secret"}, "", "")
+			}
+
+			if _, err := ClaimTokenAccess(tt.token(t)); (err == nil) != tt.wantOK {
+				t.Fatalf("err = %v, want accepted %v", err, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"online-food/dto"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrKeysNotLoaded = errors.New("token keys not loaded")
+
+type signingKey struct {
+	Kid     string
+	Method  jwt.SigningMethod
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+}
+
+type keySet struct {
+	active        *signingKey
+	keys          map[string]*signingKey
+	ordered       []*signingKey
+	accessSecret  []byte
+	refreshSecret []byte
+	verifySecret  []byte
+	issuer        string
+	audience      string
+}
+
+var keys *keySet
+
+// LoadKeys reads the signing keys once at startup.
+// JWT_KEYS is a comma separated list of kid=path pairs, JWT_ACTIVE_KID picks the key used to sign new access tokens.
+// Keys that are not active stay in the set so tokens signed before a rotation can still be verified.
+// Without JWT_KEYS access tokens fall back to HS256 with JWT_SECRET.
+func LoadKeys() error {
+	set := &keySet{
+		keys:          map[string]*signingKey{},
+		accessSecret:  []byte(os.Getenv("JWT_SECRET")),
+		refreshSecret: []byte(os.Getenv("JWT_REFRESH_SECRET")),
+		verifySecret:  []byte(os.Getenv("EMAIL_VERIFY_SECRET")),
+		issuer:        os.Getenv("JWT_ISSUER"),
+		audience:      os.Getenv("JWT_AUDIENCE"),
+	}
+
+	if set.issuer == "" {
+		set.issuer = "online-food"
+	}
+
+	if set.audience == "" {
+		set.audience = "online-food-api"
+	}
+
+	if len(set.refreshSecret) == 0 {
+		return errors.New("JWT_REFRESH_SECRET is empty")
+	}
+
+	if len(set.verifySecret) == 0 {
+		return errors.New("EMAIL_VERIFY_SECRET is empty")
+	}
+
+	keyList := strings.TrimSpace(os.Getenv("JWT_KEYS"))
+	if keyList == "" {
+		if len(set.accessSecret) == 0 {
+			return errors.New("JWT_SECRET is empty")
+		}
+		keys = set
+		return nil
+	}
+
+	for _, pair := range strings.Split(keyList, ",") {
+		kid, path, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || kid == "" || path == "" {
+			return fmt.Errorf("invalid JWT_KEYS entry: %q", pair)
+		}
+
+		if _, exist := set.keys[kid]; exist {
+			return fmt.Errorf("duplicate key id: %s", kid)
+		}
+
+		key, err := loadKeyFile(kid, path)
+		if err != nil {
+			return err
+		}
+
+		set.keys[kid] = key
+		set.ordered = append(set.ordered, key)
+	}
+
+	activeKid := os.Getenv("JWT_ACTIVE_KID")
+	active, ok := set.keys[activeKid]
+	if !ok {
+		return fmt.Errorf("active key id %q not found in JWT_KEYS", activeKid)
+	}
+
+	if active.Private == nil {
+		return fmt.Errorf("active key %q has no private key", activeKid)
+	}
+
+	set.active = active
+	keys = set
+	return nil
+}
+
+func loadKeyFile(kid, path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", kid, err)
+	}
+
+	if private, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return &signingKey{Kid: kid, Method: jwt.SigningMethodRS256, Private: private, Public: &private.PublicKey}, nil
+	}
+
+	if private, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+		edKey, ok := private.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("key %s: unsupported private key type", kid)
+		}
+		return &signingKey{Kid: kid, Method: jwt.SigningMethodEdDSA, Private: edKey, Public: edKey.Public()}, nil
+	}
+
+	if public, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return &signingKey{Kid: kid, Method: jwt.SigningMethodRS256, Public: public}, nil
+	}
+
+	if public, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+		return &signingKey{Kid: kid, Method: jwt.SigningMethodEdDSA, Public: public}, nil
+	}
+
+	return nil, fmt.Errorf("key %s: unsupported key format, expected RSA or Ed25519 PEM", kid)
+}
+
+func currentKeys() (*keySet, error) {
+	if keys == nil {
+		return nil, ErrKeysNotLoaded
+	}
+	return keys, nil
+}
+
+func (k *keySet) accessMethods() []string {
+	if k.active == nil {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+
+	methods := []string{}
+	seen := map[string]bool{}
+	for _, v := range k.ordered {
+		alg := v.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+func (k *keySet) accessKeyFunc(t *jwt.Token) (any, error) {
+	if k.active == nil {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return k.accessSecret, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	}
+
+	return key.Public, nil
+}
+
+func (k *keySet) refreshKeyFunc(t *jwt.Token) (any, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	}
+	return k.refreshSecret, nil
+}
+
+func JWKS() (*dto.JWKSResponse, error) {
+	set, err := currentKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.JWKSResponse{Keys: make([]dto.JSONWebKey, 0, len(set.ordered))}
+	for _, v := range set.ordered {
+		jwk := dto.JSONWebKey{
+			Kid: v.Kid,
+			Use: "sig",
+			Alg: v.Method.Alg(),
+		}
+
+		switch pub := v.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		response.Keys = append(response.Keys, jwk)
+	}
+
+	return response, nil
+}
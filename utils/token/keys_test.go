@@ -0,0 +1,191 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"online-food/utils/constanta"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecrets = [3]string{"access-secret", "refresh-secret", "verify-secret"}
+
+func writeEdKey(t *testing.T, name string) (string, ed25519.PrivateKey) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), name+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	return path, key
+}
+
+func kidOf(t *testing.T, tokenStr string) string {
+	t.Helper()
+
+	token, _, err := jwt.NewParser().ParseUnverified(tokenStr, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("parse header: %v", err)
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyRotation(t *testing.T) {
+	pathA, _ := writeRSAKey(t, "a")
+	pathB, _ := writeEdKey(t, "b")
+	_, keyC := writeRSAKey(t, "c")
+
+	loadTestKeys(t, testSecrets, "A="+pathA, "A")
+	signedA, err := GenerateToken(1, "budi", "budi@mail.com", constanta.Customer, 0, constanta.AccessToken, 1)
+	if err != nil {
+		t.Fatalf("sign with A: %v", err)
+	}
+
+	if kid := kidOf(t, signedA); kid != "A" {
+		t.Fatalf("kid = %q, want A", kid)
+	}
+
+	//B becomes active, A stays listed until its tokens expire
+	loadTestKeys(t, testSecrets, "A="+pathA+",B="+pathB, "B")
+	signedB, err := GenerateToken(1, "budi", "budi@mail.com", constanta.Customer, 0, constanta.AccessToken, 1)
+	if err != nil {
+		t.Fatalf("sign with B: %v", err)
+	}
+
+	if kid := kidOf(t, signedB); kid != "B" {
+		t.Fatalf("kid = %q, want B", kid)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		wantOK bool
+	}{
+		{"signed with the retired key", signedA, true},
+		{"signed with the active key", signedB, true},
+		{"unknown kid", sign(t, jwt.SigningMethodRS256, keyC, "C", accessClaims(nil)), false},
+		{"known kid, other key", sign(t, jwt.SigningMethodRS256, keyC, "A", accessClaims(nil)), false},
+		{"kid of an ed25519 key with rs256", sign(t, jwt.SigningMethodRS256, keyC, "B", accessClaims(nil)), false},
+		{"no kid", sign(t, jwt.SigningMethodRS256, keyC, "", accessClaims(nil)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ClaimTokenAccess(tt.token); (err == nil) != tt.wantOK {
+				t.Fatalf("err = %v, want accepted %v", err, tt.wantOK)
+			}
+		})
+	}
+
+	//once A is dropped from the list its tokens are refused
+	loadTestKeys(t, testSecrets, "B="+pathB, "B")
+	if _, err := ClaimTokenAccess(signedA); err == nil {
+		t.Fatal("token signed with a removed key was accepted")
+	}
+}
+
+func TestLoadKeysRejects(t *testing.T) {
+	pathA, keyA := writeRSAKey(t, "a")
+
+	public, err := x509.MarshalPKIXPublicKey(&keyA.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	publicPath := filepath.Join(t.TempDir(), "public.pem")
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		keyList   string
+		activeKid string
+	}{
+		{"active kid not listed", "A=" + pathA, "B"},
+		{"active key without private part", "A=" + publicPath, "A"},
+		{"duplicate kid", "A=" + pathA + ",A=" + pathA, "A"},
+		{"entry without path", "A", "A"},
+		{"missing file", "A=" + filepath.Join(t.TempDir(), "missing.pem"), "A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_SECRET", testSecrets[0])
+			t.Setenv("JWT_REFRESH_SECRET", testSecrets[1])
+			t.Setenv("EMAIL_VERIFY_SECRET", testSecrets[2])
+			t.Setenv("JWT_KEYS", tt.keyList)
+			t.Setenv("JWT_ACTIVE_KID", tt.activeKid)
+			t.Cleanup(func() { keys = nil })
+
+			if err := LoadKeys(); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	pathA, keyA := writeRSAKey(t, "a")
+	pathB, keyB := writeEdKey(t, "b")
+
+	loadTestKeys(t, testSecrets, "A="+pathA+",B="+pathB, "B")
+
+	result, err := JWKS()
+	if err != nil {
+		t.Fatalf("jwks: %v", err)
+	}
+
+	if len(result.Keys) != 2 {
+		t.Fatalf("keys = %+v, want A and B", result.Keys)
+	}
+
+	a, b := result.Keys[0], result.Keys[1]
+	if a.Kid != "A" || a.Kty != "RSA" || a.Alg != "RS256" || a.Use != "sig" {
+		t.Fatalf("key A = %+v", a)
+	}
+
+	n, err := base64.RawURLEncoding.DecodeString(a.N)
+	if err != nil || new(big.Int).SetBytes(n).Cmp(keyA.N) != 0 {
+		t.Fatalf("key A modulus does not match: %v", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(a.E)
+	if err != nil || new(big.Int).SetBytes(e).Int64() != int64(keyA.E) {
+		t.Fatalf("key A exponent does not match: %v", err)
+	}
+
+	if b.Kid != "B" || b.Kty != "OKP" || b.Crv != "Ed25519" || b.Alg != "EdDSA" {
+		t.Fatalf("key B = %+v", b)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(b.X)
+	if err != nil || !keyB.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		t.Fatalf("key B public key does not match: %v", err)
+	}
+
+	//the HS256 fallback has nothing to publish
+	loadTestKeys(t, testSecrets, "", "")
+	if result, err := JWKS(); err != nil || len(result.Keys) != 0 {
+		t.Fatalf("jwks = %+v, %v, want no keys", result, err)
+	}
+}
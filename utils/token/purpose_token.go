@@ -0,0 +1,82 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"online-food/dto"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// purpose tokens carry a single action (email verification, login challenge) and are never accepted as access tokens
+func GeneratePurposeToken(userId uint, email, purpose string, ttl time.Duration) (string, error) {
+	set, err := currentKeys()
+	if err != nil {
+		return "", err
+	}
+
+	jti, err := generateID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+
+	now := time.Now()
+
+	claim := &dto.PurposeTokenClaim{
+		UserID:  userId,
+		Email:   email,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    set.issuer,
+			Subject:   strconv.FormatUint(uint64(userId), 10),
+			Audience:  jwt.ClaimStrings{set.audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        jti,
+		},
+	}
+
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claim).SignedString(set.verifySecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return tokenStr, nil
+}
+
+func ClaimPurposeToken(tokenStr, purpose string) (*dto.PurposeTokenClaim, error) {
+	set, err := currentKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &dto.PurposeTokenClaim{}
+
+	token, err := jwt.ParseWithClaims(tokenStr, claim, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return set.verifySecret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(set.issuer),
+		jwt.WithAudience(set.audience),
+		jwt.WithExpirationRequired(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if claim.Purpose != purpose {
+		return nil, ErrTokenType
+	}
+
+	return claim, nil
+}